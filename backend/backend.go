@@ -16,8 +16,12 @@
 package backend
 
 import (
+	"context"
 	"crypto/sha256"
+	"errors"
+	"fmt"
 	"hash"
+	"time"
 
 	"github.com/consensys/gnark/constraint/solver"
 )
@@ -63,6 +67,10 @@ type ProverConfig struct {
 	ChallengeHash  hash.Hash
 	KZGFoldingHash hash.Hash
 	Accelerator    string
+	PKMemoryBudget uint64
+	Ctx            context.Context
+	ProgressHook   func(phase Phase, done, total int)
+	Stats          *ProverStats
 }
 
 // NewProverConfig returns a default ProverConfig with given prover options opts
@@ -73,6 +81,7 @@ func NewProverConfig(opts ...ProverOption) (ProverConfig, error) {
 		// separation tags for PLONK and Groth16
 		ChallengeHash:  sha256.New(),
 		KZGFoldingHash: sha256.New(),
+		Ctx:            context.Background(),
 	}
 	for _, option := range opts {
 		if err := option(&opt); err != nil {
@@ -136,6 +145,97 @@ func WithIcicleAcceleration() ProverOption {
 	}
 }
 
+// WithPKMemoryBudget bounds the memory, in bytes, used to hold the points of
+// the proving key when proving from a serialized key (see
+// groth16.ProveFromReader). The multi-exponentiations are then computed in
+// chunks of points. By default, each section of the proving key is read at
+// once.
+//
+// The budget doesn't account for the solution of the constraint system, which
+// is held in memory independently of it.
+func WithPKMemoryBudget(bytes uint64) ProverOption {
+	return func(pc *ProverConfig) error {
+		pc.PKMemoryBudget = bytes
+		return nil
+	}
+}
+
+// WithContext sets the context of the prover. The prover and the constraint
+// solver check it between their steps and return ctx.Err() when it is done.
+// ctx must not be nil.
+func WithContext(ctx context.Context) ProverOption {
+	return func(pc *ProverConfig) error {
+		if ctx == nil {
+			return errors.New("nil context")
+		}
+		pc.Ctx = ctx
+		return nil
+	}
+}
+
+// Phase is a phase of the proof generation, reported to the hook set with
+// WithProgressHook.
+type Phase uint8
+
+const (
+	PhaseSolve    Phase = iota // solving the constraint system, by level
+	PhaseFFT                   // FFTs of the polynomials
+	PhaseMSM                   // multi-scalar multiplications
+	PhaseQuotient              // computation of the quotient polynomial
+)
+
+// String returns the string representation of a prover phase
+func (p Phase) String() string {
+	switch p {
+	case PhaseSolve:
+		return "solve"
+	case PhaseFFT:
+		return "fft"
+	case PhaseMSM:
+		return "msm"
+	case PhaseQuotient:
+		return "quotient"
+	default:
+		return "unknown"
+	}
+}
+
+// ProverStats holds the timings of a proof generation. The duration of a phase
+// is the wall-clock time between its start and its last step; as the provers
+// run some phases concurrently, they may overlap.
+type ProverStats struct {
+	Solve    time.Duration
+	FFT      time.Duration
+	MSM      time.Duration
+	Quotient time.Duration
+	Total    time.Duration
+}
+
+// WithProgressHook sets a function called by the prover each time it
+// progresses in a phase, with the number of steps done out of the total
+// number of steps of the phase. The calls are serialized, but may come from
+// different goroutines; the hook should return quickly as it blocks the
+// prover.
+//
+// The steps are the levels of the constraint system for PhaseSolve, and the
+// individual FFTs, MSMs and quotient evaluations for the other phases.
+func WithProgressHook(hook func(phase Phase, done, total int)) ProverOption {
+	return func(pc *ProverConfig) error {
+		pc.ProgressHook = hook
+		return nil
+	}
+}
+
+// WithProverStats sets stats to be filled with the timings of the proof
+// generation once the prover returns the proof. groth16.ProveWithStats and
+// plonk.ProveWithStats return them alongside the proof instead.
+func WithProverStats(stats *ProverStats) ProverOption {
+	return func(pc *ProverConfig) error {
+		pc.Stats = stats
+		return nil
+	}
+}
+
 // VerifierOption defines option for altering the behavior of the verifier. See
 // the descriptions of functions returning instances of this type for
 // implemented options.
@@ -146,6 +246,7 @@ type VerifierConfig struct {
 	HashToFieldFn  hash.Hash
 	ChallengeHash  hash.Hash
 	KZGFoldingHash hash.Hash
+	Fingerprint    []byte
 }
 
 // NewVerifierConfig returns a default [VerifierConfig] with given verifier
@@ -197,3 +298,29 @@ func WithVerifierKZGFoldingHashFunction(hFunc hash.Hash) VerifierOption {
 		return nil
 	}
 }
+
+// WithVerifierFingerprint makes the verifier reject the verifying keys not
+// generated for the constraint system with the given fingerprint (see
+// constraint.Fingerprint), including the keys without fingerprint.
+func WithVerifierFingerprint(fingerprint []byte) VerifierOption {
+	return func(pc *VerifierConfig) error {
+		pc.Fingerprint = fingerprint
+		return nil
+	}
+}
+
+// BatchVerifyError is returned by the BatchVerify functions when the batch is
+// rejected. Index is the position of the first invalid proof in the batch and
+// Err the error returned when verifying it on its own.
+type BatchVerifyError struct {
+	Index int
+	Err   error
+}
+
+func (e *BatchVerifyError) Error() string {
+	return fmt.Sprintf("proof %d: %v", e.Index, e.Err)
+}
+
+func (e *BatchVerifyError) Unwrap() error {
+	return e.Err
+}
@@ -0,0 +1,372 @@
+// Copyright 2020 ConsenSys AG
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package groth16
+
+import (
+	"errors"
+
+	"github.com/consensys/gnark-crypto/ecc"
+	fr_bls12377 "github.com/consensys/gnark-crypto/ecc/bls12-377/fr"
+	fr_bls12381 "github.com/consensys/gnark-crypto/ecc/bls12-381/fr"
+	fr_bls24315 "github.com/consensys/gnark-crypto/ecc/bls24-315/fr"
+	fr_bls24317 "github.com/consensys/gnark-crypto/ecc/bls24-317/fr"
+	fr_bn254 "github.com/consensys/gnark-crypto/ecc/bn254/fr"
+	fr_bw6633 "github.com/consensys/gnark-crypto/ecc/bw6-633/fr"
+	fr_bw6761 "github.com/consensys/gnark-crypto/ecc/bw6-761/fr"
+	"github.com/consensys/gnark/backend"
+	groth16_bls12377 "github.com/consensys/gnark/backend/groth16/bls12-377"
+	groth16_bls12381 "github.com/consensys/gnark/backend/groth16/bls12-381"
+	groth16_bls24315 "github.com/consensys/gnark/backend/groth16/bls24-315"
+	groth16_bls24317 "github.com/consensys/gnark/backend/groth16/bls24-317"
+	groth16_bn254 "github.com/consensys/gnark/backend/groth16/bn254"
+	groth16_bw6633 "github.com/consensys/gnark/backend/groth16/bw6-633"
+	groth16_bw6761 "github.com/consensys/gnark/backend/groth16/bw6-761"
+	"github.com/consensys/gnark/backend/witness"
+	gnarkio "github.com/consensys/gnark/io"
+)
+
+var (
+	errProofCurveMismatch = errors.New("proofs and aggregation key are not on the same curve")
+	errKeyCurveMismatch   = errors.New("keys are not on the same curve")
+)
+
+// AggregationProvingKey is the structured reference string used to aggregate
+// Groth16 proofs with groth16.Aggregate
+//
+// it's underlying implementation is strongly typed with the curve (see gnark/internal/backend)
+type AggregationProvingKey interface {
+	groth16Object
+	gnarkio.UnsafeReaderFrom
+
+	// NbMaxProofs returns the maximum number of proofs the key can aggregate
+	NbMaxProofs() int
+}
+
+// AggregationVerifyingKey is used to verify proofs generated by groth16.Aggregate
+//
+// it's underlying implementation is strongly typed with the curve (see gnark/internal/backend)
+type AggregationVerifyingKey interface {
+	groth16Object
+	gnarkio.UnsafeReaderFrom
+}
+
+// AggregatedProof represents a proof generated by groth16.Aggregate
+//
+// it's underlying implementation is curve specific (see gnark/internal/backend)
+type AggregatedProof interface {
+	groth16Object
+}
+
+// SetupAggregation outputs the keys needed to aggregate up to nbMaxProofs Groth16
+// proofs on the given curve, using the SnarkPack inner pairing product argument
+// (https://eprint.iacr.org/2021/529.pdf).
+//
+// The keys do not depend on the circuit and can be reused to aggregate proofs
+// from any Groth16 VerifyingKey on the same curve. As for Setup, the randomness
+// used to compute them must not leak.
+func SetupAggregation(curveID ecc.ID, nbMaxProofs int) (AggregationProvingKey, AggregationVerifyingKey, error) {
+	pk, vk := NewAggregationProvingKey(curveID), NewAggregationVerifyingKey(curveID)
+	var err error
+	switch _pk := pk.(type) {
+	case *groth16_bls12377.AggregationProvingKey:
+		err = groth16_bls12377.SetupAggregation(nbMaxProofs, _pk, vk.(*groth16_bls12377.AggregationVerifyingKey))
+	case *groth16_bls12381.AggregationProvingKey:
+		err = groth16_bls12381.SetupAggregation(nbMaxProofs, _pk, vk.(*groth16_bls12381.AggregationVerifyingKey))
+	case *groth16_bn254.AggregationProvingKey:
+		err = groth16_bn254.SetupAggregation(nbMaxProofs, _pk, vk.(*groth16_bn254.AggregationVerifyingKey))
+	case *groth16_bw6761.AggregationProvingKey:
+		err = groth16_bw6761.SetupAggregation(nbMaxProofs, _pk, vk.(*groth16_bw6761.AggregationVerifyingKey))
+	case *groth16_bls24317.AggregationProvingKey:
+		err = groth16_bls24317.SetupAggregation(nbMaxProofs, _pk, vk.(*groth16_bls24317.AggregationVerifyingKey))
+	case *groth16_bls24315.AggregationProvingKey:
+		err = groth16_bls24315.SetupAggregation(nbMaxProofs, _pk, vk.(*groth16_bls24315.AggregationVerifyingKey))
+	case *groth16_bw6633.AggregationProvingKey:
+		err = groth16_bw6633.SetupAggregation(nbMaxProofs, _pk, vk.(*groth16_bw6633.AggregationVerifyingKey))
+	}
+	if err != nil {
+		return nil, nil, err
+	}
+	return pk, vk, nil
+}
+
+// Aggregate produces a single proof, logarithmic in size, attesting that all
+// the given proofs are valid for the corresponding public witnesses.
+//
+// All proofs must have been generated for vk, on the curve of the aggregation
+// key. Proofs with commitments (see frontend.Committer) are not supported.
+func Aggregate(pk AggregationProvingKey, vk VerifyingKey, proofs []Proof, publicWitnesses []witness.Witness, opts ...backend.ProverOption) (AggregatedProof, error) {
+	switch _pk := pk.(type) {
+	case *groth16_bls12377.AggregationProvingKey:
+		p, w, err := toCurveTyped[groth16_bls12377.Proof, fr_bls12377.Vector](proofs, publicWitnesses)
+		if err != nil {
+			return nil, err
+		}
+		_vk, ok := vk.(*groth16_bls12377.VerifyingKey)
+		if !ok {
+			return nil, errKeyCurveMismatch
+		}
+		return groth16_bls12377.Aggregate(_pk, _vk, p, w, opts...)
+	case *groth16_bls12381.AggregationProvingKey:
+		p, w, err := toCurveTyped[groth16_bls12381.Proof, fr_bls12381.Vector](proofs, publicWitnesses)
+		if err != nil {
+			return nil, err
+		}
+		_vk, ok := vk.(*groth16_bls12381.VerifyingKey)
+		if !ok {
+			return nil, errKeyCurveMismatch
+		}
+		return groth16_bls12381.Aggregate(_pk, _vk, p, w, opts...)
+	case *groth16_bn254.AggregationProvingKey:
+		p, w, err := toCurveTyped[groth16_bn254.Proof, fr_bn254.Vector](proofs, publicWitnesses)
+		if err != nil {
+			return nil, err
+		}
+		_vk, ok := vk.(*groth16_bn254.VerifyingKey)
+		if !ok {
+			return nil, errKeyCurveMismatch
+		}
+		return groth16_bn254.Aggregate(_pk, _vk, p, w, opts...)
+	case *groth16_bw6761.AggregationProvingKey:
+		p, w, err := toCurveTyped[groth16_bw6761.Proof, fr_bw6761.Vector](proofs, publicWitnesses)
+		if err != nil {
+			return nil, err
+		}
+		_vk, ok := vk.(*groth16_bw6761.VerifyingKey)
+		if !ok {
+			return nil, errKeyCurveMismatch
+		}
+		return groth16_bw6761.Aggregate(_pk, _vk, p, w, opts...)
+	case *groth16_bls24317.AggregationProvingKey:
+		p, w, err := toCurveTyped[groth16_bls24317.Proof, fr_bls24317.Vector](proofs, publicWitnesses)
+		if err != nil {
+			return nil, err
+		}
+		_vk, ok := vk.(*groth16_bls24317.VerifyingKey)
+		if !ok {
+			return nil, errKeyCurveMismatch
+		}
+		return groth16_bls24317.Aggregate(_pk, _vk, p, w, opts...)
+	case *groth16_bls24315.AggregationProvingKey:
+		p, w, err := toCurveTyped[groth16_bls24315.Proof, fr_bls24315.Vector](proofs, publicWitnesses)
+		if err != nil {
+			return nil, err
+		}
+		_vk, ok := vk.(*groth16_bls24315.VerifyingKey)
+		if !ok {
+			return nil, errKeyCurveMismatch
+		}
+		return groth16_bls24315.Aggregate(_pk, _vk, p, w, opts...)
+	case *groth16_bw6633.AggregationProvingKey:
+		p, w, err := toCurveTyped[groth16_bw6633.Proof, fr_bw6633.Vector](proofs, publicWitnesses)
+		if err != nil {
+			return nil, err
+		}
+		_vk, ok := vk.(*groth16_bw6633.VerifyingKey)
+		if !ok {
+			return nil, errKeyCurveMismatch
+		}
+		return groth16_bw6633.Aggregate(_pk, _vk, p, w, opts...)
+	default:
+		panic("unrecognized aggregation proving key curve type")
+	}
+}
+
+// VerifyAggregate verifies that proof aggregates valid Groth16 proofs for the
+// given VerifyingKey and public witnesses (in the order they were aggregated).
+func VerifyAggregate(vk VerifyingKey, avk AggregationVerifyingKey, proof AggregatedProof, publicWitnesses []witness.Witness, opts ...backend.VerifierOption) error {
+	switch _proof := proof.(type) {
+	case *groth16_bls12377.AggregatedProof:
+		_vk, _avk, err := toCurveTypedKeys[groth16_bls12377.VerifyingKey, groth16_bls12377.AggregationVerifyingKey](vk, avk)
+		if err != nil {
+			return err
+		}
+		_, w, err := toCurveTyped[groth16_bls12377.Proof, fr_bls12377.Vector](nil, publicWitnesses)
+		if err != nil {
+			return err
+		}
+		return groth16_bls12377.VerifyAggregate(_vk, _avk, _proof, w, opts...)
+	case *groth16_bls12381.AggregatedProof:
+		_vk, _avk, err := toCurveTypedKeys[groth16_bls12381.VerifyingKey, groth16_bls12381.AggregationVerifyingKey](vk, avk)
+		if err != nil {
+			return err
+		}
+		_, w, err := toCurveTyped[groth16_bls12381.Proof, fr_bls12381.Vector](nil, publicWitnesses)
+		if err != nil {
+			return err
+		}
+		return groth16_bls12381.VerifyAggregate(_vk, _avk, _proof, w, opts...)
+	case *groth16_bn254.AggregatedProof:
+		_vk, _avk, err := toCurveTypedKeys[groth16_bn254.VerifyingKey, groth16_bn254.AggregationVerifyingKey](vk, avk)
+		if err != nil {
+			return err
+		}
+		_, w, err := toCurveTyped[groth16_bn254.Proof, fr_bn254.Vector](nil, publicWitnesses)
+		if err != nil {
+			return err
+		}
+		return groth16_bn254.VerifyAggregate(_vk, _avk, _proof, w, opts...)
+	case *groth16_bw6761.AggregatedProof:
+		_vk, _avk, err := toCurveTypedKeys[groth16_bw6761.VerifyingKey, groth16_bw6761.AggregationVerifyingKey](vk, avk)
+		if err != nil {
+			return err
+		}
+		_, w, err := toCurveTyped[groth16_bw6761.Proof, fr_bw6761.Vector](nil, publicWitnesses)
+		if err != nil {
+			return err
+		}
+		return groth16_bw6761.VerifyAggregate(_vk, _avk, _proof, w, opts...)
+	case *groth16_bls24317.AggregatedProof:
+		_vk, _avk, err := toCurveTypedKeys[groth16_bls24317.VerifyingKey, groth16_bls24317.AggregationVerifyingKey](vk, avk)
+		if err != nil {
+			return err
+		}
+		_, w, err := toCurveTyped[groth16_bls24317.Proof, fr_bls24317.Vector](nil, publicWitnesses)
+		if err != nil {
+			return err
+		}
+		return groth16_bls24317.VerifyAggregate(_vk, _avk, _proof, w, opts...)
+	case *groth16_bls24315.AggregatedProof:
+		_vk, _avk, err := toCurveTypedKeys[groth16_bls24315.VerifyingKey, groth16_bls24315.AggregationVerifyingKey](vk, avk)
+		if err != nil {
+			return err
+		}
+		_, w, err := toCurveTyped[groth16_bls24315.Proof, fr_bls24315.Vector](nil, publicWitnesses)
+		if err != nil {
+			return err
+		}
+		return groth16_bls24315.VerifyAggregate(_vk, _avk, _proof, w, opts...)
+	case *groth16_bw6633.AggregatedProof:
+		_vk, _avk, err := toCurveTypedKeys[groth16_bw6633.VerifyingKey, groth16_bw6633.AggregationVerifyingKey](vk, avk)
+		if err != nil {
+			return err
+		}
+		_, w, err := toCurveTyped[groth16_bw6633.Proof, fr_bw6633.Vector](nil, publicWitnesses)
+		if err != nil {
+			return err
+		}
+		return groth16_bw6633.VerifyAggregate(_vk, _avk, _proof, w, opts...)
+	default:
+		panic("unrecognized aggregated proof curve type")
+	}
+}
+
+// toCurveTyped asserts the proofs and public witnesses have the expected
+// concrete types.
+func toCurveTyped[P any, V any](proofs []Proof, publicWitnesses []witness.Witness) ([]*P, []V, error) {
+	p := make([]*P, len(proofs))
+	for i := range proofs {
+		var ok bool
+		if p[i], ok = any(proofs[i]).(*P); !ok {
+			return nil, nil, errProofCurveMismatch
+		}
+	}
+	w := make([]V, len(publicWitnesses))
+	for i := range publicWitnesses {
+		var ok bool
+		if w[i], ok = any(publicWitnesses[i].Vector()).(V); !ok {
+			return nil, nil, witness.ErrInvalidWitness
+		}
+	}
+	return p, w, nil
+}
+
+// toCurveTypedKeys asserts the verifying key and the aggregation verifying key
+// have the expected concrete types.
+func toCurveTypedKeys[VK any, AVK any](vk VerifyingKey, avk AggregationVerifyingKey) (*VK, *AVK, error) {
+	_vk, ok := any(vk).(*VK)
+	if !ok {
+		return nil, nil, errKeyCurveMismatch
+	}
+	_avk, ok := any(avk).(*AVK)
+	if !ok {
+		return nil, nil, errKeyCurveMismatch
+	}
+	return _vk, _avk, nil
+}
+
+// NewAggregationProvingKey instantiates a curve-typed AggregationProvingKey and returns an interface object
+// This function exists for serialization purposes
+func NewAggregationProvingKey(curveID ecc.ID) AggregationProvingKey {
+	var pk AggregationProvingKey
+	switch curveID {
+	case ecc.BN254:
+		pk = &groth16_bn254.AggregationProvingKey{}
+	case ecc.BLS12_377:
+		pk = &groth16_bls12377.AggregationProvingKey{}
+	case ecc.BLS12_381:
+		pk = &groth16_bls12381.AggregationProvingKey{}
+	case ecc.BW6_761:
+		pk = &groth16_bw6761.AggregationProvingKey{}
+	case ecc.BLS24_317:
+		pk = &groth16_bls24317.AggregationProvingKey{}
+	case ecc.BLS24_315:
+		pk = &groth16_bls24315.AggregationProvingKey{}
+	case ecc.BW6_633:
+		pk = &groth16_bw6633.AggregationProvingKey{}
+	default:
+		panic("not implemented")
+	}
+	return pk
+}
+
+// NewAggregationVerifyingKey instantiates a curve-typed AggregationVerifyingKey and returns an interface
+// This function exists for serialization purposes
+func NewAggregationVerifyingKey(curveID ecc.ID) AggregationVerifyingKey {
+	var vk AggregationVerifyingKey
+	switch curveID {
+	case ecc.BN254:
+		vk = &groth16_bn254.AggregationVerifyingKey{}
+	case ecc.BLS12_377:
+		vk = &groth16_bls12377.AggregationVerifyingKey{}
+	case ecc.BLS12_381:
+		vk = &groth16_bls12381.AggregationVerifyingKey{}
+	case ecc.BW6_761:
+		vk = &groth16_bw6761.AggregationVerifyingKey{}
+	case ecc.BLS24_317:
+		vk = &groth16_bls24317.AggregationVerifyingKey{}
+	case ecc.BLS24_315:
+		vk = &groth16_bls24315.AggregationVerifyingKey{}
+	case ecc.BW6_633:
+		vk = &groth16_bw6633.AggregationVerifyingKey{}
+	default:
+		panic("not implemented")
+	}
+	return vk
+}
+
+// NewAggregatedProof instantiates a curve-typed AggregatedProof and returns an interface
+// This function exists for serialization purposes
+func NewAggregatedProof(curveID ecc.ID) AggregatedProof {
+	var proof AggregatedProof
+	switch curveID {
+	case ecc.BN254:
+		proof = &groth16_bn254.AggregatedProof{}
+	case ecc.BLS12_377:
+		proof = &groth16_bls12377.AggregatedProof{}
+	case ecc.BLS12_381:
+		proof = &groth16_bls12381.AggregatedProof{}
+	case ecc.BW6_761:
+		proof = &groth16_bw6761.AggregatedProof{}
+	case ecc.BLS24_317:
+		proof = &groth16_bls24317.AggregatedProof{}
+	case ecc.BLS24_315:
+		proof = &groth16_bls24315.AggregatedProof{}
+	case ecc.BW6_633:
+		proof = &groth16_bw6633.AggregatedProof{}
+	default:
+		panic("not implemented")
+	}
+	return proof
+}
@@ -0,0 +1,194 @@
+package groth16_test
+
+import (
+	"bytes"
+	"testing"
+
+	"github.com/consensys/gnark-crypto/ecc"
+	"github.com/consensys/gnark/backend/groth16"
+	groth16_bn254 "github.com/consensys/gnark/backend/groth16/bn254"
+	"github.com/consensys/gnark/backend/witness"
+	"github.com/consensys/gnark/frontend"
+	"github.com/consensys/gnark/frontend/cs/r1cs"
+	"github.com/consensys/gnark/io"
+	"github.com/consensys/gnark/test"
+)
+
+type squareCircuit struct {
+	X frontend.Variable
+	Y frontend.Variable `gnark:",public"`
+}
+
+func (c *squareCircuit) Define(api frontend.API) error {
+	api.AssertIsEqual(api.Mul(c.X, c.X), c.Y)
+	return nil
+}
+
+func TestAggregate(t *testing.T) {
+	assert := test.NewAssert(t)
+	for _, curve := range getCurves() {
+		assert.Run(func(assert *test.Assert) {
+			ccs, err := frontend.Compile(curve.ScalarField(), r1cs.NewBuilder, &squareCircuit{})
+			assert.NoError(err)
+			pk, vk, err := groth16.Setup(ccs)
+			assert.NoError(err)
+			apk, avk, err := groth16.SetupAggregation(curve, 5)
+			assert.NoError(err)
+			assert.Equal(8, apk.NbMaxProofs())
+
+			assert.NoError(io.RoundTripCheck(apk, func() any { return groth16.NewAggregationProvingKey(curve) }))
+			assert.NoError(io.RoundTripCheck(avk, func() any { return groth16.NewAggregationVerifyingKey(curve) }))
+
+			const nbProofs = 5
+			proofs := make([]groth16.Proof, nbProofs)
+			publicWitnesses := make([]witness.Witness, nbProofs)
+			for i := 0; i < nbProofs; i++ {
+				w, err := frontend.NewWitness(&squareCircuit{X: i + 2, Y: (i + 2) * (i + 2)}, curve.ScalarField())
+				assert.NoError(err)
+				proofs[i], err = groth16.Prove(ccs, pk, w)
+				assert.NoError(err)
+				publicWitnesses[i], err = w.Public()
+				assert.NoError(err)
+			}
+
+			for _, n := range []int{1, 2, nbProofs} {
+				proof, err := groth16.Aggregate(apk, vk, proofs[:n], publicWitnesses[:n])
+				assert.NoError(err)
+				assert.NoError(groth16.VerifyAggregate(vk, avk, proof, publicWitnesses[:n]))
+				assert.NoError(io.RoundTripCheck(proof, func() any { return groth16.NewAggregatedProof(curve) }))
+			}
+
+			proof, err := groth16.Aggregate(apk, vk, proofs, publicWitnesses)
+			assert.NoError(err)
+
+			// swapped public witnesses
+			swapped := append([]witness.Witness{}, publicWitnesses...)
+			swapped[0], swapped[1] = swapped[1], swapped[0]
+			assert.Error(groth16.VerifyAggregate(vk, avk, proof, swapped))
+
+			// missing public witness
+			assert.Error(groth16.VerifyAggregate(vk, avk, proof, publicWitnesses[:nbProofs-1]))
+
+			// key too small
+			small, _, err := groth16.SetupAggregation(curve, 2)
+			assert.NoError(err)
+			_, err = groth16.Aggregate(small, vk, proofs, publicWitnesses)
+			assert.Error(err)
+		}, curve.String())
+	}
+}
+
+type committedSquareCircuit struct {
+	squareCircuit
+}
+
+func (c *committedSquareCircuit) Define(api frontend.API) error {
+	cmt, err := api.(frontend.Committer).Commit(c.X)
+	if err != nil {
+		return err
+	}
+	api.AssertIsDifferent(cmt, 0)
+	return c.squareCircuit.Define(api)
+}
+
+func TestAggregateCommitment(t *testing.T) {
+	assert := test.NewAssert(t)
+	ccs, err := frontend.Compile(ecc.BN254.ScalarField(), r1cs.NewBuilder, &committedSquareCircuit{})
+	assert.NoError(err)
+	pk, vk, err := groth16.Setup(ccs)
+	assert.NoError(err)
+	w, err := frontend.NewWitness(&committedSquareCircuit{squareCircuit{X: 3, Y: 9}}, ecc.BN254.ScalarField())
+	assert.NoError(err)
+	proof, err := groth16.Prove(ccs, pk, w)
+	assert.NoError(err)
+	publicWitness, err := w.Public()
+	assert.NoError(err)
+
+	apk, _, err := groth16.SetupAggregation(ecc.BN254, 2)
+	assert.NoError(err)
+	_, err = groth16.Aggregate(apk, vk, []groth16.Proof{proof}, []witness.Witness{publicWitness})
+	assert.Error(err)
+}
+
+func TestAggregateTampered(t *testing.T) {
+	assert := test.NewAssert(t)
+	ccs, err := frontend.Compile(ecc.BN254.ScalarField(), r1cs.NewBuilder, &squareCircuit{})
+	assert.NoError(err)
+	pk, vk, err := groth16.Setup(ccs)
+	assert.NoError(err)
+	apk, avk, err := groth16.SetupAggregation(ecc.BN254, 4)
+	assert.NoError(err)
+
+	const nbProofs = 4
+	proofs := make([]groth16.Proof, nbProofs)
+	publicWitnesses := make([]witness.Witness, nbProofs)
+	for i := 0; i < nbProofs; i++ {
+		w, err := frontend.NewWitness(&squareCircuit{X: i + 2, Y: (i + 2) * (i + 2)}, ecc.BN254.ScalarField())
+		assert.NoError(err)
+		proofs[i], err = groth16.Prove(ccs, pk, w)
+		assert.NoError(err)
+		publicWitnesses[i], err = w.Public()
+		assert.NoError(err)
+	}
+	proof, err := groth16.Aggregate(apk, vk, proofs, publicWitnesses)
+	assert.NoError(err)
+	assert.NoError(groth16.VerifyAggregate(vk, avk, proof, publicWitnesses))
+
+	for name, tamper := range map[string]func(p *groth16_bn254.AggregatedProof){
+		"IPAB": func(p *groth16_bn254.AggregatedProof) { p.IPAB.Square(&p.IPAB) },
+		"AggC": func(p *groth16_bn254.AggregatedProof) { p.AggC.Double(&p.AggC) },
+		"IPABL": func(p *groth16_bn254.AggregatedProof) {
+			p.IPABL[0].Square(&p.IPABL[0])
+		},
+		"AggCR": func(p *groth16_bn254.AggregatedProof) {
+			p.AggCR[0].Double(&p.AggCR[0])
+		},
+		"FinalC": func(p *groth16_bn254.AggregatedProof) { p.FinalC.Double(&p.FinalC) },
+		"ComCL": func(p *groth16_bn254.AggregatedProof) {
+			// not in the r-torsion of GT
+			_, err := p.ComCL[0][0].SetRandom()
+			assert.NoError(err)
+		},
+	} {
+		assert.Run(func(assert *test.Assert) {
+			var buf bytes.Buffer
+			_, err := proof.WriteTo(&buf)
+			assert.NoError(err)
+			tampered := groth16.NewAggregatedProof(ecc.BN254)
+			_, err = tampered.ReadFrom(&buf)
+			assert.NoError(err)
+			tamper(tampered.(*groth16_bn254.AggregatedProof))
+			assert.Error(groth16.VerifyAggregate(vk, avk, tampered, publicWitnesses))
+		}, name)
+	}
+
+	// target group elements outside of the subgroup must be rejected when decoding
+	var buf bytes.Buffer
+	outside := *proof.(*groth16_bn254.AggregatedProof)
+	_, err = outside.IPAB.SetRandom()
+	assert.NoError(err)
+	_, err = outside.WriteTo(&buf)
+	assert.NoError(err)
+	_, err = groth16.NewAggregatedProof(ecc.BN254).ReadFrom(&buf)
+	assert.Error(err)
+
+	// a single invalid proof invalidates the aggregation
+	invalid := append([]groth16.Proof{}, proofs...)
+	forged := *proofs[2].(*groth16_bn254.Proof)
+	forged.Krs.Double(&forged.Krs)
+	invalid[2] = &forged
+	assert.Error(groth16.Verify(invalid[2], vk, publicWitnesses[2]))
+	proof, err = groth16.Aggregate(apk, vk, invalid, publicWitnesses)
+	assert.NoError(err)
+	assert.Error(groth16.VerifyAggregate(vk, avk, proof, publicWitnesses))
+
+	// keys on another curve must be rejected without panicking
+	ccsOther, err := frontend.Compile(ecc.BLS12_381.ScalarField(), r1cs.NewBuilder, &squareCircuit{})
+	assert.NoError(err)
+	_, vkOther, err := groth16.Setup(ccsOther)
+	assert.NoError(err)
+	_, avkOther, err := groth16.SetupAggregation(ecc.BLS12_381, 4)
+	assert.NoError(err)
+	assert.Error(groth16.VerifyAggregate(vkOther, avk, proof, publicWitnesses))
+	assert.Error(groth16.VerifyAggregate(vk, avkOther, proof, publicWitnesses))
+}
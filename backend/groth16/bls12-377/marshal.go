@@ -19,7 +19,9 @@ package groth16
 import (
 	curve "github.com/consensys/gnark-crypto/ecc/bls12-377"
 
+	"errors"
 	"github.com/consensys/gnark-crypto/ecc/bls12-377/fr/pedersen"
+	"github.com/consensys/gnark/constraint"
 	"github.com/consensys/gnark/internal/utils"
 	"io"
 )
@@ -98,7 +100,11 @@ func (vk *VerifyingKey) WriteTo(w io.Writer) (n int64, err error) {
 		return n, err
 	}
 	var m int64
-	m, err = vk.CommitmentKey.WriteTo(w)
+	if m, err = vk.CommitmentKey.WriteTo(w); err != nil {
+		return m + n, err
+	}
+	n += m
+	m, err = utils.WriteBytes(w, vk.Fingerprint)
 	return m + n, err
 }
 
@@ -110,7 +116,11 @@ func (vk *VerifyingKey) WriteRawTo(w io.Writer) (n int64, err error) {
 		return n, err
 	}
 	var m int64
-	m, err = vk.CommitmentKey.WriteRawTo(w)
+	if m, err = vk.CommitmentKey.WriteRawTo(w); err != nil {
+		return m + n, err
+	}
+	n += m
+	m, err = utils.WriteBytes(w, vk.Fingerprint)
 	return m + n, err
 }
 
@@ -172,7 +182,11 @@ func (vk *VerifyingKey) ReadFrom(r io.Reader) (int64, error) {
 		return n, err
 	}
 	var m int64
-	m, err = vk.CommitmentKey.ReadFrom(r)
+	if m, err = vk.CommitmentKey.ReadFrom(r); err != nil {
+		return m + n, err
+	}
+	n += m
+	m, err = vk.readFingerprint(r)
 	return m + n, err
 }
 
@@ -184,10 +198,26 @@ func (vk *VerifyingKey) UnsafeReadFrom(r io.Reader) (int64, error) {
 		return n, err
 	}
 	var m int64
-	m, err = vk.CommitmentKey.UnsafeReadFrom(r)
+	if m, err = vk.CommitmentKey.UnsafeReadFrom(r); err != nil {
+		return m + n, err
+	}
+	n += m
+	m, err = vk.readFingerprint(r)
 	return m + n, err
 }
 
+// readFingerprint reads the constraint system fingerprint. Verifying keys
+// serialized without fingerprint (e.g. by bellman) end before it.
+func (vk *VerifyingKey) readFingerprint(r io.Reader) (int64, error) {
+	var err error
+	var n int64
+	vk.Fingerprint, n, err = utils.ReadBytes(r, constraint.FingerprintSize)
+	if err == io.EOF {
+		return n, nil
+	}
+	return n, err
+}
+
 func (vk *VerifyingKey) readFrom(r io.Reader, decOptions ...func(*curve.Decoder)) (int64, error) {
 	dec := curve.NewDecoder(r, decOptions...)
 
@@ -299,6 +329,12 @@ func (pk *ProvingKey) writeTo(w io.Writer, raw bool) (int64, error) {
 		}
 	}
 
+	n2, err := utils.WriteBytes(w, pk.Fingerprint)
+	n += n2
+	if err != nil {
+		return n + enc.BytesWritten(), err
+	}
+
 	return n + enc.BytesWritten(), nil
 
 }
@@ -370,5 +406,296 @@ func (pk *ProvingKey) readFrom(r io.Reader, decOptions ...func(*curve.Decoder))
 		}
 	}
 
+	// proving keys serialized before the fingerprint was added end here
+	fingerprint, n2, err := utils.ReadBytes(r, constraint.FingerprintSize)
+	n += n2
+	if err != nil && err != io.EOF {
+		return n + dec.BytesRead(), err
+	}
+	pk.Fingerprint = fingerprint
+
 	return n + dec.BytesRead(), nil
 }
+
+// WriteTo writes binary encoding of the key elements to writer
+// points are compressed
+// use WriteRawTo(...) to encode the key without point compression
+func (pk *AggregationProvingKey) WriteTo(w io.Writer) (n int64, err error) {
+	return pk.writeTo(w, false)
+}
+
+// WriteRawTo writes binary encoding of the key elements to writer
+// points are not compressed
+// use WriteTo(...) to encode the key with point compression
+func (pk *AggregationProvingKey) WriteRawTo(w io.Writer) (n int64, err error) {
+	return pk.writeTo(w, true)
+}
+
+func (pk *AggregationProvingKey) writeTo(w io.Writer, raw bool) (int64, error) {
+	var enc *curve.Encoder
+	if raw {
+		enc = curve.NewEncoder(w, curve.RawEncoding())
+	} else {
+		enc = curve.NewEncoder(w)
+	}
+
+	toEncode := []interface{}{
+		pk.G1.A,
+		pk.G1.B,
+		pk.G2.A,
+		pk.G2.B,
+	}
+
+	for _, v := range toEncode {
+		if err := enc.Encode(v); err != nil {
+			return enc.BytesWritten(), err
+		}
+	}
+
+	return enc.BytesWritten(), nil
+}
+
+// ReadFrom attempts to decode an AggregationProvingKey from reader
+// AggregationProvingKey must be encoded through WriteTo (compressed) or WriteRawTo (uncompressed)
+func (pk *AggregationProvingKey) ReadFrom(r io.Reader) (int64, error) {
+	return pk.readFrom(r)
+}
+
+// UnsafeReadFrom behaves like ReadFrom excepts it doesn't check if the decoded points are on the curve
+// or in the correct subgroup
+func (pk *AggregationProvingKey) UnsafeReadFrom(r io.Reader) (int64, error) {
+	return pk.readFrom(r, curve.NoSubgroupChecks())
+}
+
+func (pk *AggregationProvingKey) readFrom(r io.Reader, decOptions ...func(*curve.Decoder)) (int64, error) {
+	dec := curve.NewDecoder(r, decOptions...)
+
+	toDecode := []interface{}{
+		&pk.G1.A,
+		&pk.G1.B,
+		&pk.G2.A,
+		&pk.G2.B,
+	}
+
+	for _, v := range toDecode {
+		if err := dec.Decode(v); err != nil {
+			return dec.BytesRead(), err
+		}
+	}
+
+	if len(pk.G1.A) != 2*len(pk.G2.A) || len(pk.G1.B) != 2*len(pk.G2.A) || len(pk.G2.B) != len(pk.G2.A) {
+		return dec.BytesRead(), errors.New("invalid aggregation proving key: inconsistent sizes")
+	}
+
+	return dec.BytesRead(), nil
+}
+
+// WriteTo writes binary encoding of the key elements to writer
+// points are compressed
+// use WriteRawTo(...) to encode the key without point compression
+func (vk *AggregationVerifyingKey) WriteTo(w io.Writer) (n int64, err error) {
+	return vk.writeTo(w, false)
+}
+
+// WriteRawTo writes binary encoding of the key elements to writer
+// points are not compressed
+// use WriteTo(...) to encode the key with point compression
+func (vk *AggregationVerifyingKey) WriteRawTo(w io.Writer) (n int64, err error) {
+	return vk.writeTo(w, true)
+}
+
+func (vk *AggregationVerifyingKey) writeTo(w io.Writer, raw bool) (int64, error) {
+	var enc *curve.Encoder
+	if raw {
+		enc = curve.NewEncoder(w, curve.RawEncoding())
+	} else {
+		enc = curve.NewEncoder(w)
+	}
+
+	toEncode := []interface{}{
+		&vk.G1.G,
+		&vk.G1.A,
+		&vk.G1.B,
+		&vk.G2.H,
+		&vk.G2.A,
+		&vk.G2.B,
+	}
+
+	for _, v := range toEncode {
+		if err := enc.Encode(v); err != nil {
+			return enc.BytesWritten(), err
+		}
+	}
+
+	return enc.BytesWritten(), nil
+}
+
+// ReadFrom attempts to decode an AggregationVerifyingKey from reader
+// AggregationVerifyingKey must be encoded through WriteTo (compressed) or WriteRawTo (uncompressed)
+func (vk *AggregationVerifyingKey) ReadFrom(r io.Reader) (int64, error) {
+	return vk.readFrom(r)
+}
+
+// UnsafeReadFrom behaves like ReadFrom excepts it doesn't check if the decoded points are on the curve
+// or in the correct subgroup
+func (vk *AggregationVerifyingKey) UnsafeReadFrom(r io.Reader) (int64, error) {
+	return vk.readFrom(r, curve.NoSubgroupChecks())
+}
+
+func (vk *AggregationVerifyingKey) readFrom(r io.Reader, decOptions ...func(*curve.Decoder)) (int64, error) {
+	dec := curve.NewDecoder(r, decOptions...)
+
+	toDecode := []interface{}{
+		&vk.G1.G,
+		&vk.G1.A,
+		&vk.G1.B,
+		&vk.G2.H,
+		&vk.G2.A,
+		&vk.G2.B,
+	}
+
+	for _, v := range toDecode {
+		if err := dec.Decode(v); err != nil {
+			return dec.BytesRead(), err
+		}
+	}
+
+	return dec.BytesRead(), nil
+}
+
+// WriteTo writes binary encoding of the AggregatedProof elements to writer
+// points are stored in compressed form, followed by the target group elements
+// use WriteRawTo(...) to encode the proof without point compression
+func (proof *AggregatedProof) WriteTo(w io.Writer) (n int64, err error) {
+	return proof.writeTo(w, false)
+}
+
+// WriteRawTo writes binary encoding of the AggregatedProof elements to writer
+// points are stored in uncompressed form, followed by the target group elements
+// use WriteTo(...) to encode the proof with point compression
+func (proof *AggregatedProof) WriteRawTo(w io.Writer) (n int64, err error) {
+	return proof.writeTo(w, true)
+}
+
+func (proof *AggregatedProof) writeTo(w io.Writer, raw bool) (int64, error) {
+	if len(proof.AggCR) != len(proof.AggCL) || len(proof.ComABL) != len(proof.AggCL) ||
+		len(proof.ComABR) != len(proof.AggCL) || len(proof.ComCL) != len(proof.AggCL) ||
+		len(proof.ComCR) != len(proof.AggCL) || len(proof.IPABL) != len(proof.AggCL) ||
+		len(proof.IPABR) != len(proof.AggCL) {
+		return 0, errors.New("invalid aggregated proof: inconsistent number of rounds")
+	}
+
+	var enc *curve.Encoder
+	if raw {
+		enc = curve.NewEncoder(w, curve.RawEncoding())
+	} else {
+		enc = curve.NewEncoder(w)
+	}
+
+	toEncode := []interface{}{
+		&proof.AggC,
+		proof.AggCL,
+		proof.AggCR,
+		&proof.FinalA,
+		&proof.FinalC,
+		&proof.FinalB,
+		&proof.FinalV1,
+		&proof.FinalV2,
+		&proof.FinalW1,
+		&proof.FinalW2,
+		&proof.OpeningV1,
+		&proof.OpeningV2,
+		&proof.OpeningW1,
+		&proof.OpeningW2,
+	}
+
+	for _, v := range toEncode {
+		if err := enc.Encode(v); err != nil {
+			return enc.BytesWritten(), err
+		}
+	}
+
+	// target group elements are not supported by the encoder, we write them raw
+	n := enc.BytesWritten()
+	for _, e := range proof.targetGroupElements() {
+		b := e.Bytes()
+		written, err := w.Write(b[:])
+		n += int64(written)
+		if err != nil {
+			return n, err
+		}
+	}
+
+	return n, nil
+}
+
+// ReadFrom attempts to decode an AggregatedProof from reader
+// AggregatedProof must be encoded through WriteTo (compressed) or WriteRawTo (uncompressed)
+func (proof *AggregatedProof) ReadFrom(r io.Reader) (n int64, err error) {
+	dec := curve.NewDecoder(r)
+
+	toDecode := []interface{}{
+		&proof.AggC,
+		&proof.AggCL,
+		&proof.AggCR,
+		&proof.FinalA,
+		&proof.FinalC,
+		&proof.FinalB,
+		&proof.FinalV1,
+		&proof.FinalV2,
+		&proof.FinalW1,
+		&proof.FinalW2,
+		&proof.OpeningV1,
+		&proof.OpeningV2,
+		&proof.OpeningW1,
+		&proof.OpeningW2,
+	}
+
+	for _, v := range toDecode {
+		if err := dec.Decode(v); err != nil {
+			return dec.BytesRead(), err
+		}
+	}
+
+	nbRounds := len(proof.AggCL)
+	if len(proof.AggCR) != nbRounds {
+		return dec.BytesRead(), errors.New("invalid aggregated proof: inconsistent number of rounds")
+	}
+	proof.ComABL = make([][2]curve.GT, nbRounds)
+	proof.ComABR = make([][2]curve.GT, nbRounds)
+	proof.ComCL = make([][2]curve.GT, nbRounds)
+	proof.ComCR = make([][2]curve.GT, nbRounds)
+	proof.IPABL = make([]curve.GT, nbRounds)
+	proof.IPABR = make([]curve.GT, nbRounds)
+
+	n = dec.BytesRead()
+	var buf [curve.SizeOfGT]byte
+	for _, e := range proof.targetGroupElements() {
+		read, err := io.ReadFull(r, buf[:])
+		n += int64(read)
+		if err != nil {
+			return n, err
+		}
+		if err := e.SetBytes(buf[:]); err != nil {
+			return n, err
+		}
+		if !e.IsInSubGroup() {
+			return n, errCorrectSubgroupCheckFailed
+		}
+	}
+
+	return n, nil
+}
+
+// targetGroupElements returns pointers to the GT elements of the proof, in
+// serialization order
+func (proof *AggregatedProof) targetGroupElements() []*curve.GT {
+	res := []*curve.GT{&proof.ComAB[0], &proof.ComAB[1], &proof.ComC[0], &proof.ComC[1], &proof.IPAB}
+	for j := range proof.AggCL {
+		res = append(res,
+			&proof.ComABL[j][0], &proof.ComABL[j][1], &proof.ComABR[j][0], &proof.ComABR[j][1],
+			&proof.ComCL[j][0], &proof.ComCL[j][1], &proof.ComCR[j][0], &proof.ComCR[j][1],
+			&proof.IPABL[j], &proof.IPABR[j])
+	}
+	return res
+}
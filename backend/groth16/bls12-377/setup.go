@@ -55,6 +55,10 @@ type ProvingKey struct {
 	NbInfinityA, NbInfinityB uint64
 
 	CommitmentKeys []pedersen.ProvingKey
+
+	// Fingerprint of the constraint system, see constraint.Fingerprint. If set,
+	// Prove rejects the constraint systems with a different fingerprint.
+	Fingerprint []byte
 }
 
 // VerifyingKey is used by a Groth16 verifier to verify the validity of a proof and a statement
@@ -79,6 +83,10 @@ type VerifyingKey struct {
 
 	CommitmentKey                pedersen.VerifyingKey
 	PublicAndCommitmentCommitted [][]int // indexes of public/commitment committed variables
+
+	// Fingerprint of the constraint system, see constraint.Fingerprint. It is
+	// checked by Verify when backend.WithVerifierFingerprint is set.
+	Fingerprint []byte
 }
 
 // Setup constructs the SRS
@@ -96,6 +104,12 @@ func Setup(r1cs *cs.R1CS, pk *ProvingKey, vk *VerifyingKey) error {
 	// get R1CS nb constraints, wires and public/private inputs
 	nbWires := r1cs.NbInternalVariables + r1cs.GetNbPublicVariables() + r1cs.GetNbSecretVariables()
 
+	fingerprint, err := constraint.Fingerprint(r1cs)
+	if err != nil {
+		return err
+	}
+	pk.Fingerprint, vk.Fingerprint = fingerprint, fingerprint
+
 	commitmentInfo := r1cs.CommitmentInfo.(constraint.Groth16Commitments)
 	commitmentWires := commitmentInfo.CommitmentIndexes()
 	privateCommitted := commitmentInfo.GetPrivateCommitted()
@@ -484,6 +498,12 @@ func DummySetup(r1cs *cs.R1CS, pk *ProvingKey) error {
 	nbWires := r1cs.NbInternalVariables + r1cs.GetNbPublicVariables() + r1cs.GetNbSecretVariables()
 	nbConstraints := r1cs.GetNbConstraints()
 	commitmentInfo := r1cs.CommitmentInfo.(constraint.Groth16Commitments)
+
+	fingerprint, err := constraint.Fingerprint(r1cs)
+	if err != nil {
+		return err
+	}
+	pk.Fingerprint = fingerprint
 	privateCommitted := commitmentInfo.GetPrivateCommitted()
 	nbPrivateWires := r1cs.GetNbSecretVariables() + r1cs.NbInternalVariables - internal.NbElements(privateCommitted) - len(commitmentInfo)
 
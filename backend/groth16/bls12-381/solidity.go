@@ -0,0 +1,403 @@
+package groth16
+
+import (
+	"encoding/hex"
+
+	curve "github.com/consensys/gnark-crypto/ecc/bls12-381"
+	"github.com/consensys/gnark-crypto/ecc/bls12-381/fp"
+)
+
+// fpSolidityBytes is the size of a base field element in the EIP-2537
+// encoding: 16 zero bytes of padding followed by the 48 big-endian bytes.
+const fpSolidityBytes = 64
+
+// fpHi returns the 16 most significant bytes of x as a hex string, as
+// stored in the first 32-byte word of the EIP-2537 encoding.
+func fpHi(x fp.Element) string {
+	b := x.Bytes()
+	return "0x" + hex.EncodeToString(b[:16])
+}
+
+// fpLo returns the 32 least significant bytes of x as a hex string, as
+// stored in the second 32-byte word of the EIP-2537 encoding.
+func fpLo(x fp.Element) string {
+	b := x.Bytes()
+	return "0x" + hex.EncodeToString(b[16:])
+}
+
+// MarshalSolidity returns the proof in the format expected by the solidity
+// verifier exported with ExportSolidity.
+//
+// Points are encoded following EIP-2537: each coordinate takes two 32-byte
+// words, and G2 coordinates are ordered (x₀, x₁, y₀, y₁). The layout is
+//
+//	Ar | Bs | Krs [| Commitments[0] | CommitmentPok]
+//
+// where the commitment part is present only if the proof has a commitment.
+func (proof *Proof) MarshalSolidity() []byte {
+	res := make([]byte, 0, 2*fpSolidityBytes*(2+4+2+2+2))
+	res = appendG1Solidity(res, &proof.Ar)
+	res = appendG2Solidity(res, &proof.Bs)
+	res = appendG1Solidity(res, &proof.Krs)
+	if len(proof.Commitments) > 0 {
+		res = appendG1Solidity(res, &proof.Commitments[0])
+		res = appendG1Solidity(res, &proof.CommitmentPok)
+	}
+	return res
+}
+
+func appendFpSolidity(dst []byte, x *fp.Element) []byte {
+	var padding [fpSolidityBytes - fp.Bytes]byte
+	b := x.Bytes()
+	dst = append(dst, padding[:]...)
+	return append(dst, b[:]...)
+}
+
+func appendG1Solidity(dst []byte, p *curve.G1Affine) []byte {
+	dst = appendFpSolidity(dst, &p.X)
+	return appendFpSolidity(dst, &p.Y)
+}
+
+func appendG2Solidity(dst []byte, p *curve.G2Affine) []byte {
+	dst = appendFpSolidity(dst, &p.X.A0)
+	dst = appendFpSolidity(dst, &p.X.A1)
+	dst = appendFpSolidity(dst, &p.Y.A0)
+	return appendFpSolidity(dst, &p.Y.A1)
+}
+
+// solidityTemplate
+// this is an experimental feature and gnark solidity generator as not been thoroughly tested
+const solidityTemplate = `
+{{- $numCommitments := len .PublicAndCommitmentCommitted }}
+{{- $numPublic := sub (sub (len .G1.K) $numCommitments) 1 }}
+{{- $numMSM := add $numPublic $numCommitments }}
+// SPDX-License-Identifier: MIT
+
+pragma solidity ^0.8.0;
+
+/// @title Groth16 verifier template for BLS12-381.
+/// @notice Supports verifying Groth16 proofs over BLS12-381 using the
+/// precompiles defined in EIP-2537.
+/// @notice Base field elements do not fit in a single word, they are
+/// represented as two words (hi, lo) following the EIP-2537 encoding,
+/// where hi holds the 16 most significant bytes.
+contract Verifier {
+
+    /// Some of the provided public input values are larger than the field modulus.
+    /// @dev Public input elements are not automatically reduced, as this is can be
+    /// a dangerous source of bugs.
+    error PublicInputNotInField();
+
+    /// The proof is invalid.
+    /// @dev This can mean that provided Groth16 proof points are not on their
+    /// curves, not in the correct subgroup, that pairing equation fails, or that
+    /// the proof is not for the provided public input.
+    error ProofInvalid();
+
+    {{- if gt $numCommitments 0 }}
+
+    /// The commitment proof of knowledge is invalid.
+    error CommitmentInvalid();
+    {{- end }}
+
+    // Addresses of precompiles (EIP-2537)
+    uint256 constant PRECOMPILE_G1ADD = 0x0b;
+    uint256 constant PRECOMPILE_G1MSM = 0x0c;
+    uint256 constant PRECOMPILE_PAIRING = 0x0f;
+
+    // Scalar field Fr order R.
+    uint256 constant R = 0x73eda753299d7d483339d80809a1d80553bda402fffe5bfeffffffff00000001;
+
+    // Groth16 alpha point in G1
+    uint256 constant ALPHA_X_HI = {{fpHi .G1.Alpha.X}};
+    uint256 constant ALPHA_X_LO = {{fpLo .G1.Alpha.X}};
+    uint256 constant ALPHA_Y_HI = {{fpHi .G1.Alpha.Y}};
+    uint256 constant ALPHA_Y_LO = {{fpLo .G1.Alpha.Y}};
+
+    // Groth16 beta point in G2 in powers of u
+    uint256 constant BETA_NEG_X_0_HI = {{fpHi .G2.Beta.X.A0}};
+    uint256 constant BETA_NEG_X_0_LO = {{fpLo .G2.Beta.X.A0}};
+    uint256 constant BETA_NEG_X_1_HI = {{fpHi .G2.Beta.X.A1}};
+    uint256 constant BETA_NEG_X_1_LO = {{fpLo .G2.Beta.X.A1}};
+    uint256 constant BETA_NEG_Y_0_HI = {{fpHi .G2.Beta.Y.A0}};
+    uint256 constant BETA_NEG_Y_0_LO = {{fpLo .G2.Beta.Y.A0}};
+    uint256 constant BETA_NEG_Y_1_HI = {{fpHi .G2.Beta.Y.A1}};
+    uint256 constant BETA_NEG_Y_1_LO = {{fpLo .G2.Beta.Y.A1}};
+
+    // Groth16 gamma point in G2 in powers of u
+    uint256 constant GAMMA_NEG_X_0_HI = {{fpHi .G2.Gamma.X.A0}};
+    uint256 constant GAMMA_NEG_X_0_LO = {{fpLo .G2.Gamma.X.A0}};
+    uint256 constant GAMMA_NEG_X_1_HI = {{fpHi .G2.Gamma.X.A1}};
+    uint256 constant GAMMA_NEG_X_1_LO = {{fpLo .G2.Gamma.X.A1}};
+    uint256 constant GAMMA_NEG_Y_0_HI = {{fpHi .G2.Gamma.Y.A0}};
+    uint256 constant GAMMA_NEG_Y_0_LO = {{fpLo .G2.Gamma.Y.A0}};
+    uint256 constant GAMMA_NEG_Y_1_HI = {{fpHi .G2.Gamma.Y.A1}};
+    uint256 constant GAMMA_NEG_Y_1_LO = {{fpLo .G2.Gamma.Y.A1}};
+
+    // Groth16 delta point in G2 in powers of u
+    uint256 constant DELTA_NEG_X_0_HI = {{fpHi .G2.Delta.X.A0}};
+    uint256 constant DELTA_NEG_X_0_LO = {{fpLo .G2.Delta.X.A0}};
+    uint256 constant DELTA_NEG_X_1_HI = {{fpHi .G2.Delta.X.A1}};
+    uint256 constant DELTA_NEG_X_1_LO = {{fpLo .G2.Delta.X.A1}};
+    uint256 constant DELTA_NEG_Y_0_HI = {{fpHi .G2.Delta.Y.A0}};
+    uint256 constant DELTA_NEG_Y_0_LO = {{fpLo .G2.Delta.Y.A0}};
+    uint256 constant DELTA_NEG_Y_1_HI = {{fpHi .G2.Delta.Y.A1}};
+    uint256 constant DELTA_NEG_Y_1_LO = {{fpLo .G2.Delta.Y.A1}};
+
+    {{- if gt $numCommitments 0 }}
+
+    // Pedersen G point in G2 in powers of u
+    uint256 constant PEDERSEN_G_X_0_HI = {{fpHi .CommitmentKey.G.X.A0}};
+    uint256 constant PEDERSEN_G_X_0_LO = {{fpLo .CommitmentKey.G.X.A0}};
+    uint256 constant PEDERSEN_G_X_1_HI = {{fpHi .CommitmentKey.G.X.A1}};
+    uint256 constant PEDERSEN_G_X_1_LO = {{fpLo .CommitmentKey.G.X.A1}};
+    uint256 constant PEDERSEN_G_Y_0_HI = {{fpHi .CommitmentKey.G.Y.A0}};
+    uint256 constant PEDERSEN_G_Y_0_LO = {{fpLo .CommitmentKey.G.Y.A0}};
+    uint256 constant PEDERSEN_G_Y_1_HI = {{fpHi .CommitmentKey.G.Y.A1}};
+    uint256 constant PEDERSEN_G_Y_1_LO = {{fpLo .CommitmentKey.G.Y.A1}};
+
+    // Pedersen GRootSigmaNeg point in G2 in powers of u
+    uint256 constant PEDERSEN_GROOTSIGMANEG_X_0_HI = {{fpHi .CommitmentKey.GRootSigmaNeg.X.A0}};
+    uint256 constant PEDERSEN_GROOTSIGMANEG_X_0_LO = {{fpLo .CommitmentKey.GRootSigmaNeg.X.A0}};
+    uint256 constant PEDERSEN_GROOTSIGMANEG_X_1_HI = {{fpHi .CommitmentKey.GRootSigmaNeg.X.A1}};
+    uint256 constant PEDERSEN_GROOTSIGMANEG_X_1_LO = {{fpLo .CommitmentKey.GRootSigmaNeg.X.A1}};
+    uint256 constant PEDERSEN_GROOTSIGMANEG_Y_0_HI = {{fpHi .CommitmentKey.GRootSigmaNeg.Y.A0}};
+    uint256 constant PEDERSEN_GROOTSIGMANEG_Y_0_LO = {{fpLo .CommitmentKey.GRootSigmaNeg.Y.A0}};
+    uint256 constant PEDERSEN_GROOTSIGMANEG_Y_1_HI = {{fpHi .CommitmentKey.GRootSigmaNeg.Y.A1}};
+    uint256 constant PEDERSEN_GROOTSIGMANEG_Y_1_LO = {{fpLo .CommitmentKey.GRootSigmaNeg.Y.A1}};
+    {{- end }}
+
+    // Constant and public input points
+    {{- $k0 := index .G1.K 0}}
+    uint256 constant CONSTANT_X_HI = {{fpHi $k0.X}};
+    uint256 constant CONSTANT_X_LO = {{fpLo $k0.X}};
+    uint256 constant CONSTANT_Y_HI = {{fpHi $k0.Y}};
+    uint256 constant CONSTANT_Y_LO = {{fpLo $k0.Y}};
+    {{- range $i, $ki := .G1.K }}
+        {{- if gt $i 0 }}
+    uint256 constant PUB_{{sub $i 1}}_X_HI = {{fpHi $ki.X}};
+    uint256 constant PUB_{{sub $i 1}}_X_LO = {{fpLo $ki.X}};
+    uint256 constant PUB_{{sub $i 1}}_Y_HI = {{fpHi $ki.Y}};
+    uint256 constant PUB_{{sub $i 1}}_Y_LO = {{fpLo $ki.Y}};
+        {{- end }}
+    {{- end }}
+
+    /// Compute the public input linear combination.
+    /// @notice Reverts with PublicInputNotInField if the input is not in the field.
+    /// @notice Computes the multi-scalar-multiplication of the public input
+    /// elements and the verification key including the constant term.
+    /// @param input The public inputs. These are elements of the scalar field Fr.
+    {{- if gt $numCommitments 0 }}
+    /// @param commitment The Pedersen commitment (x_hi, x_lo, y_hi, y_lo).
+    /// @param commitmentHash The commitment hashed with the committed public inputs.
+    {{- end }}
+    /// @return p The resulting G1 point (x_hi, x_lo, y_hi, y_lo).
+    function publicInputMSM(
+        uint256[{{$numPublic}}] calldata input
+        {{- if gt $numCommitments 0 }},
+        uint256[4] calldata commitment,
+        uint256 commitmentHash
+        {{- end }}
+    ) internal view returns (uint256[4] memory p) {
+        // Note: The G1MSM precompile does not reject unreduced scalars, so we check this.
+        // G1MSM has input (x, y, scalar)* and output (x', y').
+        // G1ADD has input (x1, y1, x2, y2) and output (x', y').
+        // We call them such that the G1MSM output is already in the second
+        // point argument of G1ADD.
+        bool success = true;
+        assembly ("memory-safe") {
+            let f := mload(0x40)
+            let g := add(f, 0x80)
+            let s
+            mstore(f, CONSTANT_X_HI)
+            mstore(add(f, 0x20), CONSTANT_X_LO)
+            mstore(add(f, 0x40), CONSTANT_Y_HI)
+            mstore(add(f, 0x60), CONSTANT_Y_LO)
+            {{- range $i := intRange $numPublic }}
+            mstore(add(g, {{mul $i 0xa0}}), PUB_{{$i}}_X_HI)
+            mstore(add(g, {{add (mul $i 0xa0) 0x20}}), PUB_{{$i}}_X_LO)
+            mstore(add(g, {{add (mul $i 0xa0) 0x40}}), PUB_{{$i}}_Y_HI)
+            mstore(add(g, {{add (mul $i 0xa0) 0x60}}), PUB_{{$i}}_Y_LO)
+            {{- if eq $i 0 }}
+            s := calldataload(input)
+            {{- else }}
+            s := calldataload(add(input, {{mul $i 0x20}}))
+            {{- end }}
+            mstore(add(g, {{add (mul $i 0xa0) 0x80}}), s)
+            success := and(success, lt(s, R))
+            {{- end }}
+            {{- if gt $numCommitments 0 }}
+            mstore(add(g, {{mul $numPublic 0xa0}}), PUB_{{$numPublic}}_X_HI)
+            mstore(add(g, {{add (mul $numPublic 0xa0) 0x20}}), PUB_{{$numPublic}}_X_LO)
+            mstore(add(g, {{add (mul $numPublic 0xa0) 0x40}}), PUB_{{$numPublic}}_Y_HI)
+            mstore(add(g, {{add (mul $numPublic 0xa0) 0x60}}), PUB_{{$numPublic}}_Y_LO)
+            mstore(add(g, {{add (mul $numPublic 0xa0) 0x80}}), commitmentHash)
+            {{- end }}
+            {{- if gt $numMSM 0 }}
+            success := and(success, staticcall(gas(), PRECOMPILE_G1MSM, g, {{mul $numMSM 0xa0}}, g, 0x80))
+            success := and(success, staticcall(gas(), PRECOMPILE_G1ADD, f, 0x100, f, 0x80))
+            {{- end }}
+            {{- if gt $numCommitments 0 }}
+            calldatacopy(g, commitment, 0x80)
+            success := and(success, staticcall(gas(), PRECOMPILE_G1ADD, f, 0x100, f, 0x80))
+            {{- end }}
+            mstore(p, mload(f))
+            mstore(add(p, 0x20), mload(add(f, 0x20)))
+            mstore(add(p, 0x40), mload(add(f, 0x40)))
+            mstore(add(p, 0x60), mload(add(f, 0x60)))
+        }
+        if (!success) {
+            // Either Public input not in field, or verification key invalid.
+            // We assume the contract is correctly generated, so the verification key is valid.
+            revert PublicInputNotInField();
+        }
+    }
+
+    {{- if gt $numCommitments 0 }}
+
+    /// Verify the proof of knowledge of the Pedersen commitment.
+    /// @notice Reverts with CommitmentInvalid if the proof of knowledge is invalid.
+    /// @param commitment The Pedersen commitment (x_hi, x_lo, y_hi, y_lo).
+    /// @param commitmentPok The proof of knowledge (x_hi, x_lo, y_hi, y_lo).
+    function verifyCommitment(
+        uint256[4] calldata commitment,
+        uint256[4] calldata commitmentPok
+    ) internal view {
+        // Check e(commitment, G) ⋅ e(commitmentPok, GRootSigmaNeg) = 1.
+        // Note: The pairing precompile checks that the points are on the curve
+        // and in the correct subgroup.
+        bool success;
+        assembly ("memory-safe") {
+            let f := mload(0x40)
+            calldatacopy(f, commitment, 0x80)
+            mstore(add(f, 0x80), PEDERSEN_G_X_0_HI)
+            mstore(add(f, 0xa0), PEDERSEN_G_X_0_LO)
+            mstore(add(f, 0xc0), PEDERSEN_G_X_1_HI)
+            mstore(add(f, 0xe0), PEDERSEN_G_X_1_LO)
+            mstore(add(f, 0x100), PEDERSEN_G_Y_0_HI)
+            mstore(add(f, 0x120), PEDERSEN_G_Y_0_LO)
+            mstore(add(f, 0x140), PEDERSEN_G_Y_1_HI)
+            mstore(add(f, 0x160), PEDERSEN_G_Y_1_LO)
+            calldatacopy(add(f, 0x180), commitmentPok, 0x80)
+            mstore(add(f, 0x200), PEDERSEN_GROOTSIGMANEG_X_0_HI)
+            mstore(add(f, 0x220), PEDERSEN_GROOTSIGMANEG_X_0_LO)
+            mstore(add(f, 0x240), PEDERSEN_GROOTSIGMANEG_X_1_HI)
+            mstore(add(f, 0x260), PEDERSEN_GROOTSIGMANEG_X_1_LO)
+            mstore(add(f, 0x280), PEDERSEN_GROOTSIGMANEG_Y_0_HI)
+            mstore(add(f, 0x2a0), PEDERSEN_GROOTSIGMANEG_Y_0_LO)
+            mstore(add(f, 0x2c0), PEDERSEN_GROOTSIGMANEG_Y_1_HI)
+            mstore(add(f, 0x2e0), PEDERSEN_GROOTSIGMANEG_Y_1_LO)
+
+            success := staticcall(gas(), PRECOMPILE_PAIRING, f, 0x300, f, 0x20)
+            // Also check returned value (both are either 1 or 0).
+            success := and(success, mload(f))
+        }
+        if (!success) {
+            revert CommitmentInvalid();
+        }
+    }
+    {{- end }}
+
+    /// Verify an uncompressed Groth16 proof.
+    /// @notice Reverts with InvalidProof if the proof is invalid or
+    /// with PublicInputNotInField the public input is not reduced.
+    /// @notice There is no return value. If the function does not revert, the
+    /// proof was successfully verified.
+    /// @param proof the points (A, B, C) in EIP-2537 format. The G1 points are
+    /// encoded as (x_hi, x_lo, y_hi, y_lo) and the G2 point as
+    /// (x0_hi, x0_lo, x1_hi, x1_lo, y0_hi, y0_lo, y1_hi, y1_lo).
+    {{- if gt $numCommitments 0 }}
+    /// @param commitment the Pedersen commitment in EIP-2537 format.
+    /// @param commitmentPok the proof of knowledge of the commitment in EIP-2537 format.
+    {{- end }}
+    /// @param input the public input field elements in the scalar field Fr.
+    /// Elements must be reduced.
+    function verifyProof(
+        uint256[16] calldata proof,
+        {{- if gt $numCommitments 0 }}
+        uint256[4] calldata commitment,
+        uint256[4] calldata commitmentPok,
+        {{- end }}
+        uint256[{{$numPublic}}] calldata input
+    ) public view {
+        {{- if gt $numCommitments 0 }}
+        verifyCommitment(commitment, commitmentPok);
+
+        // Hash the commitment together with the committed public inputs.
+        // Note: this must match the prover, which should be configured with
+        // backend.WithProverHashToFieldFunction(sha256.New()).
+        uint256 commitmentHash = uint256(
+            sha256(
+                abi.encodePacked(
+                    uint128(commitment[0]),
+                    commitment[1],
+                    uint128(commitment[2]),
+                    commitment[3]
+                    {{- range $j := index .PublicAndCommitmentCommitted 0 }},
+                    input[{{sub $j 1}}]
+                    {{- end }}
+                )
+            )
+        ) % R;
+        uint256[4] memory l = publicInputMSM(input, commitment, commitmentHash);
+        {{- else }}
+        uint256[4] memory l = publicInputMSM(input);
+        {{- end }}
+
+        // Note: The pairing precompile rejects unreduced values and points that
+        // are not in the correct subgroup, so we won't check that here.
+        bool success;
+        assembly ("memory-safe") {
+            let f := mload(0x40) // Free memory pointer.
+
+            // Copy points (A, B, C) to memory. They are already in correct encoding.
+            // This is pairing e(A, B) and G1 of e(C, -δ).
+            calldatacopy(f, proof, 0x200)
+
+            // Complete e(C, -δ) and write e(α, -β), e(L_pub, -γ) to memory.
+            mstore(add(f, 0x200), DELTA_NEG_X_0_HI)
+            mstore(add(f, 0x220), DELTA_NEG_X_0_LO)
+            mstore(add(f, 0x240), DELTA_NEG_X_1_HI)
+            mstore(add(f, 0x260), DELTA_NEG_X_1_LO)
+            mstore(add(f, 0x280), DELTA_NEG_Y_0_HI)
+            mstore(add(f, 0x2a0), DELTA_NEG_Y_0_LO)
+            mstore(add(f, 0x2c0), DELTA_NEG_Y_1_HI)
+            mstore(add(f, 0x2e0), DELTA_NEG_Y_1_LO)
+            mstore(add(f, 0x300), ALPHA_X_HI)
+            mstore(add(f, 0x320), ALPHA_X_LO)
+            mstore(add(f, 0x340), ALPHA_Y_HI)
+            mstore(add(f, 0x360), ALPHA_Y_LO)
+            mstore(add(f, 0x380), BETA_NEG_X_0_HI)
+            mstore(add(f, 0x3a0), BETA_NEG_X_0_LO)
+            mstore(add(f, 0x3c0), BETA_NEG_X_1_HI)
+            mstore(add(f, 0x3e0), BETA_NEG_X_1_LO)
+            mstore(add(f, 0x400), BETA_NEG_Y_0_HI)
+            mstore(add(f, 0x420), BETA_NEG_Y_0_LO)
+            mstore(add(f, 0x440), BETA_NEG_Y_1_HI)
+            mstore(add(f, 0x460), BETA_NEG_Y_1_LO)
+            mstore(add(f, 0x480), mload(l))
+            mstore(add(f, 0x4a0), mload(add(l, 0x20)))
+            mstore(add(f, 0x4c0), mload(add(l, 0x40)))
+            mstore(add(f, 0x4e0), mload(add(l, 0x60)))
+            mstore(add(f, 0x500), GAMMA_NEG_X_0_HI)
+            mstore(add(f, 0x520), GAMMA_NEG_X_0_LO)
+            mstore(add(f, 0x540), GAMMA_NEG_X_1_HI)
+            mstore(add(f, 0x560), GAMMA_NEG_X_1_LO)
+            mstore(add(f, 0x580), GAMMA_NEG_Y_0_HI)
+            mstore(add(f, 0x5a0), GAMMA_NEG_Y_0_LO)
+            mstore(add(f, 0x5c0), GAMMA_NEG_Y_1_HI)
+            mstore(add(f, 0x5e0), GAMMA_NEG_Y_1_LO)
+
+            // Check pairing equation.
+            success := staticcall(gas(), PRECOMPILE_PAIRING, f, 0x600, f, 0x20)
+            // Also check returned value (both are either 1 or 0).
+            success := and(success, mload(f))
+        }
+        if (!success) {
+            // Either proof or verification key invalid.
+            // We assume the contract is correctly generated, so the verification key is valid.
+            revert ProofInvalid();
+        }
+    }
+}
+`
@@ -0,0 +1,34 @@
+//go:build solccheck && (prover_checks || release_checks)
+
+package groth16_test
+
+import (
+	"testing"
+
+	"github.com/consensys/gnark-crypto/ecc"
+	"github.com/consensys/gnark/backend"
+	"github.com/consensys/gnark/frontend"
+	gnarktest "github.com/consensys/gnark/test"
+)
+
+// TestSolidityExecution runs the exported contract through test.Assert, which
+// compiles it with solc and calls verifyProof with the evm tool of
+// go-ethereum, both of which must be in the PATH.
+func TestSolidityExecution(t *testing.T) {
+	for name, c := range map[string]struct {
+		circuit, assignment frontend.Circuit
+	}{
+		"no_commitment": {&solidityCircuit{}, &solidityCircuit{X: 3, Y: 9}},
+		"commitment":    {&solidityCommitmentCircuit{}, &solidityCommitmentCircuit{X: 3, Y: 9}},
+	} {
+		t.Run(name, func(t *testing.T) {
+			assert := gnarktest.NewAssert(t)
+			assert.CheckCircuit(c.circuit,
+				gnarktest.WithCurves(ecc.BLS12_381),
+				gnarktest.WithBackends(backend.GROTH16),
+				gnarktest.WithValidAssignment(c.assignment),
+				gnarktest.NoFuzzing(),
+			)
+		})
+	}
+}
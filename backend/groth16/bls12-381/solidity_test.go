@@ -0,0 +1,133 @@
+package groth16_test
+
+import (
+	"bytes"
+	"crypto/sha256"
+	"testing"
+
+	"github.com/consensys/gnark-crypto/ecc"
+	"github.com/consensys/gnark-crypto/ecc/bls12-381/fp"
+	"github.com/consensys/gnark-crypto/ecc/bls12-381/fr"
+	"github.com/consensys/gnark/backend"
+	groth16 "github.com/consensys/gnark/backend/groth16/bls12-381"
+	cs "github.com/consensys/gnark/constraint/bls12-381"
+	"github.com/consensys/gnark/frontend"
+	"github.com/consensys/gnark/frontend/cs/r1cs"
+	"github.com/stretchr/testify/require"
+)
+
+type solidityCircuit struct {
+	X frontend.Variable `gnark:",public"`
+	Y frontend.Variable
+}
+
+func (c *solidityCircuit) Define(api frontend.API) error {
+	api.AssertIsEqual(api.Mul(c.X, c.X), c.Y)
+	return nil
+}
+
+type solidityCommitmentCircuit struct {
+	X frontend.Variable `gnark:",public"`
+	Y frontend.Variable
+}
+
+func (c *solidityCommitmentCircuit) Define(api frontend.API) error {
+	commit, err := api.Compiler().(frontend.Committer).Commit(c.X, c.Y)
+	if err != nil {
+		return err
+	}
+	api.AssertIsDifferent(commit, 0)
+	api.AssertIsEqual(api.Mul(c.X, c.X), c.Y)
+	return nil
+}
+
+type solidityTwoCommitmentsCircuit struct {
+	X frontend.Variable `gnark:",public"`
+	Y frontend.Variable
+}
+
+func (c *solidityTwoCommitmentsCircuit) Define(api frontend.API) error {
+	committer := api.Compiler().(frontend.Committer)
+	for _, v := range []frontend.Variable{c.X, c.Y} {
+		commit, err := committer.Commit(v)
+		if err != nil {
+			return err
+		}
+		api.AssertIsDifferent(commit, 0)
+	}
+	return nil
+}
+
+func TestExportSolidity(t *testing.T) {
+	for name, circuit := range map[string]frontend.Circuit{
+		"no_commitment": &solidityCircuit{},
+		"commitment":    &solidityCommitmentCircuit{},
+	} {
+		t.Run(name, func(t *testing.T) {
+			assert := require.New(t)
+
+			ccs, err := frontend.Compile(ecc.BLS12_381.ScalarField(), r1cs.NewBuilder, circuit)
+			assert.NoError(err)
+
+			var pk groth16.ProvingKey
+			var vk groth16.VerifyingKey
+			assert.NoError(groth16.Setup(ccs.(*cs.R1CS), &pk, &vk))
+
+			var buf bytes.Buffer
+			assert.NoError(vk.ExportSolidity(&buf))
+			assert.Contains(buf.String(), "PUB_0_X_HI")
+			assert.NotContains(buf.String(), "<no value>")
+
+			// exporting must leave the verifying key untouched
+			var vkBuf, vkBufAfter bytes.Buffer
+			_, err = vk.WriteTo(&vkBuf)
+			assert.NoError(err)
+			buf.Reset()
+			assert.NoError(vk.ExportSolidity(&buf))
+			_, err = vk.WriteTo(&vkBufAfter)
+			assert.NoError(err)
+			assert.Equal(vkBuf.Bytes(), vkBufAfter.Bytes())
+
+			assignment := solidityCircuit{X: 3, Y: 9}
+			w, err := frontend.NewWitness(&assignment, ecc.BLS12_381.ScalarField())
+			assert.NoError(err)
+			proof, err := groth16.Prove(ccs.(*cs.R1CS), &pk, w, backend.WithProverHashToFieldFunction(sha256.New()))
+			assert.NoError(err)
+			pw, err := w.Public()
+			assert.NoError(err)
+			assert.NoError(groth16.Verify(proof, &vk, pw.Vector().(fr.Vector), backend.WithVerifierHashToFieldFunction(sha256.New())))
+
+			// check the layout of the proof given to the contract
+			bts := proof.MarshalSolidity()
+			expectedLen := 16 * 32
+			if len(proof.Commitments) > 0 {
+				expectedLen += 8 * 32
+			}
+			assert.Len(bts, expectedLen)
+			for i := 0; i < len(bts); i += 64 {
+				assert.Equal(make([]byte, 16), bts[i:i+16], "EIP-2537 padding")
+			}
+			var x fp.Element
+			x.SetBytes(bts[16:64])
+			assert.True(x.Equal(&proof.Ar.X))
+			x.SetBytes(bts[64*2+16 : 64*3])
+			assert.True(x.Equal(&proof.Bs.X.A0))
+			x.SetBytes(bts[64*3+16 : 64*4])
+			assert.True(x.Equal(&proof.Bs.X.A1))
+		})
+	}
+}
+
+func TestExportSolidityTooManyCommitments(t *testing.T) {
+	assert := require.New(t)
+
+	ccs, err := frontend.Compile(ecc.BLS12_381.ScalarField(), r1cs.NewBuilder, &solidityTwoCommitmentsCircuit{})
+	assert.NoError(err)
+
+	var pk groth16.ProvingKey
+	var vk groth16.VerifyingKey
+	assert.NoError(groth16.Setup(ccs.(*cs.R1CS), &pk, &vk))
+
+	var buf bytes.Buffer
+	assert.Error(vk.ExportSolidity(&buf))
+}
@@ -17,9 +17,13 @@
 package groth16
 
 import (
+	"bytes"
 	"errors"
 	"fmt"
+	"hash"
 	"io"
+	"math/big"
+	"text/template"
 	"time"
 
 	"github.com/consensys/gnark-crypto/ecc"
@@ -47,6 +51,9 @@ func Verify(proof *Proof, vk *VerifyingKey, publicWitness fr.Vector, opts ...bac
 	if opt.HashToFieldFn == nil {
 		opt.HashToFieldFn = hash_to_field.New([]byte(constraint.CommitmentDst))
 	}
+	if err := vk.checkFingerprint(&opt); err != nil {
+		return err
+	}
 
 	nbPublicVars := len(vk.G1.K) - len(vk.PublicAndCommitmentCommitted)
 
@@ -72,6 +79,142 @@ func Verify(proof *Proof, vk *VerifyingKey, publicWitness fr.Vector, opts ...bac
 		close(chDone)
 	}()
 
+	// compute e(Σx.[Kvk(t)]1, -[γ]2)
+	kSumAff, err := vk.publicInputsSum(proof, publicWitness, opt.HashToFieldFn)
+	if err != nil {
+		return err
+	}
+
+	right, err := curve.MillerLoop([]curve.G1Affine{kSumAff}, []curve.G2Affine{vk.G2.gammaNeg})
+	if err != nil {
+		return err
+	}
+
+	// wait for (eKrsδ, eArBs)
+	if err := <-chDone; err != nil {
+		return err
+	}
+
+	right = curve.FinalExponentiation(&right, &doubleML)
+	if !vk.e.Equal(&right) {
+		return errPairingCheckFailed
+	}
+
+	log.Debug().Dur("took", time.Since(start)).Msg("verifier done")
+	return nil
+}
+
+// checkFingerprint returns constraint.ErrFingerprintMismatch if the verifier
+// expects a constraint system fingerprint other than the one of vk.
+func (vk *VerifyingKey) checkFingerprint(opt *backend.VerifierConfig) error {
+	if opt.Fingerprint != nil && !bytes.Equal(opt.Fingerprint, vk.Fingerprint) {
+		return constraint.ErrFingerprintMismatch
+	}
+	return nil
+}
+
+// BatchVerify verifies a batch of proofs generated for the same VerifyingKey.
+//
+// The Groth16 equations of the proofs are combined with random coefficients rᵢ
+// into a single multi-pairing check:
+//
+//	∏ e(rᵢ·Arᵢ, Bsᵢ)·e(Σ rᵢ·Kᵢ, -[γ]2)·e(Σ rᵢ·Krsᵢ, -[δ]2) = e(α, β)^{Σ rᵢ}
+//
+// If the batch is rejected, the proofs are verified one by one and a
+// *backend.BatchVerifyError holding the index of the first invalid proof is returned.
+func BatchVerify(proofs []*Proof, vk *VerifyingKey, publicWitnesses []fr.Vector, opts ...backend.VerifierOption) error {
+	opt, err := backend.NewVerifierConfig(opts...)
+	if err != nil {
+		return fmt.Errorf("new verifier config: %w", err)
+	}
+	if opt.HashToFieldFn == nil {
+		opt.HashToFieldFn = hash_to_field.New([]byte(constraint.CommitmentDst))
+	}
+	if err := vk.checkFingerprint(&opt); err != nil {
+		return err
+	}
+	if len(proofs) != len(publicWitnesses) {
+		return fmt.Errorf("got %d proofs but %d public witnesses", len(proofs), len(publicWitnesses))
+	}
+	if len(proofs) == 0 {
+		return nil
+	}
+
+	nbPublicVars := len(vk.G1.K) - len(vk.PublicAndCommitmentCommitted)
+
+	log := logger.Logger().With().Str("curve", vk.CurveID().String()).Str("backend", "groth16").Int("nbProofs", len(proofs)).Logger()
+	start := time.Now()
+
+	// random coefficients; the first one can be 1 without loss of soundness
+	coeffs := make([]fr.Element, len(proofs))
+	coeffs[0].SetOne()
+	for i := 1; i < len(coeffs); i++ {
+		if _, err := coeffs[i].SetRandom(); err != nil {
+			return err
+		}
+	}
+
+	P := make([]curve.G1Affine, len(proofs), len(proofs)+2)
+	Q := make([]curve.G2Affine, len(proofs), len(proofs)+2)
+	kSums := make([]curve.G1Affine, len(proofs))
+	krs := make([]curve.G1Affine, len(proofs))
+	var coeffsSum fr.Element
+	for i := range proofs {
+		if len(publicWitnesses[i]) != nbPublicVars-1 {
+			return &backend.BatchVerifyError{Index: i, Err: fmt.Errorf("invalid witness size, got %d, expected %d (public - ONE_WIRE)", len(publicWitnesses[i]), len(vk.G1.K)-1)}
+		}
+		if !proofs[i].isValid() {
+			return &backend.BatchVerifyError{Index: i, Err: errCorrectSubgroupCheckFailed}
+		}
+		if kSums[i], err = vk.publicInputsSum(proofs[i], publicWitnesses[i], opt.HashToFieldFn); err != nil {
+			return &backend.BatchVerifyError{Index: i, Err: err}
+		}
+		var c big.Int
+		coeffs[i].BigInt(&c)
+		P[i].ScalarMultiplication(&proofs[i].Ar, &c)
+		Q[i] = proofs[i].Bs
+		krs[i] = proofs[i].Krs
+		coeffsSum.Add(&coeffsSum, &coeffs[i])
+	}
+
+	var kSum, krsSum curve.G1Affine
+	if _, err := kSum.MultiExp(kSums, coeffs, ecc.MultiExpConfig{}); err != nil {
+		return err
+	}
+	if _, err := krsSum.MultiExp(krs, coeffs, ecc.MultiExpConfig{}); err != nil {
+		return err
+	}
+	P = append(P, kSum, krsSum)
+	Q = append(Q, vk.G2.gammaNeg, vk.G2.deltaNeg)
+
+	ml, err := curve.MillerLoop(P, Q)
+	if err != nil {
+		return err
+	}
+	right := curve.FinalExponentiation(&ml)
+
+	var c big.Int
+	coeffsSum.BigInt(&c)
+	var left curve.GT
+	left.Exp(vk.e, &c)
+	if !left.Equal(&right) {
+		// find the culprit
+		for i := range proofs {
+			if err := Verify(proofs[i], vk, publicWitnesses[i], opts...); err != nil {
+				return &backend.BatchVerifyError{Index: i, Err: err}
+			}
+		}
+		return errPairingCheckFailed
+	}
+
+	log.Debug().Dur("took", time.Since(start)).Msg("batch verifier done")
+	return nil
+}
+
+// publicInputsSum checks the commitments of the proof and returns
+// [Kvk(0)]1 + Σx.[Kvk(t)]1 + Σ commitments, where the public inputs x are
+// completed with the hashes of the commitments.
+func (vk *VerifyingKey) publicInputsSum(proof *Proof, publicWitness fr.Vector, hashToField hash.Hash) (curve.G1Affine, error) {
 	maxNbPublicCommitted := 0
 	for _, s := range vk.PublicAndCommitmentCommitted { // iterate over commitments
 		maxNbPublicCommitted = utils.Max(maxNbPublicCommitted, len(s))
@@ -85,12 +228,12 @@ func Verify(proof *Proof, vk *VerifyingKey, publicWitness fr.Vector, opts ...bac
 			copy(commitmentPrehashSerialized[offset:], publicWitness[vk.PublicAndCommitmentCommitted[i][j]-1].Marshal())
 			offset += fr.Bytes
 		}
-		opt.HashToFieldFn.Write(commitmentPrehashSerialized[:offset])
-		hashBts := opt.HashToFieldFn.Sum(nil)
-		opt.HashToFieldFn.Reset()
+		hashToField.Write(commitmentPrehashSerialized[:offset])
+		hashBts := hashToField.Sum(nil)
+		hashToField.Reset()
 		nbBuf := fr.Bytes
-		if opt.HashToFieldFn.Size() < fr.Bytes {
-			nbBuf = opt.HashToFieldFn.Size()
+		if hashToField.Size() < fr.Bytes {
+			nbBuf = hashToField.Size()
 		}
 		var res fr.Element
 		res.SetBytes(hashBts[:nbBuf])
@@ -99,17 +242,16 @@ func Verify(proof *Proof, vk *VerifyingKey, publicWitness fr.Vector, opts ...bac
 	}
 
 	if folded, err := pedersen.FoldCommitments(proof.Commitments, commitmentsSerialized); err != nil {
-		return err
+		return curve.G1Affine{}, err
 	} else {
 		if err = vk.CommitmentKey.Verify(folded, proof.CommitmentPok); err != nil {
-			return err
+			return curve.G1Affine{}, err
 		}
 	}
 
-	// compute e(Σx.[Kvk(t)]1, -[γ]2)
 	var kSum curve.G1Jac
 	if _, err := kSum.MultiExp(vk.G1.K[1:], publicWitness, ecc.MultiExpConfig{}); err != nil {
-		return err
+		return curve.G1Affine{}, err
 	}
 	kSum.AddMixed(&vk.G1.K[0])
 
@@ -119,27 +261,63 @@ func Verify(proof *Proof, vk *VerifyingKey, publicWitness fr.Vector, opts ...bac
 
 	var kSumAff curve.G1Affine
 	kSumAff.FromJacobian(&kSum)
+	return kSumAff, nil
+}
 
-	right, err := curve.MillerLoop([]curve.G1Affine{kSumAff}, []curve.G2Affine{vk.G2.gammaNeg})
-	if err != nil {
-		return err
+// ExportSolidity writes a solidity Verifier contract on provided writer.
+// This is an experimental feature and gnark solidity generator as not been thoroughly tested.
+//
+// The contract relies on the BLS12-381 precompiles of EIP-2537. Proofs must be
+// encoded with Proof.MarshalSolidity. At most one commitment is supported; the
+// commitment must be hashed with SHA256, see
+// backend.WithProverHashToFieldFunction and backend.WithVerifierHashToFieldFunction.
+//
+// See https://github.com/ConsenSys/gnark-tests for example usage.
+func (vk *VerifyingKey) ExportSolidity(w io.Writer) error {
+	if len(vk.PublicAndCommitmentCommitted) > 1 {
+		return errors.New("solidity verifier supports at most one commitment")
+	}
+	helpers := template.FuncMap{
+		"sub": func(a, b int) int {
+			return a - b
+		},
+		"mul": func(a, b int) int {
+			return a * b
+		},
+		"add": func(a, b int) int {
+			return a + b
+		},
+		"fpHi": fpHi,
+		"fpLo": fpLo,
+		"intRange": func(max int) []int {
+			out := make([]int, max)
+			for i := 0; i < max; i++ {
+				out[i] = i
+			}
+			return out
+		},
 	}
 
-	// wait for (eKrsδ, eArBs)
-	if err := <-chDone; err != nil {
+	tmpl, err := template.New("").Funcs(helpers).Parse(solidityTemplate)
+	if err != nil {
 		return err
 	}
 
-	right = curve.FinalExponentiation(&right, &doubleML)
-	if !vk.e.Equal(&right) {
-		return errPairingCheckFailed
-	}
+	// negate Beta, Gamma and Delta, to avoid negating proof elements in the verifier
+	var betaNeg curve.G2Affine
+	betaNeg.Neg(&vk.G2.Beta)
+	beta := vk.G2.Beta
+	vk.G2.Beta = betaNeg
+	vk.G2.Gamma, vk.G2.gammaNeg = vk.G2.gammaNeg, vk.G2.Gamma
+	vk.G2.Delta, vk.G2.deltaNeg = vk.G2.deltaNeg, vk.G2.Delta
 
-	log.Debug().Dur("took", time.Since(start)).Msg("verifier done")
-	return nil
-}
+	// execute template
+	err = tmpl.Execute(w, vk)
 
-// ExportSolidity not implemented for BLS12-381
-func (vk *VerifyingKey) ExportSolidity(w io.Writer) error {
-	return errors.New("not implemented")
+	// restore Beta, Gamma and Delta
+	vk.G2.Beta = beta
+	vk.G2.Gamma, vk.G2.gammaNeg = vk.G2.gammaNeg, vk.G2.Gamma
+	vk.G2.Delta, vk.G2.deltaNeg = vk.G2.deltaNeg, vk.G2.Delta
+
+	return err
 }
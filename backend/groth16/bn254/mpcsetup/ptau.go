@@ -0,0 +1,756 @@
+// Copyright 2020 ConsenSys Software Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package mpcsetup
+
+import (
+	"bufio"
+	"bytes"
+	"encoding/binary"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"hash"
+	"io"
+	"sync/atomic"
+
+	curve "github.com/consensys/gnark-crypto/ecc/bn254"
+	"github.com/consensys/gnark-crypto/ecc/bn254/fp"
+	"github.com/consensys/gnark/internal/utils"
+	"golang.org/x/crypto/blake2b"
+)
+
+// sections of the snarkjs .ptau binary format
+const (
+	ptauSectionHeader        = 1
+	ptauSectionTauG1         = 2
+	ptauSectionTauG2         = 3
+	ptauSectionAlphaTauG1    = 4
+	ptauSectionBetaTauG1     = 5
+	ptauSectionBetaG2        = 6
+	ptauSectionContributions = 7
+)
+
+const (
+	ptauFpSize = fp.Bytes
+	ptauG1Size = 2 * ptauFpSize
+	ptauG2Size = 4 * ptauFpSize
+)
+
+// PtauContribution is a contribution recorded in a .ptau transcript
+type PtauContribution struct {
+	// TauG1, TauG2, AlphaG1, BetaG1 and BetaG2 are [τ]₁, [τ]₂, [α]₁, [β]₁
+	// and [β]₂ after the contribution
+	TauG1, AlphaG1, BetaG1 curve.G1Affine
+	TauG2, BetaG2          curve.G2Affine
+
+	// PublicKeys proves the knowledge of the contributed τ, α and β
+	PublicKeys struct {
+		Tau, Alpha, Beta PublicKey
+	}
+
+	// PartialHash is the blake2b state of the response hash before the public
+	// keys were absorbed, ResponseHash the response hash, and NextChallenge the
+	// challenge given to the next participant.
+	PartialHash   []byte
+	ResponseHash  []byte
+	NextChallenge []byte
+
+	// Type is 0 for a contribution and 1 for a random beacon
+	Type uint32
+	Name string
+
+	// BeaconHash and BeaconIterationsExp are the parameters of a random
+	// beacon: the secrets are derived from BeaconHash hashed
+	// 2^BeaconIterationsExp times with SHA256.
+	BeaconHash          []byte
+	BeaconIterationsExp byte
+}
+
+// ReadPtau reads a Powers of Tau transcript in the snarkjs binary format
+// (.ptau), as produced by the Perpetual Powers of Tau ceremony, and converts
+// it to a Phase1 which can be used in InitPhase2.
+//
+// The transcript is verified as snarkjs does:
+//   - all the points are checked to be on the curve and in the correct subgroup;
+//   - the powers of τ, α and β are checked to be consistent;
+//   - each contribution is checked to update [τ], [α] and [β] with the secrets
+//     of its proofs of knowledge, which are bound to the challenge hash of the
+//     previous contribution, and random beacons are recomputed;
+//   - the response hash and the next challenge hash of the last contribution
+//     are recomputed from the powers.
+//
+// The public keys of the returned Phase1 are the ones of the last contribution.
+func ReadPtau(r io.ReadSeeker) (Phase1, []PtauContribution, error) {
+	var phase1 Phase1
+
+	sections, err := readPtauSections(r)
+	if err != nil {
+		return phase1, nil, err
+	}
+
+	// header
+	header, err := sections.read(r, ptauSectionHeader)
+	if err != nil {
+		return phase1, nil, err
+	}
+	if len(header) < 4+ptauFpSize+8 {
+		return phase1, nil, errors.New("ptau: invalid header size")
+	}
+	if n8 := binary.LittleEndian.Uint32(header); n8 != ptauFpSize {
+		return phase1, nil, fmt.Errorf("ptau: unexpected field size %d", n8)
+	}
+	q := fp.Modulus().Bytes()
+	for i := range q {
+		if header[4+i] != q[len(q)-1-i] {
+			return phase1, nil, errors.New("ptau: the transcript is not for BN254")
+		}
+	}
+	power := binary.LittleEndian.Uint32(header[4+ptauFpSize:])
+	ceremonyPower := binary.LittleEndian.Uint32(header[4+ptauFpSize+4:])
+	if power == 0 || power > 28 || ceremonyPower < power || ceremonyPower > 28 {
+		return phase1, nil, fmt.Errorf("ptau: invalid power %d", power)
+	}
+	N := 1 << power
+
+	// powers
+	if phase1.Parameters.G1.Tau, err = readPtauG1s(r, sections, ptauSectionTauG1, 2*N-1); err != nil {
+		return phase1, nil, err
+	}
+	if phase1.Parameters.G2.Tau, err = readPtauG2s(r, sections, ptauSectionTauG2, N); err != nil {
+		return phase1, nil, err
+	}
+	if phase1.Parameters.G1.AlphaTau, err = readPtauG1s(r, sections, ptauSectionAlphaTauG1, N); err != nil {
+		return phase1, nil, err
+	}
+	if phase1.Parameters.G1.BetaTau, err = readPtauG1s(r, sections, ptauSectionBetaTauG1, N); err != nil {
+		return phase1, nil, err
+	}
+	betaG2, err := readPtauG2s(r, sections, ptauSectionBetaG2, 1)
+	if err != nil {
+		return phase1, nil, err
+	}
+	phase1.Parameters.G2.Beta = betaG2[0]
+
+	// contributions
+	buf, err := sections.read(r, ptauSectionContributions)
+	if err != nil {
+		return phase1, nil, err
+	}
+	contributions, err := readPtauContributions(buf)
+	if err != nil {
+		return phase1, nil, err
+	}
+	if len(contributions) == 0 {
+		return phase1, nil, errors.New("ptau: the transcript has no contribution")
+	}
+
+	if err := checkPtau(&phase1, contributions, power, ceremonyPower); err != nil {
+		return phase1, nil, err
+	}
+
+	last := &contributions[len(contributions)-1]
+	phase1.PublicKeys = last.PublicKeys
+	phase1.Hash = phase1.hash()
+
+	return phase1, contributions, nil
+}
+
+// checkPtau checks that the contributions form a chain of updates leading to
+// the powers, and that the powers are consistent.
+func checkPtau(phase1 *Phase1, contributions []PtauContribution, power, ceremonyPower uint32) error {
+	_, _, g1, g2 := curve.Generators()
+
+	// the contributions chain from the generators to the final powers
+	prevTauG1, prevAlphaG1, prevBetaG1 := g1, g1, g1
+	prevTauG2, prevBetaG2 := g2, g2
+	prevChallenge := ptauFirstChallenge(ceremonyPower)
+	for i := range contributions {
+		c := &contributions[i]
+		keys := &c.PublicKeys
+		var err error
+		if c.ResponseHash, err = ptauResponseHash(c); err != nil {
+			return fmt.Errorf("ptau: contribution %d: %w", i+1, err)
+		}
+		switch c.Type {
+		case 0:
+		case 1:
+			_, beaconKeys := newPtauKey(newPtauBeaconRng(c.BeaconHash, c.BeaconIterationsExp), prevChallenge)
+			for j, pk := range []*PublicKey{&keys.Tau, &keys.Alpha, &keys.Beta} {
+				if !pk.SG.Equal(&beaconKeys[j].SG) || !pk.SXG.Equal(&beaconKeys[j].SXG) {
+					return fmt.Errorf("ptau: contribution %d: the keys don't match the random beacon", i+1)
+				}
+			}
+		default:
+			return fmt.Errorf("ptau: contribution %d: unknown type %d", i+1, c.Type)
+		}
+
+		// proofs of knowledge of τ, α and β
+		var r [3]curve.G2Affine
+		for j, pk := range []*PublicKey{&keys.Tau, &keys.Alpha, &keys.Beta} {
+			r[j] = ptauKeyR(byte(j), prevChallenge, &pk.SG, &pk.SXG)
+			if !r[j].IsInSubGroup() || !sameRatio(pk.SXG, pk.SG, r[j], pk.XR) {
+				return fmt.Errorf("ptau: contribution %d: invalid proof of knowledge", i+1)
+			}
+		}
+
+		// updates of τ, α and β
+		if !sameRatio(c.TauG1, prevTauG1, r[0], keys.Tau.XR) {
+			return fmt.Errorf("ptau: contribution %d: invalid update of [τ]₁", i+1)
+		}
+		if !sameRatio(keys.Tau.SXG, keys.Tau.SG, prevTauG2, c.TauG2) {
+			return fmt.Errorf("ptau: contribution %d: invalid update of [τ]₂", i+1)
+		}
+		if !sameRatio(c.AlphaG1, prevAlphaG1, r[1], keys.Alpha.XR) {
+			return fmt.Errorf("ptau: contribution %d: invalid update of [α]₁", i+1)
+		}
+		if !sameRatio(c.BetaG1, prevBetaG1, r[2], keys.Beta.XR) {
+			return fmt.Errorf("ptau: contribution %d: invalid update of [β]₁", i+1)
+		}
+		if !sameRatio(keys.Beta.SXG, keys.Beta.SG, prevBetaG2, c.BetaG2) {
+			return fmt.Errorf("ptau: contribution %d: invalid update of [β]₂", i+1)
+		}
+		if c.TauG1.IsInfinity() || c.AlphaG1.IsInfinity() || c.BetaG1.IsInfinity() {
+			return fmt.Errorf("ptau: contribution %d: degenerate update", i+1)
+		}
+		prevTauG1, prevAlphaG1, prevBetaG1 = c.TauG1, c.AlphaG1, c.BetaG1
+		prevTauG2, prevBetaG2 = c.TauG2, c.BetaG2
+		if i < len(contributions)-1 {
+			prevChallenge = c.NextChallenge
+		}
+	}
+	last := &contributions[len(contributions)-1]
+	if !last.TauG1.Equal(&phase1.Parameters.G1.Tau[1]) || !last.TauG2.Equal(&phase1.Parameters.G2.Tau[1]) ||
+		!last.AlphaG1.Equal(&phase1.Parameters.G1.AlphaTau[0]) || !last.BetaG1.Equal(&phase1.Parameters.G1.BetaTau[0]) ||
+		!last.BetaG2.Equal(&phase1.Parameters.G2.Beta) {
+		return errors.New("ptau: the last contribution doesn't match the powers")
+	}
+
+	// the hashes of the last contribution are bound to the powers. The powers
+	// of a truncated transcript can't be hashed as in the original ceremony.
+	if power == ceremonyPower {
+		h, _ := blake2b.New512(nil)
+		h.Write(prevChallenge)
+		writePtauPowers(h, phase1, true)
+		h.Write(ptauPublicKeysBytes(&last.PublicKeys))
+		if !bytes.Equal(h.Sum(nil), last.ResponseHash) {
+			return errors.New("ptau: the response hash of the last contribution doesn't match the powers")
+		}
+		h.Reset()
+		h.Write(last.ResponseHash)
+		writePtauPowers(h, phase1, false)
+		if !bytes.Equal(h.Sum(nil), last.NextChallenge) {
+			return errors.New("ptau: the challenge hash of the last contribution doesn't match the powers")
+		}
+	}
+
+	// the powers are consistent
+	if !phase1.Parameters.G1.Tau[0].Equal(&g1) || !phase1.Parameters.G2.Tau[0].Equal(&g2) {
+		return errors.New("ptau: [τ⁰] must be the generators")
+	}
+	tauL1, tauL2 := linearCombinationG1(phase1.Parameters.G1.Tau)
+	if !sameRatio(tauL1, tauL2, phase1.Parameters.G2.Tau[1], g2) {
+		return errors.New("ptau: invalid powers of τ in G₁")
+	}
+	alphaL1, alphaL2 := linearCombinationG1(phase1.Parameters.G1.AlphaTau)
+	if !sameRatio(alphaL1, alphaL2, phase1.Parameters.G2.Tau[1], g2) {
+		return errors.New("ptau: invalid powers of α(τ) in G₁")
+	}
+	betaL1, betaL2 := linearCombinationG1(phase1.Parameters.G1.BetaTau)
+	if !sameRatio(betaL1, betaL2, phase1.Parameters.G2.Tau[1], g2) {
+		return errors.New("ptau: invalid powers of β(τ) in G₁")
+	}
+	tau2L1, tau2L2 := linearCombinationG2(phase1.Parameters.G2.Tau)
+	if !sameRatio(phase1.Parameters.G1.Tau[1], g1, tau2L1, tau2L2) {
+		return errors.New("ptau: invalid powers of τ in G₂")
+	}
+	if !sameRatio(phase1.Parameters.G1.BetaTau[0], g1, g2, phase1.Parameters.G2.Beta) {
+		return errors.New("ptau: [β]₁ and [β]₂ differ")
+	}
+
+	return nil
+}
+
+// ptauFirstChallenges are the challenge hashes given to the first contributor
+// of a ceremony of 2^power powers: the blake2b hash of the hash of the empty
+// string followed by the uncompressed powers, all set to the generators.
+// Computing them is linear in the size of the ceremony, more than 100GB of
+// hashing for the power 28 of the Perpetual Powers of Tau.
+var ptauFirstChallenges = [...]string{
+	1:  "e809c07e01ec4d01624089c1f4009ec9ba62964e9056113d2fa6f3bfdf29ff2cc4ebcda749cd53327598cb0caac7dbe3b50cda3f75c64f87845ce6345fd964e4",
+	2:  "cbe18de1dbb2c768cc2516accddf9c75fd5e082e6e57a6a1e3d10371ec9584c23d094a5ff2db25f5aa7ca42ba1391d1b28ab3218984c9766ae496c8781457b52",
+	3:  "45f580c564b26059f533418e8c0cbdbcdd73543f221bfa741c1a5eeb71b0c2cd4ab44f2f0600f3d7ba93203f9b04e2de3f325a88d0cdfcd902b08b85e071a471",
+	4:  "2054432085403180e1678602c83562f1f4ddefafb4b9e7171b53070455a4cc6db11b2e5bfe5e89c0cb9ab4a7b3b9fd5a17bad62ad5ba013c34e7dd2fbb4f143b",
+	5:  "29bb480744aa1a5b6ca7de75bc5dca1f6881eefd874eb1e7c2701ade16bb1cb2af840ad29c60a7a9784ec4485ba2edfb5e92acab96484572b45c7e70002ee51c",
+	6:  "b2109ce5808995fbcd80712eabdbf6f5068841065d329308437e684d41496f8c431b9faf854c487694c3dcac03613078ba00c055795c5a49a96f5ad0a9065bc8",
+	7:  "e71f759938e4ffde9f94d238a3f25fc55b42aacb4bc330b822e49dff524b5420d6181c6c1b8dbdacf84f2556ce5c3f608db0fe473101bf071358089a4346485f",
+	8:  "219cd1f3eab9d2a70ebec1e89ce41ade8d761eb39fd6702acda5776283026ac881746beac81c214b887e9102e84c8341824fd983f4e7df844d150ddf5fd2fe48",
+	9:  "0d8118d8d038768c26c9439251627e2a19293bf0f18cf95c7642d2f8d736e739668138aaf900709eedb1b0502a7577abe16644ee80313b09a7f05c621980083f",
+	10: "95f0b4499e50f8da383b0d74c174c1698bdffe1b35066754005889a147849bbf8d64ff6c989bd89a4736b569a99a1c83a50dc181e9fe1d4d23d1888a98b3157e",
+	11: "e778ddf57120714d0a7a884113aac0db0c37dee0d580dcb4b3794fe5b2b68875c32f02759a860990bec44cbd38a86feaabea62ea9a0b682b3c076003c80042fd",
+	12: "9e63a5f62b96538daaed2372481920d1a40b91959ea38ef9f5f6a3033b8865160710d067c09d09615f928ea517bcdf49ad75abd2c8340b400e3b18e968b4ffef",
+	13: "b149df2329d37dee14a2a9c9ddcd0eb8e1fb6a5af5dcbdd4f46a10e0176dac306257919995193c2cb067c4677138f506341dc2d3279dc0204fcc2e9d14390581",
+	14: "bc0bde7980381fa642b2097591dd83f1ed15b003e15c35520af32c95eb5191492a6f3175215635cfc10e6098e2c612d0ca84f1a9f90b5333560c8af59b9209f4",
+	15: "eca6f514b89180fcfc6bf9f881a5670c45419054f1f6fec93628d6d1bf995fbf677d427cd40a7c05ebb14fe5ee96aff2b4994dc0e2904852b408a9e7fe36a02e",
+	16: "e27d7e51abd16bb3c46609c75e963b5fdbe25b00c0b93a4c528b569ad4b50fdad9926ff2781f4baefac213db214f30afe681f7be5c1f973cc567c817e871f958",
+	17: "d27bebee8c0abf5066dd8742fa7de8c454bea04a8afad209d51f58ec16bcea9e02b2774d6d408b4a71af1986203a7ed7d9d2d6d5fb7c5318b8d58183a15b9706",
+	18: "c3f903071060d9282ea4d31ef85d9dbd05f865dedb78cb138f740796091ffcd5d61ca255535fffd7df21669f04534057a3985a51f0de7909ed950d699f0e57bd",
+	19: "960060531a46ce7980c117badf032684e3adc78866b7bf7b26b34cb1165df31d8ffdf7de6dd3e902839afe451da673adcea5259fd968b7135b867bad2760ceb2",
+	20: "3393605118d83c21a2a1763bec8ebf7a6076c7546b4bbf01e35e71faad0214cf18fa19b6053dfd1102454d05754b1b21873da78e0d5d66be1f74169de3963011",
+	21: "d27e24afa6f9d22893b893924ab301023e558340a2f0fe360497061d7f91a659d6bcd23467668ad92f65fb2998da8690014a8341cc795253cec84fb87fe195b8",
+	22: "5b4d52085c949b60ab5060c93196dc51b2dc629c4dcfc5d1fdb9466e3c6c052bd1f9bada6ee24a60c0474017b7c08f51fce83c75845fb489547aa9453e256cf6",
+	23: "da01c213149ff5065924f3ade76df2ec6ae7bb941ab4ffe25e3e2d1ae9f0474faebb201bddef89d841692d71e10f3f7a3d0ffe5ce2b6162d8b692b95c28b4039",
+	24: "adc423b1cd43ea5a40601c30364febdcdf4796f0ffc56c01e1f64019146e60f9c9b68c75f1e36e275c336acbee5632f69569c9f2267dfda4ad59dcb15b8f3e84",
+	25: "661de6f41b1150ac7448085558e5ecdaae345272e662da9851b0ff3816901a2b2141722a38a35a314b41a53abba15f7198f30c57891111864081aa38d3012a5b",
+	26: "5140c98bda53f8c1fc3a25d574c409d5de41561b585b4224ab5ed369a98f2e41389c39c83b47470701e52261fa199918666181be3855d33e2ef19377365b038b",
+	27: "36bcd31f9d5ed309ded4a17ee8279e34eceec40b56be88e2fb604aebe2c714bafbf99218e7269f20ec3392bab9d45f5198b826c94bd3d3c2d780f46dfd65be67",
+	28: "93da91920d5a54a8a0fde55cd9dc3a10c4f3eef768b62c0948741370864254b4c1920f3f29d4ebc0ef3acecf2e2db63a755713d77e1ed77347a56fbc317c7a93",
+}
+
+// ptauFirstChallenge returns the challenge hash given to the first
+// contributor of a ceremony of 2^power powers.
+func ptauFirstChallenge(power uint32) []byte {
+	res, err := hex.DecodeString(ptauFirstChallenges[power])
+	if err != nil {
+		panic(err)
+	}
+	return res
+}
+
+// ptauResponseHash resumes the response hash of c from its partial hash, by
+// absorbing the public keys.
+//
+// The partial hash is the 216 bytes state of the blake2b-wasm package: the
+// input buffer (128 bytes), the chaining value h (8 little-endian words), the
+// number of bytes compressed t, the number of bytes in the buffer c and the
+// output length, the last three as little-endian 64 bits words.
+func ptauResponseHash(c *PtauContribution) ([]byte, error) {
+	ph := c.PartialHash
+	t := binary.LittleEndian.Uint64(ph[192:])
+	offset := binary.LittleEndian.Uint64(ph[200:])
+	if offset > blake2b.BlockSize || t%blake2b.BlockSize != 0 {
+		return nil, errors.New("invalid partial hash")
+	}
+
+	// x/crypto/blake2b marshaled state
+	state := make([]byte, 0, 3+8*8+2*8+1+blake2b.BlockSize+1)
+	state = append(state, "b2b"...)
+	for i := 0; i < 8; i++ {
+		state = binary.BigEndian.AppendUint64(state, binary.LittleEndian.Uint64(ph[128+8*i:]))
+	}
+	state = binary.BigEndian.AppendUint64(state, t)
+	state = binary.BigEndian.AppendUint64(state, 0)
+	state = append(state, blake2b.Size)
+	state = append(state, ph[:blake2b.BlockSize]...)
+	state = append(state, byte(offset))
+
+	h, _ := blake2b.New512(nil)
+	if err := h.(interface{ UnmarshalBinary([]byte) error }).UnmarshalBinary(state); err != nil {
+		return nil, err
+	}
+	h.Write(ptauPublicKeysBytes(&c.PublicKeys))
+	return h.Sum(nil), nil
+}
+
+// ptauPublicKeysBytes returns the public keys of a contribution as they are
+// hashed in the response hash.
+func ptauPublicKeysBytes(keys *struct{ Tau, Alpha, Beta PublicKey }) []byte {
+	res := make([]byte, 0, 6*ptauG1Size+3*ptauG2Size)
+	for _, pk := range []*PublicKey{&keys.Tau, &keys.Alpha, &keys.Beta} {
+		res = append(res, ptauUncompressedG1(&pk.SG)...)
+		res = append(res, ptauUncompressedG1(&pk.SXG)...)
+	}
+	for _, pk := range []*PublicKey{&keys.Tau, &keys.Alpha, &keys.Beta} {
+		res = append(res, ptauUncompressedG2(&pk.XR)...)
+	}
+	return res
+}
+
+// writePtauPowers writes the powers to h, with compressed or uncompressed points
+func writePtauPowers(h hash.Hash, phase1 *Phase1, compressed bool) {
+	w := bufio.NewWriterSize(h, 1<<20)
+	g1s := func(points []curve.G1Affine) {
+		for i := range points {
+			if compressed {
+				w.Write(ptauCompressedG1(&points[i]))
+			} else {
+				w.Write(ptauUncompressedG1(&points[i]))
+			}
+		}
+	}
+	g2s := func(points []curve.G2Affine) {
+		for i := range points {
+			if compressed {
+				w.Write(ptauCompressedG2(&points[i]))
+			} else {
+				w.Write(ptauUncompressedG2(&points[i]))
+			}
+		}
+	}
+	g1s(phase1.Parameters.G1.Tau)
+	g2s(phase1.Parameters.G2.Tau)
+	g1s(phase1.Parameters.G1.AlphaTau)
+	g1s(phase1.Parameters.G1.BetaTau)
+	g2s([]curve.G2Affine{phase1.Parameters.G2.Beta})
+	w.Flush()
+}
+
+// point encodings used in the hashes: big-endian coordinates, with 𝔽p²
+// elements written (a₁, a₀). The point at infinity has the flag 0x40, and
+// compressed points the flag 0x80 if y is lexicographically largest.
+
+func ptauUncompressedG1(p *curve.G1Affine) []byte {
+	res := make([]byte, ptauG1Size)
+	if p.IsInfinity() {
+		res[0] = 0x40
+		return res
+	}
+	fp.BigEndian.PutElement((*[fp.Bytes]byte)(res[0:]), p.X)
+	fp.BigEndian.PutElement((*[fp.Bytes]byte)(res[fp.Bytes:]), p.Y)
+	return res
+}
+
+func ptauUncompressedG2(p *curve.G2Affine) []byte {
+	res := make([]byte, ptauG2Size)
+	if p.IsInfinity() {
+		res[0] = 0x40
+		return res
+	}
+	for i, e := range []*fp.Element{&p.X.A1, &p.X.A0, &p.Y.A1, &p.Y.A0} {
+		fp.BigEndian.PutElement((*[fp.Bytes]byte)(res[i*fp.Bytes:]), *e)
+	}
+	return res
+}
+
+func ptauCompressedG1(p *curve.G1Affine) []byte {
+	res := make([]byte, ptauG1Size/2)
+	if p.IsInfinity() {
+		res[0] = 0x40
+		return res
+	}
+	fp.BigEndian.PutElement((*[fp.Bytes]byte)(res), p.X)
+	if p.Y.LexicographicallyLargest() {
+		res[0] |= 0x80
+	}
+	return res
+}
+
+func ptauCompressedG2(p *curve.G2Affine) []byte {
+	res := make([]byte, ptauG2Size/2)
+	if p.IsInfinity() {
+		res[0] = 0x40
+		return res
+	}
+	fp.BigEndian.PutElement((*[fp.Bytes]byte)(res[0:]), p.X.A1)
+	fp.BigEndian.PutElement((*[fp.Bytes]byte)(res[fp.Bytes:]), p.X.A0)
+	if p.Y.LexicographicallyLargest() {
+		res[0] |= 0x80
+	}
+	return res
+}
+
+type ptauSections map[uint32]struct{ offset, size int64 }
+
+// readPtauSections reads the file header and the list of sections
+func readPtauSections(r io.ReadSeeker) (ptauSections, error) {
+	var header [12]byte
+	if _, err := io.ReadFull(r, header[:]); err != nil {
+		return nil, err
+	}
+	if string(header[:4]) != "ptau" {
+		return nil, errors.New("ptau: invalid magic number")
+	}
+	if version := binary.LittleEndian.Uint32(header[4:]); version != 1 {
+		return nil, fmt.Errorf("ptau: unsupported version %d", version)
+	}
+	nbSections := binary.LittleEndian.Uint32(header[8:])
+
+	sections := make(ptauSections, nbSections)
+	offset := int64(len(header))
+	for i := uint32(0); i < nbSections; i++ {
+		var sectionHeader [12]byte
+		if _, err := io.ReadFull(r, sectionHeader[:]); err != nil {
+			return nil, err
+		}
+		offset += int64(len(sectionHeader))
+		sectionType := binary.LittleEndian.Uint32(sectionHeader[:])
+		size := binary.LittleEndian.Uint64(sectionHeader[4:])
+		if size > 1<<62 {
+			return nil, errors.New("ptau: invalid section size")
+		}
+		if _, ok := sections[sectionType]; ok {
+			return nil, fmt.Errorf("ptau: duplicate section %d", sectionType)
+		}
+		sections[sectionType] = struct{ offset, size int64 }{offset, int64(size)}
+		if offset, _ = r.Seek(int64(size), io.SeekCurrent); offset < 0 {
+			return nil, errors.New("ptau: seek failed")
+		}
+	}
+	return sections, nil
+}
+
+// open positions r at the beginning of the section and returns a reader limited to it
+func (s ptauSections) open(r io.ReadSeeker, sectionType uint32, expectedSize int64) (io.Reader, error) {
+	section, ok := s[sectionType]
+	if !ok {
+		return nil, fmt.Errorf("ptau: missing section %d", sectionType)
+	}
+	if expectedSize >= 0 && section.size != expectedSize {
+		return nil, fmt.Errorf("ptau: section %d has size %d, expected %d", sectionType, section.size, expectedSize)
+	}
+	if _, err := r.Seek(section.offset, io.SeekStart); err != nil {
+		return nil, err
+	}
+	return bufio.NewReaderSize(io.LimitReader(r, section.size), 1<<20), nil
+}
+
+// read returns the content of a (small) section
+func (s ptauSections) read(r io.ReadSeeker, sectionType uint32) ([]byte, error) {
+	sr, err := s.open(r, sectionType, -1)
+	if err != nil {
+		return nil, err
+	}
+	buf := make([]byte, s[sectionType].size)
+	_, err = io.ReadFull(sr, buf)
+	return buf, err
+}
+
+func readPtauG1s(r io.ReadSeeker, sections ptauSections, sectionType uint32, n int) ([]curve.G1Affine, error) {
+	sr, err := sections.open(r, sectionType, int64(n)*ptauG1Size)
+	if err != nil {
+		return nil, err
+	}
+	points := make([]curve.G1Affine, n)
+	var buf [ptauG1Size]byte
+	for i := range points {
+		if _, err := io.ReadFull(sr, buf[:]); err != nil {
+			return nil, err
+		}
+		if err := setPtauG1(&points[i], buf[:]); err != nil {
+			return nil, fmt.Errorf("ptau: section %d: %w", sectionType, err)
+		}
+	}
+	if err := checkSubgroupG1(points); err != nil {
+		return nil, fmt.Errorf("ptau: section %d: %w", sectionType, err)
+	}
+	return points, nil
+}
+
+func readPtauG2s(r io.ReadSeeker, sections ptauSections, sectionType uint32, n int) ([]curve.G2Affine, error) {
+	sr, err := sections.open(r, sectionType, int64(n)*ptauG2Size)
+	if err != nil {
+		return nil, err
+	}
+	points := make([]curve.G2Affine, n)
+	var buf [ptauG2Size]byte
+	for i := range points {
+		if _, err := io.ReadFull(sr, buf[:]); err != nil {
+			return nil, err
+		}
+		if err := setPtauG2(&points[i], buf[:]); err != nil {
+			return nil, fmt.Errorf("ptau: section %d: %w", sectionType, err)
+		}
+	}
+	if err := checkSubgroupG2(points); err != nil {
+		return nil, fmt.Errorf("ptau: section %d: %w", sectionType, err)
+	}
+	return points, nil
+}
+
+// readPtauContributions parses the contributions section
+func readPtauContributions(buf []byte) ([]PtauContribution, error) {
+	r := bytes.NewReader(buf)
+	var nbContributions uint32
+	if err := binary.Read(r, binary.LittleEndian, &nbContributions); err != nil {
+		return nil, err
+	}
+	if uint64(nbContributions)*(5*ptauG1Size+6*ptauG2Size) > uint64(len(buf)) {
+		return nil, errors.New("ptau: invalid number of contributions")
+	}
+
+	contributions := make([]PtauContribution, nbContributions)
+	var g1Buf [ptauG1Size]byte
+	var g2Buf [ptauG2Size]byte
+	readG1 := func(p *curve.G1Affine) error {
+		if _, err := io.ReadFull(r, g1Buf[:]); err != nil {
+			return err
+		}
+		return setPtauG1(p, g1Buf[:])
+	}
+	readG2 := func(p *curve.G2Affine) error {
+		if _, err := io.ReadFull(r, g2Buf[:]); err != nil {
+			return err
+		}
+		return setPtauG2(p, g2Buf[:])
+	}
+
+	for i := range contributions {
+		c := &contributions[i]
+		keys := &c.PublicKeys
+		for _, read := range []func() error{
+			func() error { return readG1(&c.TauG1) },
+			func() error { return readG2(&c.TauG2) },
+			func() error { return readG1(&c.AlphaG1) },
+			func() error { return readG1(&c.BetaG1) },
+			func() error { return readG2(&c.BetaG2) },
+			func() error { return readG1(&keys.Tau.SG) },
+			func() error { return readG1(&keys.Tau.SXG) },
+			func() error { return readG1(&keys.Alpha.SG) },
+			func() error { return readG1(&keys.Alpha.SXG) },
+			func() error { return readG1(&keys.Beta.SG) },
+			func() error { return readG1(&keys.Beta.SXG) },
+			func() error { return readG2(&keys.Tau.XR) },
+			func() error { return readG2(&keys.Alpha.XR) },
+			func() error { return readG2(&keys.Beta.XR) },
+		} {
+			if err := read(); err != nil {
+				return nil, fmt.Errorf("ptau: contribution %d: %w", i+1, err)
+			}
+		}
+		if err := checkSubgroupG1([]curve.G1Affine{c.TauG1, c.AlphaG1, c.BetaG1, keys.Tau.SG, keys.Tau.SXG, keys.Alpha.SG, keys.Alpha.SXG, keys.Beta.SG, keys.Beta.SXG}); err != nil {
+			return nil, fmt.Errorf("ptau: contribution %d: %w", i+1, err)
+		}
+		if err := checkSubgroupG2([]curve.G2Affine{c.TauG2, c.BetaG2, keys.Tau.XR, keys.Alpha.XR, keys.Beta.XR}); err != nil {
+			return nil, fmt.Errorf("ptau: contribution %d: %w", i+1, err)
+		}
+
+		c.PartialHash = make([]byte, 216)
+		c.NextChallenge = make([]byte, 64)
+		if _, err := io.ReadFull(r, c.PartialHash); err != nil {
+			return nil, err
+		}
+		if _, err := io.ReadFull(r, c.NextChallenge); err != nil {
+			return nil, err
+		}
+		var paramsLength uint32
+		if err := binary.Read(r, binary.LittleEndian, &c.Type); err != nil {
+			return nil, err
+		}
+		if err := binary.Read(r, binary.LittleEndian, &paramsLength); err != nil {
+			return nil, err
+		}
+		params := make([]byte, paramsLength)
+		if _, err := io.ReadFull(r, params); err != nil {
+			return nil, err
+		}
+		if err := c.readParams(params); err != nil {
+			return nil, fmt.Errorf("ptau: contribution %d: %w", i+1, err)
+		}
+		if c.Type == 1 && c.BeaconHash == nil {
+			return nil, fmt.Errorf("ptau: contribution %d: missing beacon hash", i+1)
+		}
+	}
+
+	return contributions, nil
+}
+
+// readParams parses the optional parameters of a contribution
+func (c *PtauContribution) readParams(params []byte) error {
+	lastType := byte(0)
+	for len(params) > 0 {
+		paramType := params[0]
+		if paramType <= lastType {
+			return errors.New("parameters must be sorted")
+		}
+		lastType = paramType
+		params = params[1:]
+		switch paramType {
+		case 1, 3: // name, beacon hash
+			if len(params) == 0 || len(params) < 1+int(params[0]) {
+				return errors.New("invalid parameter")
+			}
+			if paramType == 1 {
+				c.Name = string(params[1 : 1+params[0]])
+			} else {
+				c.BeaconHash = append([]byte{}, params[1:1+params[0]]...)
+			}
+			params = params[1+params[0]:]
+		case 2: // number of iterations of the beacon
+			if len(params) == 0 || params[0] > 63 {
+				return errors.New("invalid parameter")
+			}
+			c.BeaconIterationsExp = params[0]
+			params = params[1:]
+		default:
+			return fmt.Errorf("unknown parameter %d", paramType)
+		}
+	}
+	return nil
+}
+
+// setPtauFp sets e from the little-endian Montgomery representation used by snarkjs
+func setPtauFp(e *fp.Element, b []byte) error {
+	for i := range e {
+		e[i] = binary.LittleEndian.Uint64(b[8*i:])
+	}
+	// e must be reduced
+	q := fp.Modulus().Bits()
+	for i := len(e) - 1; i >= 0; i-- {
+		if e[i] != uint64(q[i]) {
+			if e[i] > uint64(q[i]) {
+				return errors.New("coordinate is not reduced")
+			}
+			return nil
+		}
+	}
+	return errors.New("coordinate is not reduced")
+}
+
+func setPtauG1(p *curve.G1Affine, b []byte) error {
+	if err := setPtauFp(&p.X, b[:ptauFpSize]); err != nil {
+		return err
+	}
+	return setPtauFp(&p.Y, b[ptauFpSize:])
+}
+
+func setPtauG2(p *curve.G2Affine, b []byte) error {
+	for i, e := range []*fp.Element{&p.X.A0, &p.X.A1, &p.Y.A0, &p.Y.A1} {
+		if err := setPtauFp(e, b[i*ptauFpSize:]); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func checkSubgroupG1(points []curve.G1Affine) error {
+	var failed uint32
+	utils.Parallelize(len(points), func(start, end int) {
+		for i := start; i < end; i++ {
+			if !points[i].IsInSubGroup() {
+				atomic.StoreUint32(&failed, 1)
+				return
+			}
+		}
+	})
+	if atomic.LoadUint32(&failed) != 0 {
+		return errors.New("point not in the correct subgroup")
+	}
+	return nil
+}
+
+func checkSubgroupG2(points []curve.G2Affine) error {
+	var failed uint32
+	utils.Parallelize(len(points), func(start, end int) {
+		for i := start; i < end; i++ {
+			if !points[i].IsInSubGroup() {
+				atomic.StoreUint32(&failed, 1)
+				return
+			}
+		}
+	})
+	if atomic.LoadUint32(&failed) != 0 {
+		return errors.New("point not in the correct subgroup")
+	}
+	return nil
+}
@@ -0,0 +1,248 @@
+// Copyright 2020 ConsenSys Software Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package mpcsetup
+
+import (
+	"crypto/sha256"
+	"encoding/binary"
+	"math/big"
+	"math/bits"
+
+	curve "github.com/consensys/gnark-crypto/ecc/bn254"
+	"github.com/consensys/gnark-crypto/ecc/bn254/fp"
+	"github.com/consensys/gnark-crypto/ecc/bn254/fr"
+	"golang.org/x/crypto/blake2b"
+)
+
+// The functions in this file reproduce the way snarkjs derives the points of
+// the proofs of knowledge of a contribution (ffjavascript ChaCha rng and
+// fromRng functions), so that the .ptau transcripts can be verified.
+
+// ptauRng is the ChaCha20 based generator of ffjavascript.
+type ptauRng struct {
+	state [16]uint32
+	buf   [16]uint32
+	idx   int
+}
+
+func newPtauRng(seed [8]uint32) *ptauRng {
+	r := &ptauRng{idx: 16}
+	r.state[0], r.state[1], r.state[2], r.state[3] = 0x61707865, 0x3320646E, 0x79622D32, 0x6B206574
+	copy(r.state[4:12], seed[:])
+	return r
+}
+
+// newPtauRngFromHash seeds the generator with the first 32 bytes of h, read as
+// big-endian words.
+func newPtauRngFromHash(h []byte) *ptauRng {
+	var seed [8]uint32
+	for i := range seed {
+		seed[i] = binary.BigEndian.Uint32(h[4*i:])
+	}
+	return newPtauRng(seed)
+}
+
+func ptauQuarterRound(s *[16]uint32, a, b, c, d int) {
+	s[a] += s[b]
+	s[d] = bits.RotateLeft32(s[d]^s[a], 16)
+	s[c] += s[d]
+	s[b] = bits.RotateLeft32(s[b]^s[c], 12)
+	s[a] += s[b]
+	s[d] = bits.RotateLeft32(s[d]^s[a], 8)
+	s[c] += s[d]
+	s[b] = bits.RotateLeft32(s[b]^s[c], 7)
+}
+
+func (r *ptauRng) update() {
+	r.buf = r.state
+	for i := 0; i < 10; i++ {
+		ptauQuarterRound(&r.buf, 0, 4, 8, 12)
+		ptauQuarterRound(&r.buf, 1, 5, 9, 13)
+		ptauQuarterRound(&r.buf, 2, 6, 10, 14)
+		ptauQuarterRound(&r.buf, 3, 7, 11, 15)
+		ptauQuarterRound(&r.buf, 0, 5, 10, 15)
+		ptauQuarterRound(&r.buf, 1, 6, 11, 12)
+		ptauQuarterRound(&r.buf, 2, 7, 8, 13)
+		ptauQuarterRound(&r.buf, 3, 4, 9, 14)
+	}
+	for i := range r.buf {
+		r.buf[i] += r.state[i]
+	}
+	r.idx = 0
+	// 128-bit block counter
+	for i := 12; i < 16; i++ {
+		r.state[i]++
+		if r.state[i] != 0 {
+			break
+		}
+	}
+}
+
+func (r *ptauRng) nextU32() uint32 {
+	if r.idx == 16 {
+		r.update()
+	}
+	r.idx++
+	return r.buf[r.idx-1]
+}
+
+func (r *ptauRng) nextU64() uint64 {
+	hi := r.nextU32()
+	return uint64(hi)<<32 | uint64(r.nextU32())
+}
+
+func (r *ptauRng) nextBool() bool {
+	return r.nextU32()&1 == 1
+}
+
+// nextLimbs samples a value below modulus (a 254-bit number) and returns its
+// little-endian limbs. ffjavascript uses the sampled value as the Montgomery
+// form of the field element, as gnark-crypto does with the limbs.
+func (r *ptauRng) nextLimbs(modulus *big.Int) [4]uint64 {
+	var v [4]uint64
+	var b big.Int
+	for {
+		for i := range v {
+			v[i] = r.nextU64()
+		}
+		v[3] &= (1 << 62) - 1
+		words := make([]big.Word, 0, 4)
+		for i := range v {
+			words = append(words, big.Word(v[i]))
+		}
+		if b.SetBits(words).Cmp(modulus) < 0 {
+			return v
+		}
+	}
+}
+
+func (r *ptauRng) fp() fp.Element {
+	return fp.Element(r.nextLimbs(fp.Modulus()))
+}
+
+func (r *ptauRng) fr() fr.Element {
+	return fr.Element(r.nextLimbs(fr.Modulus()))
+}
+
+// g1 returns a random point of G₁, as ffjavascript's G1.fromRng.
+func (r *ptauRng) g1() curve.G1Affine {
+	var p curve.G1Affine
+	var x3b fp.Element
+	var greatest bool
+	for {
+		p.X = r.fp()
+		greatest = r.nextBool()
+		x3b.Square(&p.X).Mul(&x3b, &p.X)
+		x3b.Add(&x3b, new(fp.Element).SetUint64(3))
+		if x3b.Legendre() == 1 {
+			break
+		}
+	}
+	p.Y.Sqrt(&x3b)
+	if p.Y.LexicographicallyLargest() != greatest {
+		p.Y.Neg(&p.Y)
+	}
+	return p
+}
+
+// g2CofactorBn254 is the cofactor of G₂ in the twist.
+var g2CofactorBn254, _ = new(big.Int).SetString("21888242871839275222246405745257275088844257914179612981679871602714643921549", 10)
+
+// g2 returns a random point of G₂, as ffjavascript's G2.fromRng.
+func (r *ptauRng) g2() curve.G2Affine {
+	var b curve.E2
+	b.A0.SetUint64(9)
+	b.A1.SetUint64(1)
+	b.Inverse(&b).MulByElement(&b, new(fp.Element).SetUint64(3))
+
+	var p curve.G2Affine
+	var x3b curve.E2
+	var greatest bool
+	for {
+		p.X.A0 = r.fp()
+		p.X.A1 = r.fp()
+		greatest = r.nextBool()
+		x3b.Square(&p.X).Mul(&x3b, &p.X)
+		x3b.Add(&x3b, &b)
+		if x3b.Legendre() == 1 {
+			break
+		}
+	}
+	p.Y.Sqrt(&x3b)
+	if p.Y.LexicographicallyLargest() != greatest {
+		p.Y.Neg(&p.Y)
+	}
+
+	// clear the cofactor with a plain double-and-add, the GLV based
+	// multiplication only applies to points of G₂.
+	var base, res curve.G2Jac
+	base.FromAffine(&p)
+	for i := g2CofactorBn254.BitLen() - 1; i >= 0; i-- {
+		res.DoubleAssign()
+		if g2CofactorBn254.Bit(i) == 1 {
+			res.AddAssign(&base)
+		}
+	}
+	p.FromJacobian(&res)
+	return p
+}
+
+// ptauKeyR returns the point R ∈ G₂ of the proof of knowledge of x, where
+// sG and sxG are [s]₁ and [s·x]₁. The contribution must publish [x]R.
+func ptauKeyR(personalization byte, challenge []byte, sG, sxG *curve.G1Affine) curve.G2Affine {
+	h, _ := blake2b.New512(nil)
+	h.Write([]byte{personalization})
+	h.Write(challenge)
+	h.Write(ptauUncompressedG1(sG))
+	h.Write(ptauUncompressedG1(sxG))
+	return newPtauRngFromHash(h.Sum(nil)).g2()
+}
+
+// ptauKey is the secret of a contribution
+type ptauKey struct {
+	Tau, Alpha, Beta fr.Element
+}
+
+// newPtauKey derives the secrets and the public keys of a contribution from
+// rng, as snarkjs' createPTauKey.
+func newPtauKey(rng *ptauRng, challenge []byte) (ptauKey, [3]PublicKey) {
+	var key ptauKey
+	key.Tau = rng.fr()
+	key.Alpha = rng.fr()
+	key.Beta = rng.fr()
+
+	var publicKeys [3]PublicKey
+	for i, x := range []*fr.Element{&key.Tau, &key.Alpha, &key.Beta} {
+		var xBi big.Int
+		x.BigInt(&xBi)
+		pk := &publicKeys[i]
+		pk.SG = rng.g1()
+		pk.SXG.ScalarMultiplication(&pk.SG, &xBi)
+		r := ptauKeyR(byte(i), challenge, &pk.SG, &pk.SXG)
+		pk.XR.ScalarMultiplication(&r, &xBi)
+	}
+	return key, publicKeys
+}
+
+// newPtauBeaconRng returns the generator of a random beacon contribution,
+// seeded with beaconHash hashed 2^iterationsExp times with SHA256.
+func newPtauBeaconRng(beaconHash []byte, iterationsExp byte) *ptauRng {
+	h := beaconHash
+	for i := uint64(0); i < 1<<iterationsExp; i++ {
+		d := sha256.Sum256(h)
+		h = d[:]
+	}
+	return newPtauRngFromHash(h)
+}
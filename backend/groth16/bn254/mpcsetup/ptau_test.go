@@ -0,0 +1,327 @@
+// Copyright 2020 ConsenSys Software Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package mpcsetup
+
+import (
+	"bufio"
+	"bytes"
+	"encoding"
+	"encoding/binary"
+	"errors"
+	"fmt"
+	"io/fs"
+	"math/big"
+	"os"
+	"path/filepath"
+	"testing"
+
+	curve "github.com/consensys/gnark-crypto/ecc/bn254"
+	"github.com/consensys/gnark-crypto/ecc/bn254/fp"
+	"github.com/consensys/gnark-crypto/ecc/bn254/fr"
+	"github.com/stretchr/testify/require"
+	"golang.org/x/crypto/blake2b"
+)
+
+func TestReadPtau(t *testing.T) {
+	const power = 3
+	assert := require.New(t)
+
+	srs := InitPhase1(power)
+	var contributions []PtauContribution
+	for i := 0; i < 3; i++ {
+		rng := newPtauRng([8]uint32{uint32(i + 1)})
+		contributions = append(contributions, ptauContribute(&srs, power, contributions, rng, fmt.Sprintf("contributor %d", i+1)))
+	}
+	beacon := newPtauBeaconRng([]byte("beacon"), 2)
+	contributions = append(contributions, ptauContribute(&srs, power, contributions, beacon, "final beacon"))
+	contributions[3].Type = 1
+	contributions[3].BeaconHash = []byte("beacon")
+	contributions[3].BeaconIterationsExp = 2
+
+	phase1, decodedContributions, err := ReadPtau(bytes.NewReader(writePtau(power, &srs, contributions)))
+	assert.NoError(err)
+	assert.Equal(srs.Parameters, phase1.Parameters)
+	assert.Equal(srs.PublicKeys, phase1.PublicKeys)
+	assert.Equal(phase1.hash(), phase1.Hash)
+	assert.Equal(len(contributions), len(decodedContributions))
+	assert.Equal("contributor 3", decodedContributions[2].Name)
+	assert.Equal(uint32(1), decodedContributions[3].Type)
+	assert.Equal([]byte("beacon"), decodedContributions[3].BeaconHash)
+
+	// the transcript can be used for the next phase
+	next := phase1.clone()
+	next.Contribute()
+	assert.NoError(VerifyPhase1(&phase1, &next))
+
+	// tampered powers
+	tampered := srs.clone()
+	tampered.Parameters.G1.Tau[3] = tampered.Parameters.G1.Tau[2]
+	_, _, err = ReadPtau(bytes.NewReader(writePtau(power, &tampered, contributions)))
+	assert.Error(err)
+
+	// broken contribution chain
+	_, _, err = ReadPtau(bytes.NewReader(writePtau(power, &srs, contributions[1:])))
+	assert.Error(err)
+
+	// tampered contributions
+	for name, tamper := range map[string]func(c []PtauContribution){
+		"challenge":      func(c []PtauContribution) { c[1].NextChallenge[0] ^= 1 },
+		"last challenge": func(c []PtauContribution) { c[3].NextChallenge[0] ^= 1 },
+		"partial hash":   func(c []PtauContribution) { c[3].PartialHash[130] ^= 1 },
+		"proof of knowledge": func(c []PtauContribution) {
+			c[1].PublicKeys.Alpha.XR.Double(&c[1].PublicKeys.Alpha.XR)
+		},
+		"alpha": func(c []PtauContribution) { c[1].AlphaG1.Double(&c[1].AlphaG1) },
+		"beacon": func(c []PtauContribution) {
+			c[3].BeaconHash = []byte("another beacon")
+		},
+	} {
+		t.Run(name, func(t *testing.T) {
+			c := make([]PtauContribution, len(contributions))
+			for i := range contributions {
+				c[i] = contributions[i]
+				c[i].PartialHash = append([]byte{}, contributions[i].PartialHash...)
+				c[i].NextChallenge = append([]byte{}, contributions[i].NextChallenge...)
+			}
+			tamper(c)
+			_, _, err := ReadPtau(bytes.NewReader(writePtau(power, &srs, c)))
+			require.Error(t, err)
+		})
+	}
+
+	// point not on the curve
+	buf := writePtau(power, &srs, contributions)
+	offset := bytes.Index(buf, ptauG1Bytes(&srs.Parameters.G1.Tau[1]))
+	buf[offset] ^= 1
+	_, _, err = ReadPtau(bytes.NewReader(buf))
+	assert.Error(err)
+
+	// wrong curve
+	buf = writePtau(power, &srs, contributions)
+	buf[12+12+4] ^= 1
+	_, _, err = ReadPtau(bytes.NewReader(buf))
+	assert.Error(err)
+}
+
+// TestReadPtauSnarkjs reads a transcript generated by snarkjs with
+// testdata/generate.sh: two contributions and a beacon, prepared for phase 2.
+func TestReadPtauSnarkjs(t *testing.T) {
+	f, err := os.Open(filepath.Join("testdata", "pot4_final.ptau"))
+	if errors.Is(err, fs.ErrNotExist) {
+		t.Skip("snarkjs transcript not generated, see testdata/generate.sh")
+	}
+	assert := require.New(t)
+	assert.NoError(err)
+	defer f.Close()
+
+	phase1, contributions, err := ReadPtau(f)
+	assert.NoError(err)
+	assert.Equal(2*(1<<4)-1, len(phase1.Parameters.G1.Tau))
+	assert.Equal(1<<4, len(phase1.Parameters.G2.Tau))
+	assert.Equal(3, len(contributions))
+	assert.Equal("first contribution", contributions[0].Name)
+	assert.Equal("second contribution", contributions[1].Name)
+	assert.Equal(uint32(1), contributions[2].Type)
+
+	// the transcript can be used for the next phase
+	next := phase1.clone()
+	next.Contribute()
+	assert.NoError(VerifyPhase1(&phase1, &next))
+}
+
+func TestPtauFirstChallenges(t *testing.T) {
+	for power := uint32(1); power <= 16; power++ {
+		require.Equal(t, computePtauFirstChallenge(power), ptauFirstChallenge(power), "power %d", power)
+	}
+}
+
+// computePtauFirstChallenge computes the challenge hash given to the first
+// contributor of a ceremony of 2^power powers.
+func computePtauFirstChallenge(power uint32) []byte {
+	_, _, g1, g2 := curve.Generators()
+	N := uint64(1) << power
+
+	h, _ := blake2b.New512(nil)
+	emptyHash := blake2b.Sum512(nil)
+	h.Write(emptyHash[:])
+	w := bufio.NewWriterSize(h, 1<<20)
+	g1Bytes, g2Bytes := ptauUncompressedG1(&g1), ptauUncompressedG2(&g2)
+	for _, s := range []struct {
+		p []byte
+		n uint64
+	}{{g1Bytes, 2*N - 1}, {g2Bytes, N}, {g1Bytes, N}, {g1Bytes, N}, {g2Bytes, 1}} {
+		for i := uint64(0); i < s.n; i++ {
+			w.Write(s.p)
+		}
+	}
+	w.Flush()
+	return h.Sum(nil)
+}
+
+// ptauContribute updates srs as snarkjs' powersoftau contribute command, with
+// the secrets drawn from rng.
+func ptauContribute(srs *Phase1, power uint32, contributions []PtauContribution, rng *ptauRng, name string) PtauContribution {
+	challenge := ptauFirstChallenge(power)
+	if len(contributions) > 0 {
+		challenge = contributions[len(contributions)-1].NextChallenge
+	}
+	key, publicKeys := newPtauKey(rng, challenge)
+
+	N := len(srs.Parameters.G2.Tau)
+	taus := powers(key.Tau, 2*N-1)
+	alphaTau := make([]fr.Element, N)
+	betaTau := make([]fr.Element, N)
+	for i := 0; i < N; i++ {
+		alphaTau[i].Mul(&taus[i], &key.Alpha)
+		betaTau[i].Mul(&taus[i], &key.Beta)
+	}
+	scaleG1InPlace(srs.Parameters.G1.Tau, taus)
+	scaleG2InPlace(srs.Parameters.G2.Tau, taus[0:N])
+	scaleG1InPlace(srs.Parameters.G1.AlphaTau, alphaTau)
+	scaleG1InPlace(srs.Parameters.G1.BetaTau, betaTau)
+	var betaBI big.Int
+	key.Beta.BigInt(&betaBI)
+	srs.Parameters.G2.Beta.ScalarMultiplication(&srs.Parameters.G2.Beta, &betaBI)
+	srs.PublicKeys.Tau, srs.PublicKeys.Alpha, srs.PublicKeys.Beta = publicKeys[0], publicKeys[1], publicKeys[2]
+	srs.Hash = srs.hash()
+
+	c := PtauContribution{
+		TauG1:   srs.Parameters.G1.Tau[1],
+		AlphaG1: srs.Parameters.G1.AlphaTau[0],
+		BetaG1:  srs.Parameters.G1.BetaTau[0],
+		TauG2:   srs.Parameters.G2.Tau[1],
+		BetaG2:  srs.Parameters.G2.Beta,
+		Name:    name,
+	}
+	c.PublicKeys = srs.PublicKeys
+
+	// response hash, whose state before the public keys is the partial hash
+	h, _ := blake2b.New512(nil)
+	h.Write(challenge)
+	writePtauPowers(h, srs, true)
+	state, _ := h.(encoding.BinaryMarshaler).MarshalBinary()
+	state = state[3:]
+	c.PartialHash = make([]byte, 216)
+	copy(c.PartialHash, state[8*8+2*8+1:8*8+2*8+1+blake2b.BlockSize])
+	for i := 0; i < 8; i++ {
+		binary.LittleEndian.PutUint64(c.PartialHash[128+8*i:], binary.BigEndian.Uint64(state[8*i:]))
+	}
+	binary.LittleEndian.PutUint64(c.PartialHash[192:], binary.BigEndian.Uint64(state[8*8:]))
+	binary.LittleEndian.PutUint64(c.PartialHash[200:], uint64(state[len(state)-1]))
+	binary.LittleEndian.PutUint64(c.PartialHash[208:], blake2b.Size)
+	h.Write(ptauPublicKeysBytes(&c.PublicKeys))
+	c.ResponseHash = h.Sum(nil)
+
+	h.Reset()
+	h.Write(c.ResponseHash)
+	writePtauPowers(h, srs, false)
+	c.NextChallenge = h.Sum(nil)
+
+	return c
+}
+
+// writePtau encodes srs in the snarkjs .ptau format
+func writePtau(power uint32, srs *Phase1, contributions []PtauContribution) []byte {
+	var sections [][]byte
+
+	// header
+	var header bytes.Buffer
+	_ = binary.Write(&header, binary.LittleEndian, uint32(fp.Bytes))
+	q := fp.Modulus().Bytes()
+	for i := range q {
+		header.WriteByte(q[len(q)-1-i])
+	}
+	_ = binary.Write(&header, binary.LittleEndian, power)
+	_ = binary.Write(&header, binary.LittleEndian, power)
+	sections = append(sections, header.Bytes())
+
+	g1s := func(points []curve.G1Affine) []byte {
+		var b []byte
+		for i := range points {
+			b = append(b, ptauG1Bytes(&points[i])...)
+		}
+		return b
+	}
+	g2s := func(points []curve.G2Affine) []byte {
+		var b []byte
+		for i := range points {
+			b = append(b, ptauG2Bytes(&points[i])...)
+		}
+		return b
+	}
+	sections = append(sections,
+		g1s(srs.Parameters.G1.Tau),
+		g2s(srs.Parameters.G2.Tau),
+		g1s(srs.Parameters.G1.AlphaTau),
+		g1s(srs.Parameters.G1.BetaTau),
+		g2s([]curve.G2Affine{srs.Parameters.G2.Beta}),
+	)
+
+	// contributions
+	var cb bytes.Buffer
+	_ = binary.Write(&cb, binary.LittleEndian, uint32(len(contributions)))
+	for i := range contributions {
+		c := &contributions[i]
+		k := &c.PublicKeys
+		cb.Write(g1s([]curve.G1Affine{c.TauG1}))
+		cb.Write(g2s([]curve.G2Affine{c.TauG2}))
+		cb.Write(g1s([]curve.G1Affine{c.AlphaG1, c.BetaG1}))
+		cb.Write(g2s([]curve.G2Affine{c.BetaG2}))
+		cb.Write(g1s([]curve.G1Affine{k.Tau.SG, k.Tau.SXG, k.Alpha.SG, k.Alpha.SXG, k.Beta.SG, k.Beta.SXG}))
+		cb.Write(g2s([]curve.G2Affine{k.Tau.XR, k.Alpha.XR, k.Beta.XR}))
+		cb.Write(c.PartialHash)
+		cb.Write(c.NextChallenge)
+		_ = binary.Write(&cb, binary.LittleEndian, c.Type)
+		params := append([]byte{1, byte(len(c.Name))}, c.Name...)
+		if c.Type == 1 {
+			params = append(params, 2, c.BeaconIterationsExp, 3, byte(len(c.BeaconHash)))
+			params = append(params, c.BeaconHash...)
+		}
+		_ = binary.Write(&cb, binary.LittleEndian, uint32(len(params)))
+		cb.Write(params)
+	}
+	sections = append(sections, cb.Bytes())
+
+	var buf bytes.Buffer
+	buf.WriteString("ptau")
+	_ = binary.Write(&buf, binary.LittleEndian, uint32(1))
+	_ = binary.Write(&buf, binary.LittleEndian, uint32(len(sections)))
+	for i, s := range sections {
+		_ = binary.Write(&buf, binary.LittleEndian, uint32(i+1))
+		_ = binary.Write(&buf, binary.LittleEndian, uint64(len(s)))
+		buf.Write(s)
+	}
+	return buf.Bytes()
+}
+
+func ptauFpBytes(e *fp.Element) []byte {
+	b := make([]byte, fp.Bytes)
+	for i := range e {
+		binary.LittleEndian.PutUint64(b[8*i:], e[i])
+	}
+	return b
+}
+
+func ptauG1Bytes(p *curve.G1Affine) []byte {
+	return append(ptauFpBytes(&p.X), ptauFpBytes(&p.Y)...)
+}
+
+func ptauG2Bytes(p *curve.G2Affine) []byte {
+	var b []byte
+	for _, e := range []*fp.Element{&p.X.A0, &p.X.A1, &p.Y.A0, &p.Y.A1} {
+		b = append(b, ptauFpBytes(e)...)
+	}
+	return b
+}
@@ -0,0 +1,15 @@
+#!/bin/sh
+# Generates pot4_final.ptau, the snarkjs transcript read by TestReadPtauSnarkjs
+# and by TestReadPtauSRSSnarkjs in backend/plonk/bn254/mpcsetup: two
+# contributions and a beacon, prepared for phase 2.
+set -e
+cd "$(dirname "$0")"
+tmp=$(mktemp -d)
+trap 'rm -rf "$tmp"' EXIT
+
+snarkjs powersoftau new bn128 4 "$tmp/pot4_0000.ptau"
+snarkjs powersoftau contribute "$tmp/pot4_0000.ptau" "$tmp/pot4_0001.ptau" --name="first contribution" -e="first entropy"
+snarkjs powersoftau contribute "$tmp/pot4_0001.ptau" "$tmp/pot4_0002.ptau" --name="second contribution" -e="second entropy"
+snarkjs powersoftau beacon "$tmp/pot4_0002.ptau" "$tmp/pot4_beacon.ptau" 0102030405060708090a0b0c0d0e0f101112131415161718191a1b1c1d1e1f 10 -n="final beacon"
+snarkjs powersoftau prepare phase2 "$tmp/pot4_beacon.ptau" pot4_final.ptau
+snarkjs powersoftau verify pot4_final.ptau
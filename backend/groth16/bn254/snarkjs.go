@@ -0,0 +1,355 @@
+// Copyright 2020 ConsenSys Software Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package groth16
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"math/big"
+
+	curve "github.com/consensys/gnark-crypto/ecc/bn254"
+	"github.com/consensys/gnark-crypto/ecc/bn254/fp"
+)
+
+// snarkJSProof is the layout of the snarkjs proof.json file. Commitments and
+// CommitmentPok are gnark extensions, ignored by snarkjs.
+type snarkJSProof struct {
+	A             snarkJSG1   `json:"pi_a"`
+	B             snarkJSG2   `json:"pi_b"`
+	C             snarkJSG1   `json:"pi_c"`
+	Protocol      string      `json:"protocol"`
+	Curve         string      `json:"curve"`
+	Commitments   []snarkJSG1 `json:"commitments,omitempty"`
+	CommitmentPok *snarkJSG1  `json:"commitment_pok,omitempty"`
+}
+
+// snarkJSVerifyingKey is the layout of the snarkjs verification_key.json
+// file. CommitmentKey, CommitmentIC and PublicAndCommitmentCommitted are gnark
+// extensions, ignored by snarkjs.
+type snarkJSVerifyingKey struct {
+	Protocol                     string                `json:"protocol"`
+	Curve                        string                `json:"curve"`
+	NPublic                      int                   `json:"nPublic"`
+	Alpha                        snarkJSG1             `json:"vk_alpha_1"`
+	Beta                         snarkJSG2             `json:"vk_beta_2"`
+	Gamma                        snarkJSG2             `json:"vk_gamma_2"`
+	Delta                        snarkJSG2             `json:"vk_delta_2"`
+	AlphaBeta                    [2][3][2]string       `json:"vk_alphabeta_12"`
+	IC                           []snarkJSG1           `json:"IC"`
+	CommitmentKey                *snarkJSCommitmentKey `json:"commitment_key,omitempty"`
+	CommitmentIC                 []snarkJSG1           `json:"commitment_IC,omitempty"`
+	PublicAndCommitmentCommitted [][]int               `json:"public_and_commitment_committed,omitempty"`
+}
+
+type snarkJSCommitmentKey struct {
+	G             snarkJSG2 `json:"g"`
+	GRootSigmaNeg snarkJSG2 `json:"g_root_sigma_neg"`
+}
+
+// snarkJSG1 is a point in projective coordinates (x, y, z) with z ∈ {0, 1}
+type snarkJSG1 [3]string
+
+// snarkJSG2 is a point in projective coordinates (x, y, z) with z ∈ {0, 1},
+// each coordinate being (c0, c1)
+type snarkJSG2 [3][2]string
+
+const (
+	snarkJSProtocol = "groth16"
+	snarkJSCurve    = "bn128"
+)
+
+// ExportSnarkJS writes the proof in the snarkjs proof.json format.
+//
+// The Pedersen commitments of the proof, if any, are written in the
+// "commitments" and "commitment_pok" fields; snarkjs can't verify such proofs.
+func (proof *Proof) ExportSnarkJS(w io.Writer) error {
+	p := snarkJSProof{
+		A:        toSnarkJSG1(&proof.Ar),
+		B:        toSnarkJSG2(&proof.Bs),
+		C:        toSnarkJSG1(&proof.Krs),
+		Protocol: snarkJSProtocol,
+		Curve:    snarkJSCurve,
+	}
+	if len(proof.Commitments) != 0 {
+		p.Commitments = make([]snarkJSG1, len(proof.Commitments))
+		for i := range proof.Commitments {
+			p.Commitments[i] = toSnarkJSG1(&proof.Commitments[i])
+		}
+		pok := toSnarkJSG1(&proof.CommitmentPok)
+		p.CommitmentPok = &pok
+	}
+	enc := json.NewEncoder(w)
+	enc.SetIndent("", " ")
+	return enc.Encode(&p)
+}
+
+// ImportSnarkJS reads a proof in the snarkjs proof.json format, as written by
+// snarkjs or ExportSnarkJS.
+func (proof *Proof) ImportSnarkJS(r io.Reader) error {
+	var p snarkJSProof
+	if err := json.NewDecoder(r).Decode(&p); err != nil {
+		return err
+	}
+	if err := checkSnarkJSHeader(p.Protocol, p.Curve); err != nil {
+		return err
+	}
+
+	var res Proof
+	var err error
+	if res.Ar, err = fromSnarkJSG1(p.A); err != nil {
+		return fmt.Errorf("pi_a: %w", err)
+	}
+	if res.Bs, err = fromSnarkJSG2(p.B); err != nil {
+		return fmt.Errorf("pi_b: %w", err)
+	}
+	if res.Krs, err = fromSnarkJSG1(p.C); err != nil {
+		return fmt.Errorf("pi_c: %w", err)
+	}
+	if len(p.Commitments) != 0 {
+		if p.CommitmentPok == nil {
+			return errors.New("missing commitment_pok")
+		}
+		if res.Commitments, err = fromSnarkJSG1s(p.Commitments); err != nil {
+			return fmt.Errorf("commitments: %w", err)
+		}
+		if res.CommitmentPok, err = fromSnarkJSG1(*p.CommitmentPok); err != nil {
+			return fmt.Errorf("commitment_pok: %w", err)
+		}
+	}
+
+	*proof = res
+	return nil
+}
+
+// ExportSnarkJS writes the verifying key in the snarkjs verification_key.json
+// format.
+//
+// The Pedersen commitment key, if any, is written in the "commitment_key",
+// "commitment_IC" and "public_and_commitment_committed" fields. In that case
+// "IC" holds the public inputs part of [Kvk]₁ only; snarkjs can't verify proofs
+// with commitments.
+func (vk *VerifyingKey) ExportSnarkJS(w io.Writer) error {
+	e, err := curve.Pair([]curve.G1Affine{vk.G1.Alpha}, []curve.G2Affine{vk.G2.Beta})
+	if err != nil {
+		return err
+	}
+
+	nbCommitments := len(vk.PublicAndCommitmentCommitted)
+	nbPublic := len(vk.G1.K) - 1 - nbCommitments
+	v := snarkJSVerifyingKey{
+		Protocol: snarkJSProtocol,
+		Curve:    snarkJSCurve,
+		NPublic:  nbPublic,
+		Alpha:    toSnarkJSG1(&vk.G1.Alpha),
+		Beta:     toSnarkJSG2(&vk.G2.Beta),
+		Gamma:    toSnarkJSG2(&vk.G2.Gamma),
+		Delta:    toSnarkJSG2(&vk.G2.Delta),
+		AlphaBeta: [2][3][2]string{
+			{
+				{e.C0.B0.A0.String(), e.C0.B0.A1.String()},
+				{e.C0.B1.A0.String(), e.C0.B1.A1.String()},
+				{e.C0.B2.A0.String(), e.C0.B2.A1.String()},
+			},
+			{
+				{e.C1.B0.A0.String(), e.C1.B0.A1.String()},
+				{e.C1.B1.A0.String(), e.C1.B1.A1.String()},
+				{e.C1.B2.A0.String(), e.C1.B2.A1.String()},
+			},
+		},
+		IC: make([]snarkJSG1, nbPublic+1),
+	}
+	for i := range v.IC {
+		v.IC[i] = toSnarkJSG1(&vk.G1.K[i])
+	}
+	if nbCommitments != 0 {
+		v.CommitmentKey = &snarkJSCommitmentKey{
+			G:             toSnarkJSG2(&vk.CommitmentKey.G),
+			GRootSigmaNeg: toSnarkJSG2(&vk.CommitmentKey.GRootSigmaNeg),
+		}
+		v.CommitmentIC = make([]snarkJSG1, nbCommitments)
+		for i := range v.CommitmentIC {
+			v.CommitmentIC[i] = toSnarkJSG1(&vk.G1.K[nbPublic+1+i])
+		}
+		v.PublicAndCommitmentCommitted = vk.PublicAndCommitmentCommitted
+	}
+
+	enc := json.NewEncoder(w)
+	enc.SetIndent("", " ")
+	return enc.Encode(&v)
+}
+
+// ImportSnarkJS reads a verifying key in the snarkjs verification_key.json
+// format, as written by snarkjs or ExportSnarkJS.
+//
+// "vk_alphabeta_12" is not read; e(α, β) is recomputed.
+func (vk *VerifyingKey) ImportSnarkJS(r io.Reader) error {
+	var v snarkJSVerifyingKey
+	if err := json.NewDecoder(r).Decode(&v); err != nil {
+		return err
+	}
+	if err := checkSnarkJSHeader(v.Protocol, v.Curve); err != nil {
+		return err
+	}
+	if len(v.IC) != v.NPublic+1 {
+		return fmt.Errorf("IC has %d elements, expected nPublic+1 = %d", len(v.IC), v.NPublic+1)
+	}
+	if len(v.CommitmentIC) != len(v.PublicAndCommitmentCommitted) || (len(v.CommitmentIC) != 0) != (v.CommitmentKey != nil) {
+		return errors.New("inconsistent commitment fields")
+	}
+
+	var res VerifyingKey
+	var err error
+	if res.G1.Alpha, err = fromSnarkJSG1(v.Alpha); err != nil {
+		return fmt.Errorf("vk_alpha_1: %w", err)
+	}
+	if res.G2.Beta, err = fromSnarkJSG2(v.Beta); err != nil {
+		return fmt.Errorf("vk_beta_2: %w", err)
+	}
+	if res.G2.Gamma, err = fromSnarkJSG2(v.Gamma); err != nil {
+		return fmt.Errorf("vk_gamma_2: %w", err)
+	}
+	if res.G2.Delta, err = fromSnarkJSG2(v.Delta); err != nil {
+		return fmt.Errorf("vk_delta_2: %w", err)
+	}
+	if res.G1.K, err = fromSnarkJSG1s(append(v.IC, v.CommitmentIC...)); err != nil {
+		return fmt.Errorf("IC: %w", err)
+	}
+	if v.CommitmentKey != nil {
+		if res.CommitmentKey.G, err = fromSnarkJSG2(v.CommitmentKey.G); err != nil {
+			return fmt.Errorf("commitment_key: %w", err)
+		}
+		if res.CommitmentKey.GRootSigmaNeg, err = fromSnarkJSG2(v.CommitmentKey.GRootSigmaNeg); err != nil {
+			return fmt.Errorf("commitment_key: %w", err)
+		}
+		for _, committed := range v.PublicAndCommitmentCommitted {
+			for _, j := range committed {
+				if j < 1 || j > v.NPublic {
+					return errors.New("public_and_commitment_committed: index out of range")
+				}
+			}
+		}
+		res.PublicAndCommitmentCommitted = v.PublicAndCommitmentCommitted
+	} else {
+		res.PublicAndCommitmentCommitted = [][]int{}
+	}
+
+	if err := res.Precompute(); err != nil {
+		return err
+	}
+	*vk = res
+	return nil
+}
+
+func checkSnarkJSHeader(protocol, curveName string) error {
+	if protocol != snarkJSProtocol {
+		return fmt.Errorf("unsupported protocol %q", protocol)
+	}
+	if curveName != snarkJSCurve {
+		return fmt.Errorf("unsupported curve %q", curveName)
+	}
+	return nil
+}
+
+func toSnarkJSG1(p *curve.G1Affine) snarkJSG1 {
+	if p.IsInfinity() {
+		return snarkJSG1{"0", "1", "0"}
+	}
+	return snarkJSG1{p.X.String(), p.Y.String(), "1"}
+}
+
+func toSnarkJSG2(p *curve.G2Affine) snarkJSG2 {
+	if p.IsInfinity() {
+		return snarkJSG2{{"0", "0"}, {"1", "0"}, {"0", "0"}}
+	}
+	return snarkJSG2{
+		{p.X.A0.String(), p.X.A1.String()},
+		{p.Y.A0.String(), p.Y.A1.String()},
+		{"1", "0"},
+	}
+}
+
+func fromSnarkJSG1(s snarkJSG1) (p curve.G1Affine, err error) {
+	var z fp.Element
+	if err = setSnarkJSFp(&z, s[2]); err != nil {
+		return
+	}
+	if z.IsZero() {
+		return
+	}
+	if !z.IsOne() {
+		return p, errors.New("point must be affine")
+	}
+	if err = setSnarkJSFp(&p.X, s[0]); err != nil {
+		return
+	}
+	if err = setSnarkJSFp(&p.Y, s[1]); err != nil {
+		return
+	}
+	if !p.IsInSubGroup() {
+		return p, errors.New("point not in the correct subgroup")
+	}
+	return
+}
+
+func fromSnarkJSG1s(s []snarkJSG1) ([]curve.G1Affine, error) {
+	points := make([]curve.G1Affine, len(s))
+	for i := range s {
+		var err error
+		if points[i], err = fromSnarkJSG1(s[i]); err != nil {
+			return nil, err
+		}
+	}
+	return points, nil
+}
+
+func fromSnarkJSG2(s snarkJSG2) (p curve.G2Affine, err error) {
+	var z0, z1 fp.Element
+	if err = setSnarkJSFp(&z0, s[2][0]); err != nil {
+		return
+	}
+	if err = setSnarkJSFp(&z1, s[2][1]); err != nil {
+		return
+	}
+	if z0.IsZero() && z1.IsZero() {
+		return
+	}
+	if !z0.IsOne() || !z1.IsZero() {
+		return p, errors.New("point must be affine")
+	}
+	for i, e := range []*fp.Element{&p.X.A0, &p.X.A1, &p.Y.A0, &p.Y.A1} {
+		if err = setSnarkJSFp(e, s[i/2][i%2]); err != nil {
+			return
+		}
+	}
+	if !p.IsInSubGroup() {
+		return p, errors.New("point not in the correct subgroup")
+	}
+	return
+}
+
+// setSnarkJSFp sets e from its decimal representation
+func setSnarkJSFp(e *fp.Element, s string) error {
+	b, ok := new(big.Int).SetString(s, 10)
+	if !ok {
+		return fmt.Errorf("invalid field element %q", s)
+	}
+	if b.Sign() < 0 || b.Cmp(fp.Modulus()) >= 0 {
+		return fmt.Errorf("field element %q is not reduced", s)
+	}
+	e.SetBigInt(b)
+	return nil
+}
@@ -0,0 +1,128 @@
+// Copyright 2020 ConsenSys Software Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package groth16
+
+import (
+	"bytes"
+	"encoding/json"
+	"os"
+	"strings"
+	"testing"
+
+	"github.com/consensys/gnark-crypto/ecc/bn254/fr"
+	cs "github.com/consensys/gnark/constraint/bn254"
+	"github.com/consensys/gnark/frontend"
+	"github.com/consensys/gnark/frontend/cs/r1cs"
+	"github.com/stretchr/testify/require"
+)
+
+// testdata/snarkjs holds a verification key, a proof and its public inputs in
+// the snarkjs layout, for the circuit in constraint/circom/testdata.
+func TestSnarkJSFixtures(t *testing.T) {
+	assert := require.New(t)
+
+	var vk VerifyingKey
+	f, err := os.Open("testdata/snarkjs/verification_key.json")
+	assert.NoError(err)
+	defer f.Close()
+	assert.NoError(vk.ImportSnarkJS(f))
+
+	var proof Proof
+	f, err = os.Open("testdata/snarkjs/proof.json")
+	assert.NoError(err)
+	defer f.Close()
+	assert.NoError(proof.ImportSnarkJS(f))
+
+	data, err := os.ReadFile("testdata/snarkjs/public.json")
+	assert.NoError(err)
+	var public []string
+	assert.NoError(json.Unmarshal(data, &public))
+	publicWitness := make(fr.Vector, len(public))
+	for i := range public {
+		_, err = publicWitness[i].SetString(public[i])
+		assert.NoError(err)
+	}
+
+	assert.NoError(Verify(&proof, &vk, publicWitness))
+	publicWitness[1].SetUint64(3)
+	assert.Error(Verify(&proof, &vk, publicWitness))
+
+	// export gives back the fixtures
+	var buf bytes.Buffer
+	assert.NoError(vk.ExportSnarkJS(&buf))
+	expected, err := os.ReadFile("testdata/snarkjs/verification_key.json")
+	assert.NoError(err)
+	assert.JSONEq(string(expected), buf.String())
+
+	buf.Reset()
+	assert.NoError(proof.ExportSnarkJS(&buf))
+	expected, err = os.ReadFile("testdata/snarkjs/proof.json")
+	assert.NoError(err)
+	assert.JSONEq(string(expected), buf.String())
+}
+
+type snarkJSCommitmentCircuit struct {
+	X, Y frontend.Variable `gnark:",public"`
+	Z    frontend.Variable
+}
+
+func (c *snarkJSCommitmentCircuit) Define(api frontend.API) error {
+	cmt, err := api.(frontend.Committer).Commit(c.X, c.Z)
+	if err != nil {
+		return err
+	}
+	api.AssertIsDifferent(cmt, 0)
+	api.AssertIsEqual(api.Mul(c.X, c.Z), c.Y)
+	return nil
+}
+
+func TestSnarkJSCommitment(t *testing.T) {
+	assert := require.New(t)
+
+	ccs, err := frontend.Compile(fr.Modulus(), r1cs.NewBuilder, &snarkJSCommitmentCircuit{})
+	assert.NoError(err)
+	r1cs := ccs.(*cs.R1CS)
+	var pk ProvingKey
+	var vk VerifyingKey
+	assert.NoError(Setup(r1cs, &pk, &vk))
+
+	w, err := frontend.NewWitness(&snarkJSCommitmentCircuit{X: 3, Y: 15, Z: 5}, fr.Modulus())
+	assert.NoError(err)
+	proof, err := Prove(r1cs, &pk, w)
+	assert.NoError(err)
+	publicWitness, err := w.Public()
+	assert.NoError(err)
+
+	var buf bytes.Buffer
+	assert.NoError(vk.ExportSnarkJS(&buf))
+	assert.Contains(buf.String(), `"nPublic": 2`)
+	var decodedVk VerifyingKey
+	assert.NoError(decodedVk.ImportSnarkJS(&buf))
+	assert.Equal(vk.G1.K, decodedVk.G1.K)
+	assert.Equal(vk.CommitmentKey, decodedVk.CommitmentKey)
+	assert.Equal(vk.PublicAndCommitmentCommitted, decodedVk.PublicAndCommitmentCommitted)
+
+	buf.Reset()
+	assert.NoError(proof.ExportSnarkJS(&buf))
+	var decodedProof Proof
+	assert.NoError(decodedProof.ImportSnarkJS(&buf))
+	assert.Equal(*proof, decodedProof)
+
+	assert.NoError(Verify(&decodedProof, &decodedVk, publicWitness.Vector().(fr.Vector)))
+
+	// invalid inputs
+	assert.Error(decodedProof.ImportSnarkJS(strings.NewReader(`{"protocol": "plonk", "curve": "bn128"}`)))
+	assert.Error(decodedProof.ImportSnarkJS(strings.NewReader(`{"pi_a": ["1", "3", "1"], "protocol": "groth16", "curve": "bn128"}`)))
+}
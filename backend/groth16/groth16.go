@@ -88,7 +88,7 @@ type ProvingKey interface {
 //
 // it's underlying implementation is strongly typed with the curve (see gnark/internal/backend)
 //
-// ExportSolidity is implemented for BN254 and will return an error with other curves
+// ExportSolidity is implemented for BN254 and BLS12-381 and will return an error with other curves
 type VerifyingKey interface {
 	groth16Object
 	gnarkio.UnsafeReaderFrom
@@ -160,6 +160,60 @@ func Verify(proof Proof, vk VerifyingKey, publicWitness witness.Witness, opts ..
 	}
 }
 
+// BatchVerify verifies a batch of proofs generated for the same VerifyingKey,
+// sharing a single multi-pairing between them.
+//
+// If the batch is rejected, the returned error is a *backend.BatchVerifyError
+// holding the index of the first invalid proof.
+func BatchVerify(proofs []Proof, vk VerifyingKey, publicWitnesses []witness.Witness, opts ...backend.VerifierOption) error {
+	switch _vk := vk.(type) {
+	case *groth16_bls12377.VerifyingKey:
+		p, w, err := toCurveTyped[groth16_bls12377.Proof, fr_bls12377.Vector](proofs, publicWitnesses)
+		if err != nil {
+			return err
+		}
+		return groth16_bls12377.BatchVerify(p, _vk, w, opts...)
+	case *groth16_bls12381.VerifyingKey:
+		p, w, err := toCurveTyped[groth16_bls12381.Proof, fr_bls12381.Vector](proofs, publicWitnesses)
+		if err != nil {
+			return err
+		}
+		return groth16_bls12381.BatchVerify(p, _vk, w, opts...)
+	case *groth16_bn254.VerifyingKey:
+		p, w, err := toCurveTyped[groth16_bn254.Proof, fr_bn254.Vector](proofs, publicWitnesses)
+		if err != nil {
+			return err
+		}
+		return groth16_bn254.BatchVerify(p, _vk, w, opts...)
+	case *groth16_bw6761.VerifyingKey:
+		p, w, err := toCurveTyped[groth16_bw6761.Proof, fr_bw6761.Vector](proofs, publicWitnesses)
+		if err != nil {
+			return err
+		}
+		return groth16_bw6761.BatchVerify(p, _vk, w, opts...)
+	case *groth16_bls24317.VerifyingKey:
+		p, w, err := toCurveTyped[groth16_bls24317.Proof, fr_bls24317.Vector](proofs, publicWitnesses)
+		if err != nil {
+			return err
+		}
+		return groth16_bls24317.BatchVerify(p, _vk, w, opts...)
+	case *groth16_bls24315.VerifyingKey:
+		p, w, err := toCurveTyped[groth16_bls24315.Proof, fr_bls24315.Vector](proofs, publicWitnesses)
+		if err != nil {
+			return err
+		}
+		return groth16_bls24315.BatchVerify(p, _vk, w, opts...)
+	case *groth16_bw6633.VerifyingKey:
+		p, w, err := toCurveTyped[groth16_bw6633.Proof, fr_bw6633.Vector](proofs, publicWitnesses)
+		if err != nil {
+			return err
+		}
+		return groth16_bw6633.BatchVerify(p, _vk, w, opts...)
+	default:
+		panic("unrecognized verifying key curve type")
+	}
+}
+
 // Prove runs the groth16.Prove algorithm.
 //
 // if the force flag is set:
@@ -198,6 +252,74 @@ func Prove(r1cs constraint.ConstraintSystem, pk ProvingKey, fullWitness witness.
 	}
 }
 
+// ProveWithStats runs the groth16.Prove algorithm and returns the timings of
+// the proof generation alongside the proof.
+func ProveWithStats(r1cs constraint.ConstraintSystem, pk ProvingKey, fullWitness witness.Witness, opts ...backend.ProverOption) (Proof, backend.ProverStats, error) {
+	var stats backend.ProverStats
+	proof, err := Prove(r1cs, pk, fullWitness, append(opts[:len(opts):len(opts)], backend.WithProverStats(&stats))...)
+	return proof, stats, err
+}
+
+// ProveFromReader runs the groth16.Prove algorithm with a proving key read
+// lazily from pk, which must hold a ProvingKey serialized with WriteRawTo. The
+// multi-exponentiations are computed in chunks of points, whose size is set by
+// backend.WithPKMemoryBudget, so that the proving key doesn't need to fit in
+// memory. The wire values and the quotient polynomial are still held in memory.
+func ProveFromReader(r1cs constraint.ConstraintSystem, pk io.ReaderAt, fullWitness witness.Witness, opts ...backend.ProverOption) (Proof, error) {
+	switch _r1cs := r1cs.(type) {
+	case *cs_bls12377.R1CS:
+		_pk, err := groth16_bls12377.NewProvingKeyReader(pk)
+		if err != nil {
+			return nil, err
+		}
+		return groth16_bls12377.ProveFromReader(_r1cs, _pk, fullWitness, opts...)
+
+	case *cs_bls12381.R1CS:
+		_pk, err := groth16_bls12381.NewProvingKeyReader(pk)
+		if err != nil {
+			return nil, err
+		}
+		return groth16_bls12381.ProveFromReader(_r1cs, _pk, fullWitness, opts...)
+
+	case *cs_bn254.R1CS:
+		_pk, err := groth16_bn254.NewProvingKeyReader(pk)
+		if err != nil {
+			return nil, err
+		}
+		return groth16_bn254.ProveFromReader(_r1cs, _pk, fullWitness, opts...)
+
+	case *cs_bw6761.R1CS:
+		_pk, err := groth16_bw6761.NewProvingKeyReader(pk)
+		if err != nil {
+			return nil, err
+		}
+		return groth16_bw6761.ProveFromReader(_r1cs, _pk, fullWitness, opts...)
+
+	case *cs_bls24317.R1CS:
+		_pk, err := groth16_bls24317.NewProvingKeyReader(pk)
+		if err != nil {
+			return nil, err
+		}
+		return groth16_bls24317.ProveFromReader(_r1cs, _pk, fullWitness, opts...)
+
+	case *cs_bls24315.R1CS:
+		_pk, err := groth16_bls24315.NewProvingKeyReader(pk)
+		if err != nil {
+			return nil, err
+		}
+		return groth16_bls24315.ProveFromReader(_r1cs, _pk, fullWitness, opts...)
+
+	case *cs_bw6633.R1CS:
+		_pk, err := groth16_bw6633.NewProvingKeyReader(pk)
+		if err != nil {
+			return nil, err
+		}
+		return groth16_bw6633.ProveFromReader(_r1cs, _pk, fullWitness, opts...)
+	default:
+		panic("unrecognized R1CS curve type")
+	}
+}
+
 // Setup runs groth16.Setup with provided R1CS and outputs a key pair associated with the circuit.
 //
 // Note that careful consideration must be given to this step in a production environment.
@@ -1,15 +1,28 @@
 package groth16_test
 
 import (
+	"bytes"
+	"context"
+	"errors"
 	"fmt"
 	"math/big"
+	"os"
 	"testing"
 
 	"github.com/consensys/gnark"
 	"github.com/consensys/gnark-crypto/ecc"
 	"github.com/consensys/gnark/backend"
 	"github.com/consensys/gnark/backend/groth16"
+	groth16_bls12377 "github.com/consensys/gnark/backend/groth16/bls12-377"
+	groth16_bls12381 "github.com/consensys/gnark/backend/groth16/bls12-381"
+	groth16_bls24315 "github.com/consensys/gnark/backend/groth16/bls24-315"
+	groth16_bls24317 "github.com/consensys/gnark/backend/groth16/bls24-317"
+	groth16_bn254 "github.com/consensys/gnark/backend/groth16/bn254"
+	groth16_bw6633 "github.com/consensys/gnark/backend/groth16/bw6-633"
+	groth16_bw6761 "github.com/consensys/gnark/backend/groth16/bw6-761"
+	"github.com/consensys/gnark/backend/witness"
 	"github.com/consensys/gnark/constraint"
+	"github.com/consensys/gnark/constraint/solver"
 	"github.com/consensys/gnark/frontend"
 	"github.com/consensys/gnark/frontend/cs/r1cs"
 	"github.com/consensys/gnark/test"
@@ -182,3 +195,277 @@ func getCurves() []ecc.ID {
 	}
 	return gnark.Curves()
 }
+
+func TestBatchVerify(t *testing.T) {
+	assert := test.NewAssert(t)
+	for _, curve := range getCurves() {
+		for _, circuit := range []frontend.Circuit{&squareCircuit{}, &committedSquareCircuit{}} {
+			assert.Run(func(assert *test.Assert) {
+				ccs, err := frontend.Compile(curve.ScalarField(), r1cs.NewBuilder, circuit)
+				assert.NoError(err)
+				pk, vk, err := groth16.Setup(ccs)
+				assert.NoError(err)
+
+				const nbProofs = 3
+				proofs := make([]groth16.Proof, nbProofs)
+				publicWitnesses := make([]witness.Witness, nbProofs)
+				for i := 0; i < nbProofs; i++ {
+					assignment := &committedSquareCircuit{squareCircuit{X: i + 2, Y: (i + 2) * (i + 2)}}
+					w, err := frontend.NewWitness(assignment, curve.ScalarField())
+					assert.NoError(err)
+					proofs[i], err = groth16.Prove(ccs, pk, w)
+					assert.NoError(err)
+					publicWitnesses[i], err = w.Public()
+					assert.NoError(err)
+				}
+				assert.NoError(groth16.BatchVerify(proofs, vk, publicWitnesses))
+
+				// invalid second proof
+				publicWitnesses[1], publicWitnesses[2] = publicWitnesses[2], publicWitnesses[1]
+				err = groth16.BatchVerify(proofs, vk, publicWitnesses)
+				var batchErr *backend.BatchVerifyError
+				assert.True(errors.As(err, &batchErr))
+				assert.Equal(1, batchErr.Index)
+				publicWitnesses[1], publicWitnesses[2] = publicWitnesses[2], publicWitnesses[1]
+
+				// invalid Krs in the last proof, only detected by the folded
+				// pairing check
+				tamperKrs(proofs[2], proofs[0])
+				err = groth16.BatchVerify(proofs, vk, publicWitnesses)
+				assert.True(errors.As(err, &batchErr))
+				assert.Equal(2, batchErr.Index)
+			}, curve.String(), fmt.Sprintf("%T", circuit))
+		}
+	}
+}
+
+// tamperKrs replaces the Krs point of proof by the one of other.
+func tamperKrs(proof, other groth16.Proof) {
+	switch p := proof.(type) {
+	case *groth16_bn254.Proof:
+		p.Krs = other.(*groth16_bn254.Proof).Krs
+	case *groth16_bls12377.Proof:
+		p.Krs = other.(*groth16_bls12377.Proof).Krs
+	case *groth16_bls12381.Proof:
+		p.Krs = other.(*groth16_bls12381.Proof).Krs
+	case *groth16_bls24315.Proof:
+		p.Krs = other.(*groth16_bls24315.Proof).Krs
+	case *groth16_bls24317.Proof:
+		p.Krs = other.(*groth16_bls24317.Proof).Krs
+	case *groth16_bw6761.Proof:
+		p.Krs = other.(*groth16_bw6761.Proof).Krs
+	case *groth16_bw6633.Proof:
+		p.Krs = other.(*groth16_bw6633.Proof).Krs
+	default:
+		panic("unknown proof type")
+	}
+}
+
+func TestProveFromReader(t *testing.T) {
+	assert := test.NewAssert(t)
+	for _, curve := range getCurves() {
+		assert.Run(func(assert *test.Assert) {
+			ccs, err := frontend.Compile(curve.ScalarField(), r1cs.NewBuilder, &committedSquareCircuit{})
+			assert.NoError(err)
+			pk, vk, err := groth16.Setup(ccs)
+			assert.NoError(err)
+			w, err := frontend.NewWitness(&committedSquareCircuit{squareCircuit{X: 3, Y: 9}}, curve.ScalarField())
+			assert.NoError(err)
+			publicWitness, err := w.Public()
+			assert.NoError(err)
+
+			var buf bytes.Buffer
+			_, err = pk.WriteRawTo(&buf)
+			assert.NoError(err)
+			r := bytes.NewReader(buf.Bytes())
+
+			for _, budget := range []uint64{0, 1, 1000} {
+				proof, err := groth16.ProveFromReader(ccs, r, w, backend.WithPKMemoryBudget(budget))
+				assert.NoError(err)
+				assert.NoError(groth16.Verify(proof, vk, publicWitness))
+			}
+
+			// the proving key must not be compressed
+			buf.Reset()
+			_, err = pk.WriteTo(&buf)
+			assert.NoError(err)
+			_, err = groth16.ProveFromReader(ccs, bytes.NewReader(buf.Bytes()), w)
+			assert.Error(err)
+		}, curve.String())
+	}
+}
+
+func TestProveContext(t *testing.T) {
+	assert := test.NewAssert(t)
+	ccs, err := frontend.Compile(ecc.BN254.ScalarField(), r1cs.NewBuilder, &committedSquareCircuit{})
+	assert.NoError(err)
+	pk, vk, err := groth16.Setup(ccs)
+	assert.NoError(err)
+	w, err := frontend.NewWitness(&committedSquareCircuit{squareCircuit{X: 3, Y: 9}}, ecc.BN254.ScalarField())
+	assert.NoError(err)
+
+	ctx, cancel := context.WithCancel(context.Background())
+	proof, err := groth16.Prove(ccs, pk, w, backend.WithContext(ctx))
+	assert.NoError(err)
+	publicWitness, err := w.Public()
+	assert.NoError(err)
+	assert.NoError(groth16.Verify(proof, vk, publicWitness))
+
+	cancel()
+	_, err = groth16.Prove(ccs, pk, w, backend.WithContext(ctx))
+	assert.True(errors.Is(err, context.Canceled), err)
+
+	var buf bytes.Buffer
+	_, err = pk.WriteRawTo(&buf)
+	assert.NoError(err)
+	_, err = groth16.ProveFromReader(ccs, bytes.NewReader(buf.Bytes()), w, backend.WithContext(ctx))
+	assert.True(errors.Is(err, context.Canceled), err)
+
+	// the solver alone
+	_, err = ccs.Solve(w, solver.WithContext(ctx))
+	assert.True(errors.Is(err, context.Canceled), err)
+
+	// cancelled once the multi-exponentiations are scheduled
+	ctx, cancel = context.WithCancel(context.Background())
+	defer cancel()
+	hook := func(phase backend.Phase, done, total int) {
+		if phase == backend.PhaseMSM && done == 0 {
+			cancel()
+		}
+	}
+	_, err = groth16.Prove(ccs, pk, w, backend.WithContext(ctx), backend.WithProgressHook(hook))
+	assert.True(errors.Is(err, context.Canceled), err)
+
+	// nil contexts are rejected
+	_, err = groth16.Prove(ccs, pk, w, backend.WithContext(nil)) //nolint:staticcheck // nil context on purpose
+	assert.Error(err)
+	_, err = ccs.Solve(w, solver.WithContext(nil)) //nolint:staticcheck // nil context on purpose
+	assert.Error(err)
+}
+
+func TestProverProgress(t *testing.T) {
+	assert := test.NewAssert(t)
+	ccs, err := frontend.Compile(ecc.BN254.ScalarField(), r1cs.NewBuilder, &committedSquareCircuit{})
+	assert.NoError(err)
+	pk, _, err := groth16.Setup(ccs)
+	assert.NoError(err)
+	w, err := frontend.NewWitness(&committedSquareCircuit{squareCircuit{X: 3, Y: 9}}, ecc.BN254.ScalarField())
+	assert.NoError(err)
+
+	var buf bytes.Buffer
+	_, err = pk.WriteRawTo(&buf)
+	assert.NoError(err)
+
+	prove := map[string]func(opts ...backend.ProverOption) error{
+		"in-memory": func(opts ...backend.ProverOption) error {
+			_, err := groth16.Prove(ccs, pk, w, opts...)
+			return err
+		},
+		"reader": func(opts ...backend.ProverOption) error {
+			_, err := groth16.ProveFromReader(ccs, bytes.NewReader(buf.Bytes()), w, opts...)
+			return err
+		},
+	}
+	for name, prove := range prove {
+		assert.Run(func(assert *test.Assert) {
+			progress := make(map[backend.Phase][2]int)
+			var stats backend.ProverStats
+			hook := func(phase backend.Phase, done, total int) {
+				assert.True(done <= total && done >= progress[phase][0], "phase %s: %d/%d after %d", phase, done, total, progress[phase][0])
+				progress[phase] = [2]int{done, total}
+			}
+			assert.NoError(prove(backend.WithProgressHook(hook), backend.WithProverStats(&stats)))
+			for _, phase := range []backend.Phase{backend.PhaseSolve, backend.PhaseFFT, backend.PhaseMSM, backend.PhaseQuotient} {
+				assert.True(progress[phase][1] > 0 && progress[phase][0] == progress[phase][1], "phase %s not completed", phase)
+			}
+			assert.True(stats.Total > 0 && stats.Total >= stats.Solve && stats.Total >= stats.MSM)
+		}, name)
+	}
+
+	_, stats, err := groth16.ProveWithStats(ccs, pk, w)
+	assert.NoError(err)
+	assert.True(stats.Total > 0 && stats.Total >= stats.Solve && stats.Total >= stats.MSM)
+}
+
+func TestFingerprint(t *testing.T) {
+	assert := test.NewAssert(t)
+	ccs, err := frontend.Compile(ecc.BN254.ScalarField(), r1cs.NewBuilder, &committedSquareCircuit{})
+	assert.NoError(err)
+	other, err := frontend.Compile(ecc.BN254.ScalarField(), r1cs.NewBuilder, &squareCircuit{})
+	assert.NoError(err)
+	pk, vk, err := groth16.Setup(ccs)
+	assert.NoError(err)
+	w, err := frontend.NewWitness(&committedSquareCircuit{squareCircuit{X: 3, Y: 9}}, ecc.BN254.ScalarField())
+	assert.NoError(err)
+	publicWitness, err := w.Public()
+	assert.NoError(err)
+	fingerprint, err := constraint.Fingerprint(ccs)
+	assert.NoError(err)
+	otherFingerprint, err := constraint.Fingerprint(other)
+	assert.NoError(err)
+
+	// the fingerprint survives serialization
+	var buf bytes.Buffer
+	_, err = pk.WriteRawTo(&buf)
+	assert.NoError(err)
+	rawPk := bytes.NewReader(bytes.Clone(buf.Bytes()))
+	pk = groth16.NewProvingKey(ecc.BN254)
+	_, err = pk.ReadFrom(&buf)
+	assert.NoError(err)
+	buf.Reset()
+	_, err = vk.WriteTo(&buf)
+	assert.NoError(err)
+	vk = groth16.NewVerifyingKey(ecc.BN254)
+	_, err = vk.ReadFrom(&buf)
+	assert.NoError(err)
+
+	proof, err := groth16.Prove(ccs, pk, w)
+	assert.NoError(err)
+	assert.NoError(groth16.Verify(proof, vk, publicWitness, backend.WithVerifierFingerprint(fingerprint)))
+	err = groth16.Verify(proof, vk, publicWitness, backend.WithVerifierFingerprint(otherFingerprint))
+	assert.True(errors.Is(err, constraint.ErrFingerprintMismatch), err)
+
+	_, err = groth16.Prove(other, pk, w)
+	assert.True(errors.Is(err, constraint.ErrFingerprintMismatch), err)
+	_, err = groth16.ProveFromReader(other, rawPk, w)
+	assert.True(errors.Is(err, constraint.ErrFingerprintMismatch), err)
+}
+
+// TestReadKeysWithoutFingerprint reads keys serialized before the fingerprint
+// was added. testdata holds the keys of squareCircuit on BN254.
+func TestReadKeysWithoutFingerprint(t *testing.T) {
+	assert := test.NewAssert(t)
+	ccs, err := frontend.Compile(ecc.BN254.ScalarField(), r1cs.NewBuilder, &squareCircuit{})
+	assert.NoError(err)
+
+	pkBytes, err := os.ReadFile("testdata/groth16_pk.bin")
+	assert.NoError(err)
+	pk := groth16.NewProvingKey(ecc.BN254)
+	n, err := pk.ReadFrom(bytes.NewReader(pkBytes))
+	assert.NoError(err)
+	assert.Equal(int64(len(pkBytes)), n)
+	vkBytes, err := os.ReadFile("testdata/groth16_vk.bin")
+	assert.NoError(err)
+	vk := groth16.NewVerifyingKey(ecc.BN254)
+	n, err = vk.ReadFrom(bytes.NewReader(vkBytes))
+	assert.NoError(err)
+	assert.Equal(int64(len(vkBytes)), n)
+
+	w, err := frontend.NewWitness(&squareCircuit{X: 3, Y: 9}, ecc.BN254.ScalarField())
+	assert.NoError(err)
+	publicWitness, err := w.Public()
+	assert.NoError(err)
+	proof, err := groth16.Prove(ccs, pk, w)
+	assert.NoError(err)
+	assert.NoError(groth16.Verify(proof, vk, publicWitness))
+
+	// the streaming prover reads the raw encoding: without the length of the
+	// empty fingerprint, it is the layout written before it was added.
+	var buf bytes.Buffer
+	_, err = pk.WriteRawTo(&buf)
+	assert.NoError(err)
+	raw := buf.Bytes()[:buf.Len()-4]
+	proof, err = groth16.ProveFromReader(ccs, bytes.NewReader(raw), w)
+	assert.NoError(err)
+	assert.NoError(groth16.Verify(proof, vk, publicWitness))
+}
@@ -0,0 +1,96 @@
+// Package progress reports the progress of the provers to the hook set with
+// backend.WithProgressHook and records the timings of their phases.
+package progress
+
+import (
+	"sync"
+	"time"
+
+	"github.com/consensys/gnark/backend"
+	"github.com/consensys/gnark/constraint/solver"
+)
+
+const nbPhases = int(backend.PhaseQuotient) + 1
+
+type phase struct {
+	start       time.Time
+	took        time.Duration
+	done, total int
+}
+
+// Tracker tracks the phases of a proof generation. It is safe for concurrent
+// use.
+type Tracker struct {
+	hook  func(backend.Phase, int, int)
+	stats *backend.ProverStats
+	start time.Time
+
+	lock   sync.Mutex
+	phases [nbPhases]phase
+}
+
+// New returns a Tracker reporting to the hook and filling the stats of opt.
+func New(opt *backend.ProverConfig) *Tracker {
+	return &Tracker{
+		hook:  opt.ProgressHook,
+		stats: opt.Stats,
+		start: time.Now(),
+	}
+}
+
+// Begin starts phase p, made of total steps.
+func (t *Tracker) Begin(p backend.Phase, total int) {
+	t.Report(p, 0, total)
+}
+
+// Step marks a step of phase p as done.
+func (t *Tracker) Step(p backend.Phase) {
+	t.lock.Lock()
+	defer t.lock.Unlock()
+	t.report(p, t.phases[p].done+1, t.phases[p].total)
+}
+
+// Report sets the progress of phase p to done steps out of total.
+func (t *Tracker) Report(p backend.Phase, done, total int) {
+	t.lock.Lock()
+	defer t.lock.Unlock()
+	t.report(p, done, total)
+}
+
+func (t *Tracker) report(p backend.Phase, done, total int) {
+	ph := &t.phases[p]
+	if ph.start.IsZero() {
+		ph.start = time.Now()
+	}
+	ph.done, ph.total = done, total
+	if done >= total {
+		ph.took = time.Since(ph.start)
+	}
+	if t.hook != nil {
+		t.hook(p, done, total)
+	}
+}
+
+// SolverOption returns a solver option reporting the solved levels of the
+// constraint system as steps of backend.PhaseSolve.
+func (t *Tracker) SolverOption() solver.Option {
+	return solver.WithProgressHook(func(done, total int) {
+		t.Report(backend.PhaseSolve, done, total)
+	})
+}
+
+// Finish fills the stats with the timings of the phases.
+func (t *Tracker) Finish() {
+	if t.stats == nil {
+		return
+	}
+	t.lock.Lock()
+	defer t.lock.Unlock()
+	*t.stats = backend.ProverStats{
+		Solve:    t.phases[backend.PhaseSolve].took,
+		FFT:      t.phases[backend.PhaseFFT].took,
+		MSM:      t.phases[backend.PhaseMSM].took,
+		Quotient: t.phases[backend.PhaseQuotient].took,
+		Total:    time.Since(t.start),
+	}
+}
@@ -19,7 +19,10 @@ package plonk
 import (
 	curve "github.com/consensys/gnark-crypto/ecc/bls12-381"
 
+	"fmt"
 	"github.com/consensys/gnark-crypto/ecc/bls12-381/kzg"
+	"github.com/consensys/gnark/constraint"
+	"github.com/consensys/gnark/internal/utils"
 	"io"
 )
 
@@ -182,10 +185,21 @@ func (vk *VerifyingKey) WriteRawTo(w io.Writer) (int64, error) {
 	return vk.writeTo(w, curve.RawEncoding())
 }
 
+// vkVersionFlag is set in the first word of the serialized verifying keys
+// which start with their layout version. The verifying keys serialized before
+// the versions were introduced start with vk.Size, which never has this bit
+// set, and have no fingerprint.
+const vkVersionFlag = uint64(1) << 63
+
+// vkVersion is the version of the layout written by WriteTo and WriteRawTo:
+//   - 1: the fields of version 0, followed by the fingerprint
+const vkVersion = 1
+
 func (vk *VerifyingKey) writeTo(w io.Writer, options ...func(*curve.Encoder)) (n int64, err error) {
 	enc := curve.NewEncoder(w)
 
 	toEncode := []interface{}{
+		vkVersionFlag | vkVersion,
 		vk.Size,
 		&vk.SizeInv,
 		&vk.Generator,
@@ -213,7 +227,8 @@ func (vk *VerifyingKey) writeTo(w io.Writer, options ...func(*curve.Encoder)) (n
 		}
 	}
 
-	return enc.BytesWritten(), nil
+	m, err := utils.WriteBytes(w, vk.Fingerprint)
+	return enc.BytesWritten() + m, err
 }
 
 // UnsafeReadFrom reads from binary representation in r into VerifyingKey.
@@ -225,8 +240,25 @@ func (vk *VerifyingKey) UnsafeReadFrom(r io.Reader) (int64, error) {
 // ReadFrom reads from binary representation in r into VerifyingKey
 func (vk *VerifyingKey) ReadFrom(r io.Reader) (int64, error) {
 	dec := curve.NewDecoder(r)
+
+	var version uint64
+	if err := dec.Decode(&version); err != nil {
+		return dec.BytesRead(), err
+	}
+	if version&vkVersionFlag == 0 {
+		// version 0: the first word is the size
+		vk.Size, version = version, 0
+	} else {
+		version &^= vkVersionFlag
+		if version > vkVersion {
+			return dec.BytesRead(), fmt.Errorf("unsupported verifying key version %d", version)
+		}
+		if err := dec.Decode(&vk.Size); err != nil {
+			return dec.BytesRead(), err
+		}
+	}
+
 	toDecode := []interface{}{
-		&vk.Size,
 		&vk.SizeInv,
 		&vk.Generator,
 		&vk.NbPublicVariables,
@@ -257,5 +289,15 @@ func (vk *VerifyingKey) ReadFrom(r io.Reader) (int64, error) {
 		vk.Qcp = []kzg.Digest{}
 	}
 
-	return dec.BytesRead(), nil
+	vk.Fingerprint = nil
+	if version == 0 {
+		return dec.BytesRead(), nil
+	}
+	fingerprint, m, err := utils.ReadBytes(r, constraint.FingerprintSize)
+	if err != nil {
+		return dec.BytesRead() + m, err
+	}
+	vk.Fingerprint = fingerprint
+
+	return dec.BytesRead() + m, nil
 }
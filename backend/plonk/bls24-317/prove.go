@@ -42,6 +42,7 @@ import (
 	"github.com/consensys/gnark-crypto/ecc/bls24-317/kzg"
 	fiatshamir "github.com/consensys/gnark-crypto/fiat-shamir"
 	"github.com/consensys/gnark/backend"
+	"github.com/consensys/gnark/backend/internal/progress"
 	"github.com/consensys/gnark/backend/witness"
 
 	"github.com/consensys/gnark/constraint"
@@ -125,11 +126,14 @@ func Prove(spr *cs.SparseR1CS, pk *ProvingKey, fullWitness witness.Witness, opts
 	if err != nil {
 		return nil, fmt.Errorf("get prover options: %w", err)
 	}
+	if err := constraint.CheckFingerprint(spr, pk.Vk.Fingerprint); err != nil {
+		return nil, err
+	}
 
 	start := time.Now()
 
 	// init instance
-	g, ctx := errgroup.WithContext(context.Background())
+	g, ctx := errgroup.WithContext(opt.Ctx)
 	instance, err := newInstance(ctx, spr, pk, fullWitness, &opt)
 	if err != nil {
 		return nil, fmt.Errorf("new instance: %w", err)
@@ -166,21 +170,31 @@ func Prove(spr *cs.SparseR1CS, pk *ProvingKey, fullWitness witness.Witness, opts
 	g.Go(instance.batchOpening)
 
 	if err := g.Wait(); err != nil {
+		if errCtx := opt.Ctx.Err(); errCtx != nil {
+			return nil, errCtx
+		}
 		return nil, err
 	}
 
 	log.Debug().Dur("took", time.Since(start)).Msg("prover done")
+	instance.tracker.Finish()
 	return instance.proof, nil
 }
 
+// nbMultiExps is the number of KZG commitments and openings of the prover,
+// reported as steps of backend.PhaseMSM: [L], [R], [O], [Z], [H₀], [H₁], [H₂],
+// the linearized polynomial, the opening of Z at ωζ and the batch opening at ζ.
+const nbMultiExps = 10
+
 // represents a Prover instance
 type instance struct {
 	ctx context.Context
 
-	pk    *ProvingKey
-	proof *Proof
-	spr   *cs.SparseR1CS
-	opt   *backend.ProverConfig
+	pk      *ProvingKey
+	proof   *Proof
+	spr     *cs.SparseR1CS
+	opt     *backend.ProverConfig
+	tracker *progress.Tracker
 
 	fs             *fiatshamir.Transcript
 	kzgFoldingHash hash.Hash // for KZG folding
@@ -235,6 +249,7 @@ func newInstance(ctx context.Context, spr *cs.SparseR1CS, pk *ProvingKey, fullWi
 		proof:                  &Proof{},
 		spr:                    spr,
 		opt:                    opts,
+		tracker:                progress.New(opts),
 		fullWitness:            fullWitness,
 		bp:                     make([]*iop.Polynomial, nb_blinding_polynomials),
 		fs:                     fiatshamir.NewTranscript(opts.ChallengeHash, "gamma", "beta", "alpha", "zeta"),
@@ -337,7 +352,8 @@ func (s *instance) bsb22Hint(_ *big.Int, ins, outs []*big.Int) error {
 // solveConstraints computes the evaluation of the polynomials L, R, O
 // and sets x[id_L], x[id_R], x[id_O] in canonical form
 func (s *instance) solveConstraints() error {
-	_solution, err := s.spr.Solve(s.fullWitness, s.opt.SolverOpts...)
+	solverOpts := append([]solver.Option{solver.WithContext(s.ctx), s.tracker.SolverOption()}, s.opt.SolverOpts...)
+	_solution, err := s.spr.Solve(s.fullWitness, solverOpts...)
 	if err != nil {
 		return err
 	}
@@ -404,6 +420,7 @@ func (s *instance) commitToLRO() error {
 	case <-s.chbp:
 	}
 
+	s.tracker.Begin(backend.PhaseMSM, nbMultiExps)
 	g := new(errgroup.Group)
 
 	g.Go(func() (err error) {
@@ -466,6 +483,10 @@ func (s *instance) deriveGammaAndBeta() error {
 func (s *instance) commitToPolyAndBlinding(p, b *iop.Polynomial) (commit curve.G1Affine, err error) {
 
 	commit, err = kzg.Commit(p.Coefficients(), s.pk.KzgLagrange)
+	if err != nil {
+		return
+	}
+	s.tracker.Step(backend.PhaseMSM)
 
 	// we add in the blinding contribution
 	n := int(s.domain0.Cardinality)
@@ -548,9 +569,10 @@ func (s *instance) evaluateConstraints() (err error) {
 	if err != nil {
 		return err
 	}
+	s.tracker.Step(backend.PhaseQuotient)
 
 	// commit to h
-	if err := commitToQuotient(s.h1(), s.h2(), s.h3(), s.proof, s.pk.Kzg); err != nil {
+	if err := commitToQuotient(s.h1(), s.h2(), s.h3(), s.proof, s.pk.Kzg, s.tracker); err != nil {
 		return err
 	}
 
@@ -620,6 +642,7 @@ func (s *instance) openZ() (err error) {
 	if err != nil {
 		return err
 	}
+	s.tracker.Step(backend.PhaseMSM)
 	close(s.chZOpening)
 	return nil
 }
@@ -743,6 +766,7 @@ func (s *instance) computeLinearizedPolynomial() error {
 	if err != nil {
 		return err
 	}
+	s.tracker.Step(backend.PhaseMSM)
 	close(s.chLinearizedPolynomial)
 	return nil
 }
@@ -802,8 +826,12 @@ func (s *instance) batchOpening() error {
 		s.pk.Kzg,
 		s.proof.ZShiftedOpening.ClaimedValue.Marshal(),
 	)
+	if err != nil {
+		return err
+	}
+	s.tracker.Step(backend.PhaseMSM)
 
-	return err
+	return nil
 }
 
 // evaluate the full set of constraints, all polynomials in x are back in
@@ -952,7 +980,16 @@ func (s *instance) computeNumerator() (*iop.Polynomial, error) {
 	m := uint64(s.domain1.Cardinality)
 	mm := uint64(64 - bits.TrailingZeros64(m))
 
+	// the polynomials are moved to each coset of the large domain and back, and
+	// the quotient is divided by Xⁿ-1 once evaluated on all the cosets
+	s.tracker.Begin(backend.PhaseFFT, rho+1)
+	s.tracker.Begin(backend.PhaseQuotient, rho+1)
+
 	for i := 0; i < rho; i++ {
+		if err := s.ctx.Err(); err != nil {
+			wgBuf.Wait()
+			return nil, err
+		}
 
 		coset.Mul(&coset, &shifters[i])
 		tmp.Exp(coset, bn).Sub(&tmp, &one)
@@ -1006,6 +1043,7 @@ func (s *instance) computeNumerator() (*iop.Polynomial, error) {
 			// fft in the correct coset
 			p.ToLagrange(s.domain0, nbTasks).ToRegular()
 		})
+		s.tracker.Step(backend.PhaseFFT)
 
 		wgBuf.Wait()
 		if _, err := iop.Evaluate(
@@ -1016,6 +1054,7 @@ func (s *instance) computeNumerator() (*iop.Polynomial, error) {
 		); err != nil {
 			return nil, err
 		}
+		s.tracker.Step(backend.PhaseQuotient)
 		wgBuf.Add(1)
 		go func(i int) {
 			for j := 0; j < int(n); j++ {
@@ -1060,6 +1099,7 @@ func (s *instance) computeNumerator() (*iop.Polynomial, error) {
 		for _, q := range s.bp {
 			scalePowers(q, cs)
 		}
+		s.tracker.Step(backend.PhaseFFT)
 
 		close(s.chRestoreLRO)
 	}()
@@ -1191,21 +1231,27 @@ func coefficients(p []*iop.Polynomial) [][]fr.Element {
 	return res
 }
 
-func commitToQuotient(h1, h2, h3 []fr.Element, proof *Proof, kzgPk kzg.ProvingKey) error {
+func commitToQuotient(h1, h2, h3 []fr.Element, proof *Proof, kzgPk kzg.ProvingKey, tracker *progress.Tracker) error {
 	g := new(errgroup.Group)
 
 	g.Go(func() (err error) {
-		proof.H[0], err = kzg.Commit(h1, kzgPk)
+		if proof.H[0], err = kzg.Commit(h1, kzgPk); err == nil {
+			tracker.Step(backend.PhaseMSM)
+		}
 		return
 	})
 
 	g.Go(func() (err error) {
-		proof.H[1], err = kzg.Commit(h2, kzgPk)
+		if proof.H[1], err = kzg.Commit(h2, kzgPk); err == nil {
+			tracker.Step(backend.PhaseMSM)
+		}
 		return
 	})
 
 	g.Go(func() (err error) {
-		proof.H[2], err = kzg.Commit(h3, kzgPk)
+		if proof.H[2], err = kzg.Commit(h3, kzgPk); err == nil {
+			tracker.Step(backend.PhaseMSM)
+		}
 		return
 	})
 
@@ -55,6 +55,11 @@ type VerifyingKey struct {
 	Qcp                []kzg.Digest
 
 	CommitmentConstraintIndexes []uint64
+
+	// Fingerprint of the constraint system, see constraint.Fingerprint. If set,
+	// Prove rejects the constraint systems with a different fingerprint, and
+	// Verify checks it when backend.WithVerifierFingerprint is set.
+	Fingerprint []byte
 }
 
 // Trace stores a plonk trace as columns
@@ -92,6 +97,11 @@ func Setup(spr *cs.SparseR1CS, srs, srsLagrange kzg.SRS) (*ProvingKey, *Verifyin
 	var vk VerifyingKey
 	pk.Vk = &vk
 	vk.CommitmentConstraintIndexes = internal.IntSliceToUint64Slice(spr.CommitmentInfo.CommitmentIndexes())
+	fingerprint, err := constraint.Fingerprint(spr)
+	if err != nil {
+		return nil, nil, err
+	}
+	vk.Fingerprint = fingerprint
 
 	// step 0: set the fft domains
 	domain := initFFTDomain(spr)
@@ -0,0 +1,44 @@
+// Copyright 2020 ConsenSys Software Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package mpcsetup
+
+import (
+	"io"
+
+	"github.com/consensys/gnark-crypto/ecc/bn254/kzg"
+	groth16 "github.com/consensys/gnark/backend/groth16/bn254/mpcsetup"
+)
+
+// ReadPtau reads a Powers of Tau transcript in the snarkjs binary format
+// (.ptau). The transcript is checked as described in groth16 mpcsetup.ReadPtau.
+//
+// The KZG SRS for plonk.Setup is then obtained with ExtractSRS.
+func ReadPtau(r io.ReadSeeker) (PowersOfTau, error) {
+	phase1, _, err := groth16.ReadPtau(r)
+	if err != nil {
+		return PowersOfTau{}, err
+	}
+	return ImportPhase1(&phase1), nil
+}
+
+// ReadPtauSRS reads a .ptau transcript and returns the KZG SRS in canonical
+// and Lagrange form, with the sizes returned by plonk.SRSSize.
+func ReadPtauSRS(r io.ReadSeeker, sizeCanonical, sizeLagrange uint64) (canonical, lagrange *kzg.SRS, err error) {
+	p, err := ReadPtau(r)
+	if err != nil {
+		return nil, nil, err
+	}
+	return p.ExtractSRS(sizeCanonical, sizeLagrange)
+}
@@ -0,0 +1,58 @@
+// Copyright 2020 ConsenSys Software Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package mpcsetup
+
+import (
+	"errors"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"testing"
+
+	curve "github.com/consensys/gnark-crypto/ecc/bn254"
+	"github.com/consensys/gnark/backend/plonk"
+	cs "github.com/consensys/gnark/constraint/bn254"
+	"github.com/consensys/gnark/frontend"
+	"github.com/consensys/gnark/frontend/cs/scs"
+	"github.com/stretchr/testify/require"
+)
+
+// TestReadPtauSRSSnarkjs proves with the SRS of the snarkjs transcript
+// generated by backend/groth16/bn254/mpcsetup/testdata/generate.sh.
+func TestReadPtauSRSSnarkjs(t *testing.T) {
+	f, err := os.Open(filepath.Join("..", "..", "..", "groth16", "bn254", "mpcsetup", "testdata", "pot4_final.ptau"))
+	if errors.Is(err, fs.ErrNotExist) {
+		t.Skip("snarkjs transcript not generated, see backend/groth16/bn254/mpcsetup/testdata/generate.sh")
+	}
+	assert := require.New(t)
+	assert.NoError(err)
+	defer f.Close()
+
+	ccs, err := frontend.Compile(curve.ID.ScalarField(), scs.NewBuilder, &Circuit{})
+	assert.NoError(err)
+	sizeCanonical, sizeLagrange := plonk.SRSSize(ccs.(*cs.SparseR1CS).System)
+	canonical, lagrange, err := ReadPtauSRS(f, uint64(sizeCanonical), uint64(sizeLagrange))
+	assert.NoError(err)
+
+	pk, vk, err := plonk.Setup(ccs, canonical, lagrange)
+	assert.NoError(err)
+	witness, err := frontend.NewWitness(&Circuit{X: 3, Y: 9}, curve.ID.ScalarField())
+	assert.NoError(err)
+	pubWitness, err := witness.Public()
+	assert.NoError(err)
+	proof, err := plonk.Prove(ccs, pk, witness)
+	assert.NoError(err)
+	assert.NoError(plonk.Verify(proof, vk, pubWitness))
+}
@@ -20,6 +20,7 @@
 package plonk
 
 import (
+	"errors"
 	"io"
 
 	"github.com/consensys/gnark-crypto/ecc"
@@ -157,6 +158,14 @@ func Prove(ccs constraint.ConstraintSystem, pk ProvingKey, fullWitness witness.W
 	}
 }
 
+// ProveWithStats runs Prove and returns the timings of the proof generation
+// alongside the proof.
+func ProveWithStats(ccs constraint.ConstraintSystem, pk ProvingKey, fullWitness witness.Witness, opts ...backend.ProverOption) (Proof, backend.ProverStats, error) {
+	var stats backend.ProverStats
+	proof, err := Prove(ccs, pk, fullWitness, append(opts[:len(opts):len(opts)], backend.WithProverStats(&stats))...)
+	return proof, stats, err
+}
+
 // Verify verifies a PLONK proof, from the proof, preprocessed public data, and public witness.
 func Verify(proof Proof, vk VerifyingKey, publicWitness witness.Witness, opts ...backend.VerifierOption) error {
 
@@ -216,6 +225,80 @@ func Verify(proof Proof, vk VerifyingKey, publicWitness witness.Witness, opts ..
 	}
 }
 
+// BatchVerify verifies a batch of proofs generated for the same VerifyingKey,
+// folding their KZG openings into a single multi-pairing.
+//
+// If the batch is rejected, the returned error is a *backend.BatchVerifyError
+// holding the index of the first invalid proof.
+func BatchVerify(proofs []Proof, vk VerifyingKey, publicWitnesses []witness.Witness, opts ...backend.VerifierOption) error {
+	switch _vk := vk.(type) {
+	case *plonk_bls12377.VerifyingKey:
+		p, w, err := toCurveTyped[plonk_bls12377.Proof, fr_bls12377.Vector](proofs, publicWitnesses)
+		if err != nil {
+			return err
+		}
+		return plonk_bls12377.BatchVerify(p, _vk, w, opts...)
+	case *plonk_bls12381.VerifyingKey:
+		p, w, err := toCurveTyped[plonk_bls12381.Proof, fr_bls12381.Vector](proofs, publicWitnesses)
+		if err != nil {
+			return err
+		}
+		return plonk_bls12381.BatchVerify(p, _vk, w, opts...)
+	case *plonk_bn254.VerifyingKey:
+		p, w, err := toCurveTyped[plonk_bn254.Proof, fr_bn254.Vector](proofs, publicWitnesses)
+		if err != nil {
+			return err
+		}
+		return plonk_bn254.BatchVerify(p, _vk, w, opts...)
+	case *plonk_bw6761.VerifyingKey:
+		p, w, err := toCurveTyped[plonk_bw6761.Proof, fr_bw6761.Vector](proofs, publicWitnesses)
+		if err != nil {
+			return err
+		}
+		return plonk_bw6761.BatchVerify(p, _vk, w, opts...)
+	case *plonk_bls24317.VerifyingKey:
+		p, w, err := toCurveTyped[plonk_bls24317.Proof, fr_bls24317.Vector](proofs, publicWitnesses)
+		if err != nil {
+			return err
+		}
+		return plonk_bls24317.BatchVerify(p, _vk, w, opts...)
+	case *plonk_bls24315.VerifyingKey:
+		p, w, err := toCurveTyped[plonk_bls24315.Proof, fr_bls24315.Vector](proofs, publicWitnesses)
+		if err != nil {
+			return err
+		}
+		return plonk_bls24315.BatchVerify(p, _vk, w, opts...)
+	case *plonk_bw6633.VerifyingKey:
+		p, w, err := toCurveTyped[plonk_bw6633.Proof, fr_bw6633.Vector](proofs, publicWitnesses)
+		if err != nil {
+			return err
+		}
+		return plonk_bw6633.BatchVerify(p, _vk, w, opts...)
+	default:
+		panic("unrecognized verifying key type")
+	}
+}
+
+// toCurveTyped asserts the proofs and public witnesses have the expected
+// concrete types.
+func toCurveTyped[P any, V any](proofs []Proof, publicWitnesses []witness.Witness) ([]*P, []V, error) {
+	p := make([]*P, len(proofs))
+	for i := range proofs {
+		var ok bool
+		if p[i], ok = any(proofs[i]).(*P); !ok {
+			return nil, nil, errors.New("proofs and verifying key are not on the same curve")
+		}
+	}
+	w := make([]V, len(publicWitnesses))
+	for i := range publicWitnesses {
+		var ok bool
+		if w[i], ok = any(publicWitnesses[i].Vector()).(V); !ok {
+			return nil, nil, witness.ErrInvalidWitness
+		}
+	}
+	return p, w, nil
+}
+
 // NewCS instantiate a concrete curved-typed SparseR1CS and return a ConstraintSystem interface
 // This method exists for (de)serialization purposes
 func NewCS(curveID ecc.ID) constraint.ConstraintSystem {
@@ -2,8 +2,11 @@ package plonk_test
 
 import (
 	"bytes"
+	"context"
+	"errors"
 	"fmt"
 	"math/big"
+	"os"
 	"testing"
 
 	"github.com/consensys/gnark"
@@ -11,6 +14,14 @@ import (
 	"github.com/consensys/gnark-crypto/kzg"
 	"github.com/consensys/gnark/backend"
 	"github.com/consensys/gnark/backend/plonk"
+	plonk_bls12377 "github.com/consensys/gnark/backend/plonk/bls12-377"
+	plonk_bls12381 "github.com/consensys/gnark/backend/plonk/bls12-381"
+	plonk_bls24315 "github.com/consensys/gnark/backend/plonk/bls24-315"
+	plonk_bls24317 "github.com/consensys/gnark/backend/plonk/bls24-317"
+	plonk_bn254 "github.com/consensys/gnark/backend/plonk/bn254"
+	plonk_bw6633 "github.com/consensys/gnark/backend/plonk/bw6-633"
+	plonk_bw6761 "github.com/consensys/gnark/backend/plonk/bw6-761"
+	"github.com/consensys/gnark/backend/witness"
 	"github.com/consensys/gnark/constraint"
 	"github.com/consensys/gnark/frontend"
 	"github.com/consensys/gnark/frontend/cs/scs"
@@ -19,6 +30,71 @@ import (
 	"github.com/stretchr/testify/require"
 )
 
+func TestBatchVerify(t *testing.T) {
+	assert := test.NewAssert(t)
+	for _, curve := range getCurves() {
+		assert.Run(func(assert *test.Assert) {
+			ccs, err := frontend.Compile(curve.ScalarField(), scs.NewBuilder, &committedSquareCircuit{})
+			assert.NoError(err)
+			srs, srsLagrange, err := unsafekzg.NewSRS(ccs)
+			assert.NoError(err)
+			pk, vk, err := plonk.Setup(ccs, srs, srsLagrange)
+			assert.NoError(err)
+
+			const nbProofs = 3
+			proofs := make([]plonk.Proof, nbProofs)
+			publicWitnesses := make([]witness.Witness, nbProofs)
+			for i := 0; i < nbProofs; i++ {
+				w, err := frontend.NewWitness(&committedSquareCircuit{X: i + 2, Y: (i + 2) * (i + 2)}, curve.ScalarField())
+				assert.NoError(err)
+				proofs[i], err = plonk.Prove(ccs, pk, w)
+				assert.NoError(err)
+				publicWitnesses[i], err = w.Public()
+				assert.NoError(err)
+			}
+			assert.NoError(plonk.BatchVerify(proofs, vk, publicWitnesses))
+
+			// invalid second proof
+			publicWitnesses[1], publicWitnesses[2] = publicWitnesses[2], publicWitnesses[1]
+			err = plonk.BatchVerify(proofs, vk, publicWitnesses)
+			var batchErr *backend.BatchVerifyError
+			assert.True(errors.As(err, &batchErr))
+			assert.Equal(1, batchErr.Index)
+			publicWitnesses[1], publicWitnesses[2] = publicWitnesses[2], publicWitnesses[1]
+
+			// invalid opening of the last proof, only detected by the folded
+			// KZG check
+			tamperOpening(proofs[2], proofs[0])
+			err = plonk.BatchVerify(proofs, vk, publicWitnesses)
+			assert.True(errors.As(err, &batchErr))
+			assert.Equal(2, batchErr.Index)
+		}, curve.String())
+	}
+}
+
+// tamperOpening replaces the opening proof of Z at μζ of proof by the one of
+// other.
+func tamperOpening(proof, other plonk.Proof) {
+	switch p := proof.(type) {
+	case *plonk_bn254.Proof:
+		p.ZShiftedOpening.H = other.(*plonk_bn254.Proof).ZShiftedOpening.H
+	case *plonk_bls12377.Proof:
+		p.ZShiftedOpening.H = other.(*plonk_bls12377.Proof).ZShiftedOpening.H
+	case *plonk_bls12381.Proof:
+		p.ZShiftedOpening.H = other.(*plonk_bls12381.Proof).ZShiftedOpening.H
+	case *plonk_bls24315.Proof:
+		p.ZShiftedOpening.H = other.(*plonk_bls24315.Proof).ZShiftedOpening.H
+	case *plonk_bls24317.Proof:
+		p.ZShiftedOpening.H = other.(*plonk_bls24317.Proof).ZShiftedOpening.H
+	case *plonk_bw6761.Proof:
+		p.ZShiftedOpening.H = other.(*plonk_bw6761.Proof).ZShiftedOpening.H
+	case *plonk_bw6633.Proof:
+		p.ZShiftedOpening.H = other.(*plonk_bw6633.Proof).ZShiftedOpening.H
+	default:
+		panic("unknown proof type")
+	}
+}
+
 //--------------------//
 //     benches		  //
 //--------------------//
@@ -320,6 +396,21 @@ func (c *smallCircuit) Define(api frontend.API) error {
 	return nil
 }
 
+type committedSquareCircuit struct {
+	X frontend.Variable
+	Y frontend.Variable `gnark:",public"`
+}
+
+func (c *committedSquareCircuit) Define(api frontend.API) error {
+	cmt, err := api.(frontend.Committer).Commit(c.X)
+	if err != nil {
+		return err
+	}
+	api.AssertIsDifferent(cmt, 0)
+	api.AssertIsEqual(api.Mul(c.X, c.X), c.Y)
+	return nil
+}
+
 type constantHash struct{}
 
 func (h constantHash) Write(p []byte) (n int, err error) { return len(p), nil }
@@ -334,3 +425,142 @@ func getCurves() []ecc.ID {
 	}
 	return gnark.Curves()
 }
+
+func TestProveContext(t *testing.T) {
+	assert := test.NewAssert(t)
+	ccs, err := frontend.Compile(ecc.BN254.ScalarField(), scs.NewBuilder, &committedSquareCircuit{})
+	assert.NoError(err)
+	srs, srsLagrange, err := unsafekzg.NewSRS(ccs)
+	assert.NoError(err)
+	pk, vk, err := plonk.Setup(ccs, srs, srsLagrange)
+	assert.NoError(err)
+	w, err := frontend.NewWitness(&committedSquareCircuit{X: 3, Y: 9}, ecc.BN254.ScalarField())
+	assert.NoError(err)
+
+	ctx, cancel := context.WithCancel(context.Background())
+	proof, err := plonk.Prove(ccs, pk, w, backend.WithContext(ctx))
+	assert.NoError(err)
+	publicWitness, err := w.Public()
+	assert.NoError(err)
+	assert.NoError(plonk.Verify(proof, vk, publicWitness))
+
+	cancel()
+	_, err = plonk.Prove(ccs, pk, w, backend.WithContext(ctx))
+	assert.True(errors.Is(err, context.Canceled), err)
+}
+
+func TestProverProgress(t *testing.T) {
+	assert := test.NewAssert(t)
+	ccs, err := frontend.Compile(ecc.BN254.ScalarField(), scs.NewBuilder, &committedSquareCircuit{})
+	assert.NoError(err)
+	srs, srsLagrange, err := unsafekzg.NewSRS(ccs)
+	assert.NoError(err)
+	pk, _, err := plonk.Setup(ccs, srs, srsLagrange)
+	assert.NoError(err)
+	w, err := frontend.NewWitness(&committedSquareCircuit{X: 3, Y: 9}, ecc.BN254.ScalarField())
+	assert.NoError(err)
+
+	progress := make(map[backend.Phase][2]int)
+	hook := func(phase backend.Phase, done, total int) {
+		assert.True(done <= total && done >= progress[phase][0], "phase %s: %d/%d after %d", phase, done, total, progress[phase][0])
+		progress[phase] = [2]int{done, total}
+	}
+	_, stats, err := plonk.ProveWithStats(ccs, pk, w, backend.WithProgressHook(hook))
+	assert.NoError(err)
+	for _, phase := range []backend.Phase{backend.PhaseSolve, backend.PhaseFFT, backend.PhaseMSM, backend.PhaseQuotient} {
+		assert.True(progress[phase][1] > 0 && progress[phase][0] == progress[phase][1], "phase %s not completed", phase)
+	}
+	assert.True(stats.Total > 0 && stats.Total >= stats.Solve && stats.Total >= stats.MSM)
+}
+
+func TestFingerprint(t *testing.T) {
+	assert := test.NewAssert(t)
+	ccs, err := frontend.Compile(ecc.BN254.ScalarField(), scs.NewBuilder, &committedSquareCircuit{})
+	assert.NoError(err)
+	other, err := frontend.Compile(ecc.BN254.ScalarField(), scs.NewBuilder, &smallCircuit{})
+	assert.NoError(err)
+	srs, srsLagrange, err := unsafekzg.NewSRS(ccs)
+	assert.NoError(err)
+	pk, vk, err := plonk.Setup(ccs, srs, srsLagrange)
+	assert.NoError(err)
+	w, err := frontend.NewWitness(&committedSquareCircuit{X: 3, Y: 9}, ecc.BN254.ScalarField())
+	assert.NoError(err)
+	publicWitness, err := w.Public()
+	assert.NoError(err)
+	fingerprint, err := constraint.Fingerprint(ccs)
+	assert.NoError(err)
+	otherFingerprint, err := constraint.Fingerprint(other)
+	assert.NoError(err)
+
+	// the fingerprint survives serialization
+	var buf bytes.Buffer
+	_, err = pk.WriteTo(&buf)
+	assert.NoError(err)
+	pk = plonk.NewProvingKey(ecc.BN254)
+	_, err = pk.ReadFrom(&buf)
+	assert.NoError(err)
+	buf.Reset()
+	_, err = vk.WriteTo(&buf)
+	assert.NoError(err)
+	vk = plonk.NewVerifyingKey(ecc.BN254)
+	_, err = vk.ReadFrom(&buf)
+	assert.NoError(err)
+
+	proof, err := plonk.Prove(ccs, pk, w)
+	assert.NoError(err)
+	assert.NoError(plonk.Verify(proof, vk, publicWitness, backend.WithVerifierFingerprint(fingerprint)))
+	err = plonk.Verify(proof, vk, publicWitness, backend.WithVerifierFingerprint(otherFingerprint))
+	assert.True(errors.Is(err, constraint.ErrFingerprintMismatch), err)
+
+	_, err = plonk.Prove(other, pk, w)
+	assert.True(errors.Is(err, constraint.ErrFingerprintMismatch), err)
+}
+
+// TestReadKeysWithoutFingerprint reads keys serialized before the verifying
+// key layout was versioned. testdata holds the keys of a squaring circuit on
+// BN254.
+func TestReadKeysWithoutFingerprint(t *testing.T) {
+	assert := test.NewAssert(t)
+	ccs, err := frontend.Compile(ecc.BN254.ScalarField(), scs.NewBuilder, &squareCircuit{})
+	assert.NoError(err)
+
+	pkBytes, err := os.ReadFile("testdata/plonk_pk.bin")
+	assert.NoError(err)
+	pk := plonk.NewProvingKey(ecc.BN254)
+	n, err := pk.ReadFrom(bytes.NewReader(pkBytes))
+	assert.NoError(err)
+	assert.Equal(int64(len(pkBytes)), n)
+	vkBytes, err := os.ReadFile("testdata/plonk_vk.bin")
+	assert.NoError(err)
+	vk := plonk.NewVerifyingKey(ecc.BN254)
+	n, err = vk.ReadFrom(bytes.NewReader(vkBytes))
+	assert.NoError(err)
+	assert.Equal(int64(len(vkBytes)), n)
+
+	w, err := frontend.NewWitness(&squareCircuit{X: 3, Y: 9}, ecc.BN254.ScalarField())
+	assert.NoError(err)
+	publicWitness, err := w.Public()
+	assert.NoError(err)
+	proof, err := plonk.Prove(ccs, pk, w)
+	assert.NoError(err)
+	assert.NoError(plonk.Verify(proof, vk, publicWitness))
+
+	// written again with the current layout
+	var buf bytes.Buffer
+	_, err = vk.WriteTo(&buf)
+	assert.NoError(err)
+	vk = plonk.NewVerifyingKey(ecc.BN254)
+	_, err = vk.ReadFrom(&buf)
+	assert.NoError(err)
+	assert.NoError(plonk.Verify(proof, vk, publicWitness))
+}
+
+type squareCircuit struct {
+	X frontend.Variable
+	Y frontend.Variable `gnark:",public"`
+}
+
+func (c *squareCircuit) Define(api frontend.API) error {
+	api.AssertIsEqual(api.Mul(c.X, c.X), c.Y)
+	return nil
+}
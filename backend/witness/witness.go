@@ -43,6 +43,7 @@ import (
 	"encoding/json"
 	"errors"
 	"fmt"
+	"hash"
 	"io"
 	"math/big"
 	"reflect"
@@ -159,6 +160,44 @@ func (w *witness) Public() (Witness, error) {
 	}, nil
 }
 
+// HashPublicInputs returns the witness of the circuit compiled with the option
+// frontend.WithPublicInputHashing: its only public value is the digest of the
+// public values of w computed with h, reduced modulo the field, followed by the
+// public and secret values of w as secret values. The public values are
+// written to h as big-endian bytes.
+func HashPublicInputs(w Witness, h hash.Hash) (Witness, error) {
+	public, err := w.Public()
+	if err != nil {
+		return nil, err
+	}
+	nbPublic := reflect.ValueOf(public.Vector()).Len()
+	vector := w.Vector()
+	n := reflect.ValueOf(vector).Len()
+	v := resize(vector, n+1)
+	h.Reset()
+	i := 0
+	for e := range iterate(vector) {
+		if i < nbPublic {
+			h.Write(e.(interface{ Marshal() []byte }).Marshal())
+		}
+		if err == nil {
+			err = set(v, i+1, e)
+		}
+		i++
+	}
+	if err != nil {
+		return nil, err
+	}
+	if err := set(v, 0, new(big.Int).SetBytes(h.Sum(nil))); err != nil {
+		return nil, err
+	}
+	return &witness{
+		vector:   v,
+		nbPublic: 1,
+		nbSecret: uint32(n),
+	}, nil
+}
+
 func (w *witness) WriteTo(wr io.Writer) (n int64, err error) {
 	// write number of public, number of secret
 	if err := binary.Write(wr, binary.BigEndian, w.nbPublic); err != nil {
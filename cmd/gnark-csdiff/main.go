@@ -0,0 +1,67 @@
+// Command gnark-csdiff compares two serialized constraint systems and reports
+// their differences per source location.
+//
+// Usage:
+//
+//	gnark-csdiff old.ccs new.ccs
+//
+// The constraint systems must have been serialized with their WriteTo method.
+// The exit status is 0 if no difference was found, 1 if the constraint systems
+// differ and 2 on error.
+package main
+
+import (
+	"flag"
+	"fmt"
+	"os"
+
+	"github.com/consensys/gnark/constraint"
+	"github.com/consensys/gnark/constraint/diff"
+)
+
+func main() {
+	flag.Usage = func() {
+		fmt.Fprintf(flag.CommandLine.Output(), "usage: %s old.ccs new.ccs\n", os.Args[0])
+		flag.PrintDefaults()
+	}
+	flag.Parse()
+	if flag.NArg() != 2 {
+		flag.Usage()
+		os.Exit(2)
+	}
+
+	before, err := readConstraintSystem(flag.Arg(0))
+	if err != nil {
+		fail(err)
+	}
+	after, err := readConstraintSystem(flag.Arg(1))
+	if err != nil {
+		fail(err)
+	}
+
+	report := diff.Compare(before, after)
+	if _, err := report.WriteTo(os.Stdout); err != nil {
+		fail(err)
+	}
+	if !report.Empty() {
+		os.Exit(1)
+	}
+}
+
+func readConstraintSystem(path string) (constraint.ConstraintSystem, error) {
+	f, err := os.Open(path)
+	if err != nil {
+		return nil, err
+	}
+	defer f.Close()
+	ccs, err := diff.ReadConstraintSystem(f)
+	if err != nil {
+		return nil, fmt.Errorf("%s: %w", path, err)
+	}
+	return ccs, nil
+}
+
+func fail(err error) {
+	fmt.Fprintln(os.Stderr, err)
+	os.Exit(2)
+}
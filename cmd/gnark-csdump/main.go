@@ -0,0 +1,71 @@
+// Command gnark-csdump writes a serialized constraint system as a human
+// readable listing or as a Graphviz DOT dependency graph.
+//
+// Usage:
+//
+//	gnark-csdump [-format text|dot] [-o output] circuit.ccs
+//
+// The constraint system must have been serialized with its WriteTo method.
+package main
+
+import (
+	"flag"
+	"fmt"
+	"io"
+	"os"
+
+	"github.com/consensys/gnark/constraint"
+	"github.com/consensys/gnark/constraint/diff"
+)
+
+func main() {
+	format := flag.String("format", "text", "output format: text or dot")
+	output := flag.String("o", "", "output file (default: standard output)")
+	flag.Usage = func() {
+		fmt.Fprintf(flag.CommandLine.Output(), "usage: %s [flags] circuit.ccs\n", os.Args[0])
+		flag.PrintDefaults()
+	}
+	flag.Parse()
+	if flag.NArg() != 1 {
+		flag.Usage()
+		os.Exit(2)
+	}
+
+	var write func(io.Writer, constraint.ConstraintSystem) error
+	switch *format {
+	case "text":
+		write = constraint.WriteText
+	case "dot":
+		write = constraint.WriteDOT
+	default:
+		fail(fmt.Errorf("unknown format %q", *format))
+	}
+
+	f, err := os.Open(flag.Arg(0))
+	if err != nil {
+		fail(err)
+	}
+	ccs, err := diff.ReadConstraintSystem(f)
+	f.Close()
+	if err != nil {
+		fail(fmt.Errorf("%s: %w", flag.Arg(0), err))
+	}
+
+	w := os.Stdout
+	if *output != "" {
+		if w, err = os.Create(*output); err != nil {
+			fail(err)
+		}
+	}
+	if err := write(w, ccs); err != nil {
+		fail(err)
+	}
+	if err := w.Close(); err != nil {
+		fail(err)
+	}
+}
+
+func fail(err error) {
+	fmt.Fprintln(os.Stderr, err)
+	os.Exit(1)
+}
@@ -17,6 +17,7 @@
 package cs
 
 import (
+	"context"
 	"errors"
 	"fmt"
 	"github.com/consensys/gnark-crypto/ecc"
@@ -24,6 +25,7 @@ import (
 	"github.com/consensys/gnark/constraint"
 	csolver "github.com/consensys/gnark/constraint/solver"
 	"github.com/rs/zerolog"
+	"io"
 	"math"
 	"math/big"
 	"strconv"
@@ -50,6 +52,16 @@ type solver struct {
 	logger  zerolog.Logger
 	nbTasks int
 
+	// checked between levels
+	ctx context.Context
+	// called between levels, may be nil
+	progress func(done, total int)
+
+	// records the solved wires, may be nil
+	trace       *csolver.Trace
+	traceWriter io.Writer
+	traced      []int // wires solved by the current instruction
+
 	a, b, c fr.Vector // R1CS solver will compute the a,b,c matrices
 
 	q *big.Int
@@ -104,6 +116,10 @@ func newSolver(cs *system, witness fr.Vector, opts ...csolver.Option) (*solver,
 		mHintsFunctions: hintFunctions,
 		logger:          opt.Logger,
 		nbTasks:         opt.NbTasks,
+		ctx:             opt.Ctx,
+		progress:        opt.ProgressHook,
+		trace:           opt.Trace,
+		traceWriter:     opt.TraceWriter,
 		q:               cs.Field(),
 	}
 
@@ -121,6 +137,16 @@ func newSolver(cs *system, witness fr.Vector, opts ...csolver.Option) (*solver,
 	// to ensure we instantiated all wires
 	s.nbSolved += uint64(len(witness) + witnessOffset)
 
+	if s.trace != nil {
+		// the instructions are recorded in solving order
+		s.nbTasks = 1
+		values := make([]string, len(witness)+witnessOffset)
+		for i := range values {
+			values[i] = s.values[i].String()
+		}
+		cs.TraceInputs(s.trace, values)
+	}
+
 	if s.Type == constraint.SystemR1CS {
 		n := ecc.NextPowerOfTwo(uint64(cs.GetNbConstraints()))
 		s.a = make(fr.Vector, cs.GetNbConstraints(), n)
@@ -138,6 +164,20 @@ func (s *solver) set(id int, value fr.Element) {
 	s.values[id] = value
 	s.solved[id] = true
 	atomic.AddUint64(&s.nbSolved, 1)
+	if s.trace != nil {
+		s.traced = append(s.traced, id)
+	}
+}
+
+// traceInstruction records the instruction iID and the wires it solved in the
+// trace.
+func (s *solver) traceInstruction(iID int, err error) {
+	values := make([]string, len(s.traced))
+	for i, w := range s.traced {
+		values[i] = s.values[w].String()
+	}
+	s.TraceInstruction(s.trace, iID, s.traced, values, err)
+	s.traced = s.traced[:0]
 }
 
 // computeTerm computes coeff*variable
@@ -468,7 +508,13 @@ func (solver *solver) run() error {
 	var scratch scratch
 
 	// for each level, we push the tasks
-	for _, level := range solver.Levels {
+	for l, level := range solver.Levels {
+		if err := solver.ctx.Err(); err != nil {
+			return err
+		}
+		if solver.progress != nil {
+			solver.progress(l, len(solver.Levels))
+		}
 
 		// max CPU to use
 		maxCPU := float64(len(level)) / minWorkPerCPU
@@ -476,7 +522,11 @@ func (solver *solver) run() error {
 		if maxCPU <= 1.0 || solver.nbTasks == 1 {
 			// we do it sequentially
 			for _, i := range level {
-				if err := solver.processInstruction(solver.Instructions[i], &scratch); err != nil {
+				err := solver.processInstruction(solver.Instructions[i], &scratch)
+				if solver.trace != nil {
+					solver.traceInstruction(i, err)
+				}
+				if err != nil {
 					return err
 				}
 			}
@@ -523,6 +573,9 @@ func (solver *solver) run() error {
 			return <-chError
 		}
 	}
+	if solver.progress != nil {
+		solver.progress(len(solver.Levels), len(solver.Levels))
+	}
 
 	if int(solver.nbSolved) != len(solver.values) {
 		return errors.New("solver didn't assign a value to all wires")
@@ -80,6 +80,7 @@ func TestSerialization(t *testing.T) {
 						"field",
 						"CoeffTable.mCoeffs",
 						"System.lbWireLevel",
+						"System.fingerprint",
 						"System.genericHint",
 						"System.SymbolTable",
 						"System.bitLen")); diff != "" {
@@ -89,6 +89,15 @@ func (cs *system) Solve(witness witness.Witness, opts ...csolver.Option) (any, e
 	// (or sooner, if a constraint is not satisfied)
 	defer solver.printLogs(cs.Logs)
 
+	// write the trace once the solver is done, whether it succeeded or not
+	if solver.traceWriter != nil {
+		defer func() {
+			if err := solver.trace.WriteJSON(solver.traceWriter); err != nil {
+				log.Err(err).Msg("writing solver trace")
+			}
+		}()
+	}
+
 	// run it.
 	if err := solver.run(); err != nil {
 		log.Err(err).Send()
@@ -195,6 +204,10 @@ func (cs *system) ReadFrom(r io.Reader) (int64, error) {
 		cs.CommitmentInfo = *v
 	}
 
+	if err := constraint.StoreFingerprint(cs); err != nil {
+		return int64(decoder.NumBytesRead()), err
+	}
+
 	return int64(decoder.NumBytesRead()), nil
 }
 
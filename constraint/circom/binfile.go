@@ -0,0 +1,133 @@
+package circom
+
+import (
+	"encoding/binary"
+	"errors"
+	"fmt"
+	"io"
+	"math/big"
+
+	"github.com/consensys/gnark-crypto/ecc/bn254/fr"
+)
+
+// readSections reads a file in the iden3 binary format (used by .r1cs and
+// .wtns files) and returns its sections.
+func readSections(r io.Reader, magic string, versions ...uint32) (map[uint32][]byte, error) {
+	var header [12]byte
+	if _, err := io.ReadFull(r, header[:]); err != nil {
+		return nil, err
+	}
+	if string(header[:4]) != magic {
+		return nil, fmt.Errorf("invalid magic number, expected %q", magic)
+	}
+	version := binary.LittleEndian.Uint32(header[4:])
+	supported := false
+	for _, v := range versions {
+		supported = supported || v == version
+	}
+	if !supported {
+		return nil, fmt.Errorf("unsupported %s version %d", magic, version)
+	}
+	nbSections := binary.LittleEndian.Uint32(header[8:])
+
+	sections := make(map[uint32][]byte, nbSections)
+	for i := uint32(0); i < nbSections; i++ {
+		var sectionHeader [12]byte
+		if _, err := io.ReadFull(r, sectionHeader[:]); err != nil {
+			return nil, err
+		}
+		sectionType := binary.LittleEndian.Uint32(sectionHeader[:])
+		size := binary.LittleEndian.Uint64(sectionHeader[4:])
+		if _, ok := sections[sectionType]; ok {
+			return nil, fmt.Errorf("duplicate section %d", sectionType)
+		}
+		// read progressively so that a corrupted size doesn't allocate the whole memory
+		var buf []byte
+		for remaining := size; remaining > 0; {
+			n := remaining
+			if n > 1<<24 {
+				n = 1 << 24
+			}
+			chunk := make([]byte, n)
+			if _, err := io.ReadFull(r, chunk); err != nil {
+				return nil, err
+			}
+			buf = append(buf, chunk...)
+			remaining -= n
+		}
+		sections[sectionType] = buf
+	}
+	return sections, nil
+}
+
+// decoder reads little-endian values from a section
+type decoder struct {
+	buf []byte
+	err error
+}
+
+func (d *decoder) next(n int) []byte {
+	if d.err != nil {
+		return nil
+	}
+	if len(d.buf) < n {
+		d.err = io.ErrUnexpectedEOF
+		return nil
+	}
+	b := d.buf[:n]
+	d.buf = d.buf[n:]
+	return b
+}
+
+func (d *decoder) uint32() uint32 {
+	b := d.next(4)
+	if b == nil {
+		return 0
+	}
+	return binary.LittleEndian.Uint32(b)
+}
+
+func (d *decoder) uint64() uint64 {
+	b := d.next(8)
+	if b == nil {
+		return 0
+	}
+	return binary.LittleEndian.Uint64(b)
+}
+
+// element reads a field element in canonical little-endian form
+func (d *decoder) element() fr.Element {
+	b := d.next(fr.Bytes)
+	if b == nil {
+		return fr.Element{}
+	}
+	e, err := fr.LittleEndian.Element((*[fr.Bytes]byte)(b))
+	if err != nil {
+		d.err = err
+	}
+	return e
+}
+
+// checkField reads the field description of a header and checks it is the
+// BN254 scalar field.
+func (d *decoder) checkField() error {
+	n8 := d.uint32()
+	if d.err != nil {
+		return d.err
+	}
+	if n8 != fr.Bytes {
+		return errors.New("unsupported field: only the BN254 scalar field is supported")
+	}
+	b := d.next(fr.Bytes)
+	if d.err != nil {
+		return d.err
+	}
+	q := make([]byte, len(b))
+	for i := range b {
+		q[len(b)-1-i] = b[i]
+	}
+	if new(big.Int).SetBytes(q).Cmp(fr.Modulus()) != 0 {
+		return errors.New("unsupported field: only the BN254 scalar field is supported")
+	}
+	return nil
+}
@@ -0,0 +1,121 @@
+package circom_test
+
+import (
+	"bytes"
+	"encoding/binary"
+	"os"
+	"testing"
+
+	"github.com/consensys/gnark-crypto/ecc/bn254/fr"
+	"github.com/consensys/gnark/backend/groth16"
+	"github.com/consensys/gnark/backend/witness"
+	"github.com/consensys/gnark/constraint"
+	cs "github.com/consensys/gnark/constraint/bn254"
+	"github.com/consensys/gnark/constraint/circom"
+	"github.com/stretchr/testify/require"
+)
+
+// testdata/example.r1cs is the constraint system of testdata/example.circom, and
+// testdata/example.wtns its witness for the inputs of testdata/example.json.
+
+func readExample(t *testing.T) (constraint.ConstraintSystem, witness.Witness) {
+	assert := require.New(t)
+
+	f, err := os.Open("testdata/example.r1cs")
+	assert.NoError(err)
+	defer f.Close()
+	ccs, err := circom.ReadR1CS(f)
+	assert.NoError(err)
+
+	f, err = os.Open("testdata/example.wtns")
+	assert.NoError(err)
+	defer f.Close()
+	w, err := circom.ReadWitness(f, ccs)
+	assert.NoError(err)
+
+	return ccs, w
+}
+
+func TestReadR1CS(t *testing.T) {
+	assert := require.New(t)
+	ccs, w := readExample(t)
+
+	assert.Equal(2, ccs.GetNbConstraints())
+	assert.Equal(3, ccs.GetNbPublicVariables())
+	assert.Equal(3, ccs.GetNbSecretVariables())
+	assert.Equal(0, ccs.GetNbInternalVariables())
+
+	// public witness is {out, a}
+	publicWitness, err := w.Public()
+	assert.NoError(err)
+	assert.Equal(fr.Vector{fr.NewElement(29), fr.NewElement(2)}, publicWitness.Vector())
+
+	_, err = ccs.Solve(w)
+	assert.NoError(err)
+
+	// serialization round trip
+	var buf bytes.Buffer
+	_, err = ccs.WriteTo(&buf)
+	assert.NoError(err)
+	var decoded cs.R1CS
+	_, err = decoded.ReadFrom(&buf)
+	assert.NoError(err)
+	assert.Equal(ccs.(constraint.R1CS).GetR1Cs(), decoded.GetR1Cs())
+	_, err = decoded.Solve(w)
+	assert.NoError(err)
+
+	// wrong witness
+	values := w.Vector().(fr.Vector)
+	values[len(values)-1].SetUint64(7)
+	_, err = ccs.Solve(w)
+	assert.Error(err)
+}
+
+func TestGroth16(t *testing.T) {
+	assert := require.New(t)
+	ccs, w := readExample(t)
+
+	pk, vk, err := groth16.Setup(ccs)
+	assert.NoError(err)
+	proof, err := groth16.Prove(ccs, pk, w)
+	assert.NoError(err)
+	publicWitness, err := w.Public()
+	assert.NoError(err)
+	assert.NoError(groth16.Verify(proof, vk, publicWitness))
+
+	// wrong public input
+	publicWitness.Vector().(fr.Vector)[1].SetUint64(3)
+	assert.Error(groth16.Verify(proof, vk, publicWitness))
+}
+
+func TestReadInvalid(t *testing.T) {
+	assert := require.New(t)
+
+	data, err := os.ReadFile("testdata/example.r1cs")
+	assert.NoError(err)
+
+	// truncated
+	_, err = circom.ReadR1CS(bytes.NewReader(data[:len(data)-1]))
+	assert.Error(err)
+
+	// wrong field
+	corrupted := append([]byte{}, data...)
+	corrupted[12+12+4] ^= 1
+	_, err = circom.ReadR1CS(bytes.NewReader(corrupted))
+	assert.Error(err)
+
+	// number of constraints larger than the constraints section
+	corrupted = append([]byte{}, data...)
+	binary.LittleEndian.PutUint32(corrupted[12+12+4+32+4*4+8:], 1<<31)
+	_, err = circom.ReadR1CS(bytes.NewReader(corrupted))
+	assert.ErrorContains(err, "more constraints")
+
+	// witness for a different constraint system
+	ccs := cs.NewR1CS(0)
+	ccs.AddPublicVariable("1")
+	f, err := os.Open("testdata/example.wtns")
+	assert.NoError(err)
+	defer f.Close()
+	_, err = circom.ReadWitness(f, ccs)
+	assert.Error(err)
+}
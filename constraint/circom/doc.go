@@ -0,0 +1,14 @@
+// Package circom reads the constraint systems (.r1cs) and witnesses (.wtns)
+// produced by circom and snarkjs.
+//
+// The imported constraint system can be used with groth16.Setup and
+// groth16.Prove like a constraint system compiled with gnark's frontend.
+//
+// circom computes the value of all the signals of a circuit in its witness
+// generator, and the .wtns file holds all of them. Hence, the constraint system
+// has no internal variable: the private inputs and the intermediate signals of
+// the circom circuit are all secret variables, and solving the constraint
+// system only checks that the constraints are satisfied.
+//
+// Only the BN254 scalar field is supported.
+package circom
@@ -0,0 +1,122 @@
+package circom
+
+import (
+	"errors"
+	"fmt"
+	"io"
+
+	"github.com/consensys/gnark/constraint"
+	cs "github.com/consensys/gnark/constraint/bn254"
+)
+
+// sections of the .r1cs format
+const (
+	r1csSectionHeader      = 1
+	r1csSectionConstraints = 2
+	r1csSectionWireToLabel = 3
+	r1csSectionCustomGates = 4
+)
+
+// ReadR1CS reads a constraint system in the circom .r1cs format.
+//
+// The wires of the circom circuit keep their index: wire 0 is the constant
+// one, followed by the public outputs, the public inputs, the private inputs
+// and the intermediate signals. The public outputs and inputs are public
+// variables, all the other signals are secret variables.
+func ReadR1CS(r io.Reader) (constraint.ConstraintSystem, error) {
+	sections, err := readSections(r, "r1cs", 1)
+	if err != nil {
+		return nil, err
+	}
+	if _, ok := sections[r1csSectionCustomGates]; ok {
+		return nil, errors.New("circom custom gates are not supported")
+	}
+
+	// header
+	buf, ok := sections[r1csSectionHeader]
+	if !ok {
+		return nil, errors.New("missing header section")
+	}
+	d := decoder{buf: buf}
+	if err := d.checkField(); err != nil {
+		return nil, err
+	}
+	nbWires := d.uint32()
+	nbPublicOutputs := d.uint32()
+	nbPublicInputs := d.uint32()
+	nbPrivateInputs := d.uint32()
+	_ = d.uint64() // number of labels
+	nbConstraints := d.uint32()
+	if d.err != nil {
+		return nil, fmt.Errorf("invalid header: %w", d.err)
+	}
+	nbPublic := 1 + uint64(nbPublicOutputs) + uint64(nbPublicInputs)
+	if nbPublic+uint64(nbPrivateInputs) > uint64(nbWires) {
+		return nil, errors.New("invalid header: more inputs than wires")
+	}
+
+	// the capacity is bounded by the size of the constraints section, where
+	// each constraint takes at least the three lengths of its linear
+	// expressions, so that a corrupted header doesn't allocate the whole memory
+	constraintsSection, ok := sections[r1csSectionConstraints]
+	if !ok {
+		return nil, errors.New("missing constraints section")
+	}
+	if uint64(nbConstraints)*12 > uint64(len(constraintsSection)) {
+		return nil, errors.New("invalid header: more constraints than the constraints section holds")
+	}
+
+	ccs := cs.NewR1CS(int(nbConstraints))
+	ccs.AddPublicVariable("1")
+	for i := uint64(1); i < nbPublic; i++ {
+		ccs.AddPublicVariable(wireName(i, nbPublicOutputs))
+	}
+	for i := nbPublic; i < uint64(nbWires); i++ {
+		ccs.AddSecretVariable(wireName(i, nbPublicOutputs))
+	}
+	bID := ccs.AddBlueprint(&constraint.BlueprintGenericR1C{})
+
+	// constraints
+	d = decoder{buf: constraintsSection}
+	readLinearExpression := func() constraint.LinearExpression {
+		n := d.uint32()
+		if uint64(n) > uint64(len(d.buf)) {
+			d.err = io.ErrUnexpectedEOF
+			return nil
+		}
+		l := make(constraint.LinearExpression, 0, n)
+		for i := uint32(0); i < n && d.err == nil; i++ {
+			wireID := d.uint32()
+			coeff := d.element()
+			if wireID >= nbWires {
+				d.err = fmt.Errorf("wire %d out of range", wireID)
+			}
+			l = append(l, ccs.MakeTerm(ccs.FromInterface(coeff), int(wireID)))
+		}
+		return l
+	}
+	for i := uint32(0); i < nbConstraints; i++ {
+		var r1c constraint.R1C
+		r1c.L = readLinearExpression()
+		r1c.R = readLinearExpression()
+		r1c.O = readLinearExpression()
+		if d.err != nil {
+			return nil, fmt.Errorf("constraint %d: %w", i, d.err)
+		}
+		// circom constraints are A*B-C = 0
+		ccs.AddR1C(r1c, bID)
+	}
+	if len(d.buf) != 0 {
+		return nil, errors.New("unexpected data after the constraints")
+	}
+
+	return ccs, nil
+}
+
+// wireName returns the name of the variable of the given circom wire
+func wireName(wireID uint64, nbPublicOutputs uint32) string {
+	if wireID <= uint64(nbPublicOutputs) {
+		return fmt.Sprintf("out%d", wireID-1)
+	}
+	return fmt.Sprintf("w%d", wireID)
+}
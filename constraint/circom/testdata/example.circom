@@ -0,0 +1,14 @@
+pragma circom 2.0.0;
+
+template Example() {
+    signal input a;
+    signal input b;
+    signal input c;
+    signal t;
+    signal output out;
+
+    t <== a * b;
+    out <== t * c + 5;
+}
+
+component main {public [a]} = Example();
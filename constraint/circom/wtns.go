@@ -0,0 +1,90 @@
+package circom
+
+import (
+	"errors"
+	"fmt"
+	"io"
+
+	"github.com/consensys/gnark-crypto/ecc/bn254/fr"
+	"github.com/consensys/gnark/backend/witness"
+	"github.com/consensys/gnark/constraint"
+)
+
+// sections of the .wtns format
+const (
+	wtnsSectionHeader = 1
+	wtnsSectionValues = 2
+)
+
+// ReadWitness reads a witness in the snarkjs .wtns format, for the constraint
+// system ccs obtained with ReadR1CS.
+//
+// The returned witness holds the values of all the circom signals but the
+// constant one; its public part is obtained with witness.Public().
+func ReadWitness(r io.Reader, ccs constraint.ConstraintSystem) (witness.Witness, error) {
+	sections, err := readSections(r, "wtns", 1, 2)
+	if err != nil {
+		return nil, err
+	}
+
+	// header
+	buf, ok := sections[wtnsSectionHeader]
+	if !ok {
+		return nil, errors.New("missing header section")
+	}
+	d := decoder{buf: buf}
+	if err := d.checkField(); err != nil {
+		return nil, err
+	}
+	nbValues := d.uint32()
+	if d.err != nil {
+		return nil, fmt.Errorf("invalid header: %w", d.err)
+	}
+	nbPublic, nbSecret := ccs.GetNbPublicVariables(), ccs.GetNbSecretVariables()
+	if ccs.GetNbInternalVariables() != 0 || int(nbValues) != nbPublic+nbSecret {
+		return nil, fmt.Errorf("witness has %d values, the constraint system %d wires", nbValues, nbPublic+nbSecret+ccs.GetNbInternalVariables())
+	}
+
+	// values
+	buf, ok = sections[wtnsSectionValues]
+	if !ok {
+		return nil, errors.New("missing values section")
+	}
+	if len(buf) != int(nbValues)*fr.Bytes {
+		return nil, errors.New("invalid values section size")
+	}
+	d = decoder{buf: buf}
+	values := make(fr.Vector, nbValues)
+	for i := range values {
+		values[i] = d.element()
+	}
+	if d.err != nil {
+		return nil, d.err
+	}
+	if !values[0].IsOne() {
+		return nil, errors.New("the first value of the witness must be 1")
+	}
+
+	w, err := witness.New(ccs.Field())
+	if err != nil {
+		return nil, err
+	}
+	// done stops the feeder if Fill returns before consuming all the values
+	ch := make(chan any)
+	done := make(chan struct{})
+	defer close(done)
+	go func() {
+		defer close(ch)
+		for i := 1; i < len(values); i++ {
+			select {
+			case ch <- values[i]:
+			case <-done:
+				return
+			}
+		}
+	}()
+	if err := w.Fill(nbPublic-1, nbSecret, ch); err != nil {
+		return nil, err
+	}
+	return w, nil
+}
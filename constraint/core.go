@@ -127,6 +127,10 @@ type System struct {
 	GkrInfo        GkrInfo
 
 	genericHint BlueprintID
+
+	// fingerprint of the complete system, see StoreFingerprint. It is dropped
+	// when the system is modified.
+	fingerprint []byte `cbor:"-"`
 }
 
 // NewSystem initialize the common structure among constraint system
@@ -161,8 +165,14 @@ func (system *System) GetInstruction(id int) Instruction {
 	return system.Instructions[id].Unpack(system)
 }
 
+// GetInstructionBlueprint returns the blueprint of the instruction at index id
+func (system *System) GetInstructionBlueprint(id int) Blueprint {
+	return system.Blueprints[system.Instructions[id].BlueprintID]
+}
+
 // AddBlueprint adds a blueprint to the system and returns its ID
 func (system *System) AddBlueprint(b Blueprint) BlueprintID {
+	system.fingerprint = nil
 	system.Blueprints = append(system.Blueprints, b)
 	return BlueprintID(len(system.Blueprints) - 1)
 }
@@ -207,6 +217,7 @@ func (system *System) CheckSerializationHeader() error {
 	}
 	system.q = new(big.Int).Set(scalarField)
 	system.bitLen = system.q.BitLen()
+	system.fingerprint = nil
 	return nil
 }
 
@@ -226,6 +237,7 @@ func (system *System) FieldBitLen() int {
 
 func (system *System) AddInternalVariable() (idx int) {
 	idx = system.NbInternalVariables + system.GetNbPublicVariables() + system.GetNbSecretVariables()
+	system.fingerprint = nil
 	system.NbInternalVariables++
 	// also grow the level slice
 	system.lbWireLevel = append(system.lbWireLevel, LevelUnset)
@@ -237,12 +249,14 @@ func (system *System) AddInternalVariable() (idx int) {
 
 func (system *System) AddPublicVariable(name string) (idx int) {
 	idx = system.GetNbPublicVariables()
+	system.fingerprint = nil
 	system.Public = append(system.Public, name)
 	return idx
 }
 
 func (system *System) AddSecretVariable(name string) (idx int) {
 	idx = system.GetNbSecretVariables() + system.GetNbPublicVariables()
+	system.fingerprint = nil
 	system.Secret = append(system.Secret, name)
 	return idx
 }
@@ -304,6 +318,7 @@ func (system *System) AddSolverHint(f solver.Hint, id solver.HintID, input []Lin
 }
 
 func (system *System) AddCommitment(c Commitment) error {
+	system.fingerprint = nil
 	switch v := c.(type) {
 	case Groth16Commitment:
 		system.CommitmentInfo = append(system.CommitmentInfo.(Groth16Commitments), v)
@@ -327,6 +342,25 @@ func (system *System) AttachDebugInfo(debugInfo DebugInfo, constraintID []int) {
 	}
 }
 
+// GetDebugInfo returns the debug information attached to the constraint cID
+func (system *System) GetDebugInfo(cID int) (LogEntry, bool) {
+	id, ok := system.MDebug[cID]
+	if !ok {
+		return LogEntry{}, false
+	}
+	return system.DebugInfo[id], true
+}
+
+// GetSymbolTable returns the symbol table of the debug information and logs
+func (system *System) GetSymbolTable() *debug.SymbolTable {
+	return &system.SymbolTable
+}
+
+// GetHintName returns the name of the hint with the given ID
+func (system *System) GetHintName(id solver.HintID) string {
+	return system.MHintsDependencies[id]
+}
+
 // VariableToString implements Resolver
 func (system *System) VariableToString(vID int) string {
 	nbPublic := system.GetNbPublicVariables()
@@ -389,6 +423,8 @@ func (cs *System) AddInstruction(bID BlueprintID, calldata []uint32) []uint32 {
 		BlueprintID:      bID,
 	}
 
+	cs.fingerprint = nil
+
 	// append the call data
 	cs.CallData = append(cs.CallData, calldata...)
 
@@ -475,6 +511,7 @@ func (system *System) AddGkr(gkr GkrInfo) error {
 		return fmt.Errorf("currently only one GKR sub-circuit per SNARK is supported")
 	}
 
+	system.fingerprint = nil
 	system.GkrInfo = gkr
 	return nil
 }
@@ -0,0 +1,464 @@
+// Package diff compares two compiled constraint systems.
+//
+// The instructions of the constraint systems are grouped by the source
+// location recorded in their debug information (see
+// [constraint.ConstraintSystem.GetDebugInfo]); the instructions without debug
+// information are attributed to the next instruction which has some, as the
+// frontend attaches the debug information to the constraint closing a gadget
+// or an assertion. Constraint systems compiled with the debug build tag hold
+// the debug information of every constraint, which gives the finest grouping.
+//
+// The constraints are compared through their string representation, in which
+// the internal wires are anonymized, so that renumbering the wires doesn't
+// show up as a difference.
+package diff
+
+import (
+	"bytes"
+	"fmt"
+	"io"
+	"math/big"
+	"reflect"
+	"sort"
+	"strings"
+
+	"github.com/consensys/gnark-crypto/ecc"
+	"github.com/consensys/gnark/constraint"
+	cs_bls12377 "github.com/consensys/gnark/constraint/bls12-377"
+	cs_bls12381 "github.com/consensys/gnark/constraint/bls12-381"
+	cs_bls24315 "github.com/consensys/gnark/constraint/bls24-315"
+	cs_bls24317 "github.com/consensys/gnark/constraint/bls24-317"
+	cs_bn254 "github.com/consensys/gnark/constraint/bn254"
+	cs_bw6633 "github.com/consensys/gnark/constraint/bw6-633"
+	cs_bw6761 "github.com/consensys/gnark/constraint/bw6-761"
+	"github.com/consensys/gnark/internal/utils"
+	"github.com/fxamacker/cbor/v2"
+)
+
+// UnknownLocation is the location of the instructions which are not followed
+// by any instruction with debug information.
+const UnknownLocation = "<unknown location>"
+
+// Delta is a quantity before and after the change.
+type Delta struct {
+	Before, After int
+}
+
+// Changed returns true if the quantity changed
+func (d Delta) Changed() bool {
+	return d.Before != d.After
+}
+
+func (d Delta) String() string {
+	return fmt.Sprintf("%d → %d (%+d)", d.Before, d.After, d.After-d.Before)
+}
+
+// Report is the difference between two constraint systems.
+type Report struct {
+	NbPublicVariables, NbSecretVariables, NbInternalVariables Delta
+	NbConstraints, NbInstructions                             Delta
+
+	// Blueprints lists the blueprints whose number of instructions changed,
+	// including the ones used by only one of the constraint systems.
+	Blueprints []BlueprintChange
+
+	// Locations lists the source locations whose instructions changed, in
+	// the order of the new constraint system, followed by the locations only
+	// present in the old one.
+	Locations []LocationChange
+}
+
+// BlueprintChange reports the change of the number of instructions using a
+// blueprint, identified by its type.
+type BlueprintChange struct {
+	Name           string
+	NbInstructions Delta
+}
+
+// LocationChange reports the changes of the instructions attributed to a
+// source location.
+type LocationChange struct {
+	Location            string
+	NbConstraints       Delta
+	NbInternalVariables Delta // wires first referenced at the location
+
+	// Added and Removed are the constraints present in only one of the
+	// constraint systems, with repetitions.
+	Added, Removed []string
+
+	// AddedHints and RemovedHints are the hint calls present in only one of
+	// the constraint systems, with repetitions.
+	AddedHints, RemovedHints []string
+}
+
+// Compare returns the differences between the constraint systems before and
+// after.
+func Compare(before, after constraint.ConstraintSystem) *Report {
+	sBefore, sAfter := summarize(before), summarize(after)
+	r := &Report{
+		NbPublicVariables:   Delta{before.GetNbPublicVariables(), after.GetNbPublicVariables()},
+		NbSecretVariables:   Delta{before.GetNbSecretVariables(), after.GetNbSecretVariables()},
+		NbInternalVariables: Delta{before.GetNbInternalVariables(), after.GetNbInternalVariables()},
+		NbConstraints:       Delta{before.GetNbConstraints(), after.GetNbConstraints()},
+		NbInstructions:      Delta{before.GetNbInstructions(), after.GetNbInstructions()},
+	}
+
+	names := make([]string, 0, len(sAfter.blueprints))
+	for name := range sAfter.blueprints {
+		names = append(names, name)
+	}
+	for name := range sBefore.blueprints {
+		if _, ok := sAfter.blueprints[name]; !ok {
+			names = append(names, name)
+		}
+	}
+	sort.Strings(names)
+	for _, name := range names {
+		d := Delta{sBefore.blueprints[name], sAfter.blueprints[name]}
+		if d.Changed() {
+			r.Blueprints = append(r.Blueprints, BlueprintChange{Name: name, NbInstructions: d})
+		}
+	}
+
+	locations := append([]string{}, sAfter.order...)
+	for _, l := range sBefore.order {
+		if _, ok := sAfter.locations[l]; !ok {
+			locations = append(locations, l)
+		}
+	}
+	empty := newLocation()
+	for _, l := range locations {
+		lBefore, lAfter := sBefore.locations[l], sAfter.locations[l]
+		if lBefore == nil {
+			lBefore = empty
+		}
+		if lAfter == nil {
+			lAfter = empty
+		}
+		c := LocationChange{
+			Location:            l,
+			NbConstraints:       Delta{lBefore.nbConstraints, lAfter.nbConstraints},
+			NbInternalVariables: Delta{lBefore.nbWires, lAfter.nbWires},
+			Added:               difference(lAfter.constraints, lBefore.constraints),
+			Removed:             difference(lBefore.constraints, lAfter.constraints),
+			AddedHints:          difference(lAfter.hints, lBefore.hints),
+			RemovedHints:        difference(lBefore.hints, lAfter.hints),
+		}
+		if c.NbConstraints.Changed() || c.NbInternalVariables.Changed() ||
+			len(c.Added)+len(c.Removed)+len(c.AddedHints)+len(c.RemovedHints) != 0 {
+			r.Locations = append(r.Locations, c)
+		}
+	}
+
+	return r
+}
+
+// Empty returns true if no difference was found.
+func (r *Report) Empty() bool {
+	return !r.NbPublicVariables.Changed() && !r.NbSecretVariables.Changed() &&
+		!r.NbInternalVariables.Changed() && !r.NbConstraints.Changed() &&
+		!r.NbInstructions.Changed() && len(r.Blueprints) == 0 && len(r.Locations) == 0
+}
+
+// WriteTo writes a human readable version of the report to w.
+func (r *Report) WriteTo(w io.Writer) (int64, error) {
+	var sbb strings.Builder
+	writeDelta := func(name string, d Delta) {
+		if d.Changed() {
+			fmt.Fprintf(&sbb, "%s: %s\n", name, d)
+		}
+	}
+	writeList := func(prefix string, l []string) {
+		// the lists are sorted, print the repeated entries once
+		for i := 0; i < len(l); {
+			j := i + 1
+			for j < len(l) && l[j] == l[i] {
+				j++
+			}
+			if j-i == 1 {
+				fmt.Fprintf(&sbb, "    %s %s\n", prefix, l[i])
+			} else {
+				fmt.Fprintf(&sbb, "    %s %d× %s\n", prefix, j-i, l[i])
+			}
+			i = j
+		}
+	}
+
+	if r.Empty() {
+		sbb.WriteString("no difference\n")
+	}
+	writeDelta("public variables", r.NbPublicVariables)
+	writeDelta("secret variables", r.NbSecretVariables)
+	writeDelta("internal variables", r.NbInternalVariables)
+	writeDelta("constraints", r.NbConstraints)
+	writeDelta("instructions", r.NbInstructions)
+	if len(r.Blueprints) != 0 {
+		sbb.WriteString("\nblueprints (number of instructions):\n")
+		for _, b := range r.Blueprints {
+			fmt.Fprintf(&sbb, "  %s: %s\n", b.Name, b.NbInstructions)
+		}
+	}
+	for _, l := range r.Locations {
+		fmt.Fprintf(&sbb, "\n%s\n", l.Location)
+		if l.NbConstraints.Changed() {
+			fmt.Fprintf(&sbb, "  constraints: %s\n", l.NbConstraints)
+		}
+		if l.NbInternalVariables.Changed() {
+			fmt.Fprintf(&sbb, "  internal variables: %s\n", l.NbInternalVariables)
+		}
+		writeList("-", l.Removed)
+		writeList("+", l.Added)
+		writeList("- hint", l.RemovedHints)
+		writeList("+ hint", l.AddedHints)
+	}
+
+	n, err := io.WriteString(w, sbb.String())
+	return int64(n), err
+}
+
+// ReadConstraintSystem reads a constraint system serialized with WriteTo. The
+// curve and the type of the constraint system are read from the serialized
+// data.
+func ReadConstraintSystem(r io.Reader) (constraint.ConstraintSystem, error) {
+	data, err := io.ReadAll(r)
+	if err != nil {
+		return nil, err
+	}
+
+	// the serialization header
+	var header struct {
+		ScalarField string
+		Type        constraint.SystemType
+	}
+	dm, err := cbor.DecOptions{
+		MaxArrayElements: 2147483647,
+		MaxMapPairs:      2147483647,
+	}.DecMode()
+	if err != nil {
+		return nil, err
+	}
+	if err := dm.Unmarshal(data, &header); err != nil {
+		return nil, fmt.Errorf("read header: %w", err)
+	}
+	q, ok := new(big.Int).SetString(header.ScalarField, 16)
+	if !ok {
+		return nil, fmt.Errorf("invalid scalar field %q", header.ScalarField)
+	}
+	ccs, err := newConstraintSystem(utils.FieldToCurve(q), header.Type)
+	if err != nil {
+		return nil, err
+	}
+	if _, err := ccs.ReadFrom(bytes.NewReader(data)); err != nil {
+		return nil, err
+	}
+	return ccs, nil
+}
+
+func newConstraintSystem(curve ecc.ID, t constraint.SystemType) (constraint.ConstraintSystem, error) {
+	if t != constraint.SystemR1CS && t != constraint.SystemSparseR1CS {
+		return nil, fmt.Errorf("unknown constraint system type %d", t)
+	}
+	r1cs := t == constraint.SystemR1CS
+	switch curve {
+	case ecc.BN254:
+		if r1cs {
+			return &cs_bn254.R1CS{}, nil
+		}
+		return &cs_bn254.SparseR1CS{}, nil
+	case ecc.BLS12_377:
+		if r1cs {
+			return &cs_bls12377.R1CS{}, nil
+		}
+		return &cs_bls12377.SparseR1CS{}, nil
+	case ecc.BLS12_381:
+		if r1cs {
+			return &cs_bls12381.R1CS{}, nil
+		}
+		return &cs_bls12381.SparseR1CS{}, nil
+	case ecc.BLS24_315:
+		if r1cs {
+			return &cs_bls24315.R1CS{}, nil
+		}
+		return &cs_bls24315.SparseR1CS{}, nil
+	case ecc.BLS24_317:
+		if r1cs {
+			return &cs_bls24317.R1CS{}, nil
+		}
+		return &cs_bls24317.SparseR1CS{}, nil
+	case ecc.BW6_633:
+		if r1cs {
+			return &cs_bw6633.R1CS{}, nil
+		}
+		return &cs_bw6633.SparseR1CS{}, nil
+	case ecc.BW6_761:
+		if r1cs {
+			return &cs_bw6761.R1CS{}, nil
+		}
+		return &cs_bw6761.SparseR1CS{}, nil
+	default:
+		return nil, fmt.Errorf("unsupported curve %s", curve)
+	}
+}
+
+// summary of a constraint system
+type summary struct {
+	blueprints map[string]int // number of instructions per blueprint
+	locations  map[string]*location
+	order      []string // locations in order of appearance
+}
+
+type location struct {
+	nbConstraints, nbWires int
+	constraints, hints     map[string]int
+}
+
+func newLocation() *location {
+	return &location{constraints: make(map[string]int), hints: make(map[string]int)}
+}
+
+func summarize(ccs constraint.ConstraintSystem) *summary {
+	s := &summary{
+		blueprints: make(map[string]int),
+		locations:  make(map[string]*location),
+	}
+	r := anonymizer{ConstraintSystem: ccs, nbInputs: ccs.GetNbPublicVariables() + ccs.GetNbSecretVariables()}
+	tree := newWireTracker(ccs)
+	symbols := ccs.GetSymbolTable()
+
+	// the instructions waiting for an instruction with debug information
+	var pending location
+	pending.constraints, pending.hints = make(map[string]int), make(map[string]int)
+
+	flush := func(l string) {
+		loc, ok := s.locations[l]
+		if !ok {
+			loc = newLocation()
+			s.locations[l] = loc
+			s.order = append(s.order, l)
+		}
+		loc.nbConstraints += pending.nbConstraints
+		loc.nbWires += pending.nbWires
+		for k, v := range pending.constraints {
+			loc.constraints[k] += v
+			delete(pending.constraints, k)
+		}
+		for k, v := range pending.hints {
+			loc.hints[k] += v
+			delete(pending.hints, k)
+		}
+		pending.nbConstraints, pending.nbWires = 0, 0
+	}
+
+	var r1c constraint.R1C
+	var sparseR1C constraint.SparseR1C
+	var hint constraint.HintMapping
+	for i := 0; i < ccs.GetNbInstructions(); i++ {
+		inst := ccs.GetInstruction(i)
+		b := ccs.GetInstructionBlueprint(i)
+		s.blueprints[blueprintName(b)]++
+
+		tree.nbNew = 0
+		b.UpdateInstructionTree(inst, tree)
+		pending.nbWires += tree.nbNew
+
+		switch t := b.(type) {
+		case constraint.BlueprintR1C:
+			t.DecompressR1C(&r1c, inst)
+			pending.constraints[r1c.String(r)]++
+		case constraint.BlueprintSparseR1C:
+			t.DecompressSparseR1C(&sparseR1C, inst)
+			pending.constraints[sparseR1C.String(r)]++
+		case constraint.BlueprintHint:
+			t.DecompressHint(&hint, inst)
+			name := ccs.GetHintName(hint.HintID)
+			if name == "" {
+				name = fmt.Sprintf("hint %d", hint.HintID)
+			}
+			pending.hints[fmt.Sprintf("%s(%d inputs) → %d outputs", name, len(hint.Inputs), hint.OutputRange.End-hint.OutputRange.Start)]++
+		default:
+			if b.NbConstraints() != 0 {
+				pending.constraints[fmt.Sprintf("%s(%d calldata)", blueprintName(b), len(inst.Calldata))] += b.NbConstraints()
+			}
+		}
+		pending.nbConstraints += b.NbConstraints()
+
+		// the location of the last constraint of the instruction
+		for c := b.NbConstraints() - 1; c >= 0; c-- {
+			if d, ok := ccs.GetDebugInfo(int(inst.ConstraintOffset) + c); ok && len(d.Stack) != 0 {
+				flush(d.Location(symbols))
+				break
+			}
+		}
+	}
+	if pending.nbConstraints+pending.nbWires+len(pending.constraints)+len(pending.hints) != 0 {
+		flush(UnknownLocation)
+	}
+	return s
+}
+
+// difference returns the elements of a not in b, with repetitions, sorted
+func difference(a, b map[string]int) []string {
+	var res []string
+	for k, n := range a {
+		for i := b[k]; i < n; i++ {
+			res = append(res, k)
+		}
+	}
+	sort.Strings(res)
+	return res
+}
+
+func blueprintName(b constraint.Blueprint) string {
+	t := reflect.TypeOf(b)
+	for t.Kind() == reflect.Pointer {
+		t = t.Elem()
+	}
+	return t.String()
+}
+
+// anonymizer resolves the internal wires to the same name, so that two
+// constraints differing only by the numbering of the internal wires have the
+// same representation.
+type anonymizer struct {
+	constraint.ConstraintSystem
+	nbInputs int
+}
+
+func (a anonymizer) VariableToString(vID int) string {
+	if vID >= a.nbInputs {
+		return "v"
+	}
+	return a.ConstraintSystem.VariableToString(vID)
+}
+
+// wireTracker is an instruction tree counting the wires first referenced by an
+// instruction.
+type wireTracker struct {
+	offset uint32
+	levels []constraint.Level
+	nbNew  int
+}
+
+func newWireTracker(ccs constraint.ConstraintSystem) *wireTracker {
+	t := &wireTracker{
+		offset: uint32(ccs.GetNbPublicVariables() + ccs.GetNbSecretVariables()),
+		levels: make([]constraint.Level, ccs.GetNbInternalVariables()),
+	}
+	for i := range t.levels {
+		t.levels[i] = constraint.LevelUnset
+	}
+	return t
+}
+
+func (t *wireTracker) HasWire(wireID uint32) bool {
+	return wireID >= t.offset && wireID-t.offset < uint32(len(t.levels))
+}
+
+func (t *wireTracker) GetWireLevel(wireID uint32) constraint.Level {
+	return t.levels[wireID-t.offset]
+}
+
+func (t *wireTracker) InsertWire(wireID uint32, level constraint.Level) {
+	t.levels[wireID-t.offset] = level
+	t.nbNew++
+}
@@ -0,0 +1,91 @@
+package diff_test
+
+import (
+	"bytes"
+	"strings"
+	"testing"
+
+	"github.com/consensys/gnark-crypto/ecc"
+	"github.com/consensys/gnark/constraint"
+	"github.com/consensys/gnark/constraint/diff"
+	"github.com/consensys/gnark/debug"
+	"github.com/consensys/gnark/frontend"
+	"github.com/consensys/gnark/frontend/cs/r1cs"
+	"github.com/consensys/gnark/frontend/cs/scs"
+	"github.com/stretchr/testify/require"
+)
+
+type diffCircuit struct {
+	X, Y  frontend.Variable
+	Z     frontend.Variable `gnark:",public"`
+	extra bool
+}
+
+func (c *diffCircuit) Define(api frontend.API) error {
+	api.AssertIsLessOrEqual(c.X, c.Y)
+	if c.extra {
+		api.AssertIsLessOrEqual(api.Mul(c.X, c.X), c.Z)
+	}
+	api.AssertIsLessOrEqual(c.Y, c.Z)
+	return nil
+}
+
+func TestCompare(t *testing.T) {
+	for _, newBuilder := range []frontend.NewBuilder{r1cs.NewBuilder, scs.NewBuilder} {
+		compile := func(circuit frontend.Circuit) constraint.ConstraintSystem {
+			ccs, err := frontend.Compile(ecc.BN254.ScalarField(), newBuilder, circuit)
+			require.NoError(t, err)
+
+			// serialization round trip
+			var buf bytes.Buffer
+			_, err = ccs.WriteTo(&buf)
+			require.NoError(t, err)
+			res, err := diff.ReadConstraintSystem(&buf)
+			require.NoError(t, err)
+			require.IsType(t, ccs, res)
+			return res
+		}
+
+		before, after := compile(&diffCircuit{}), compile(&diffCircuit{extra: true})
+
+		require.True(t, diff.Compare(before, before).Empty())
+
+		report := diff.Compare(before, after)
+		require.False(t, report.Empty())
+		require.Equal(t, diff.Delta{Before: before.GetNbConstraints(), After: after.GetNbConstraints()}, report.NbConstraints)
+		require.True(t, report.NbInternalVariables.Changed())
+		require.False(t, report.NbPublicVariables.Changed())
+
+		// the changes per location add up
+		nbAdded, nbRemoved := 0, 0
+		for _, l := range report.Locations {
+			require.Equal(t, l.NbConstraints.After-l.NbConstraints.Before, len(l.Added)-len(l.Removed))
+			nbAdded += len(l.Added)
+			nbRemoved += len(l.Removed)
+		}
+		require.Equal(t, report.NbConstraints.After-report.NbConstraints.Before, nbAdded-nbRemoved)
+
+		var sb strings.Builder
+		_, err := report.WriteTo(&sb)
+		require.NoError(t, err)
+		require.Contains(t, sb.String(), report.Locations[0].Location)
+
+		// the reverse comparison removes the same constraints
+		reverse := diff.Compare(after, before)
+		require.Equal(t, len(report.Locations), len(reverse.Locations))
+		for i, l := range reverse.Locations {
+			require.Equal(t, report.Locations[i].Added, l.Removed)
+			require.Equal(t, report.Locations[i].AddedHints, l.RemovedHints)
+		}
+
+		if debug.Debug {
+			// the full stacks are recorded, only the extra assertion changed
+			require.Len(t, report.Locations, 1)
+			l := report.Locations[0]
+			require.Contains(t, l.Location, "(*diffCircuit).Define")
+			require.Equal(t, 0, l.NbConstraints.Before)
+			require.Empty(t, l.Removed)
+			require.NotEmpty(t, l.AddedHints)
+		}
+	}
+}
@@ -0,0 +1,253 @@
+package constraint
+
+import (
+	"bufio"
+	"fmt"
+	"io"
+	"reflect"
+	"strconv"
+	"strings"
+
+	"github.com/consensys/gnark/debug"
+	"golang.org/x/exp/slices"
+)
+
+// WriteText writes a human readable listing of the constraint system to w.
+//
+// Each instruction is written on its own line, with its index, its level in
+// the instruction tree, the name of its blueprint, the constraint or hint it
+// encodes and, when the constraint has debug information, its source location.
+// The wires are named after the circuit schema for the public and secret
+// inputs, and v0, v1, ... for the internal wires.
+func WriteText(w io.Writer, cs ConstraintSystem) error {
+	bw := bufio.NewWriter(w)
+	symbols := cs.GetSymbolTable()
+
+	nbPublic, nbSecret := cs.GetNbPublicVariables(), cs.GetNbSecretVariables()
+	fmt.Fprintf(bw, "// %s over %s\n", systemTypeName(cs), cs.Field().String())
+	fmt.Fprintf(bw, "// %d public variables:", nbPublic)
+	for i := 0; i < nbPublic; i++ {
+		bw.WriteString(" " + cs.VariableToString(i))
+	}
+	fmt.Fprintf(bw, "\n// %d secret variables:", nbSecret)
+	for i := 0; i < nbSecret; i++ {
+		bw.WriteString(" " + cs.VariableToString(nbPublic+i))
+	}
+	fmt.Fprintf(bw, "\n// %d internal variables, %d constraints, %d instructions\n",
+		cs.GetNbInternalVariables(), cs.GetNbConstraints(), cs.GetNbInstructions())
+
+	var (
+		r1c       R1C
+		sparseR1C SparseR1C
+		hint      HintMapping
+		sbb       strings.Builder
+	)
+	tree := newWireRecorder(cs)
+	for i := 0; i < cs.GetNbInstructions(); i++ {
+		inst := cs.GetInstruction(i)
+		b := cs.GetInstructionBlueprint(i)
+		level := tree.update(i, b, inst)
+
+		sbb.Reset()
+		switch t := b.(type) {
+		case BlueprintR1C:
+			t.DecompressR1C(&r1c, inst)
+			sbb.WriteString(r1c.String(cs))
+		case BlueprintSparseR1C:
+			t.DecompressSparseR1C(&sparseR1C, inst)
+			sbb.WriteString(sparseR1C.String(cs))
+		case BlueprintHint:
+			t.DecompressHint(&hint, inst)
+			writeHint(&sbb, cs, &hint)
+		default:
+			sbb.WriteString("calldata: ")
+			for j, c := range inst.Calldata {
+				if j != 0 {
+					sbb.WriteByte(' ')
+				}
+				sbb.WriteString(strconv.FormatUint(uint64(c), 10))
+			}
+		}
+
+		fmt.Fprintf(bw, "#%d\tlevel %d\t%s\t%s", i, level, blueprintName(b), sbb.String())
+		if location := instructionLocation(cs, symbols, b, inst); location != "" {
+			bw.WriteString("\t// " + location)
+		}
+		bw.WriteByte('\n')
+	}
+
+	return bw.Flush()
+}
+
+// WriteDOT writes the dependency graph of the instructions of the constraint
+// system to w, in the Graphviz DOT format.
+//
+// The instructions are the nodes of the graph, grouped by level of the
+// instruction tree; an edge links the instruction introducing a wire to each
+// of the instructions depending on it.
+func WriteDOT(w io.Writer, cs ConstraintSystem) error {
+	bw := bufio.NewWriter(w)
+
+	tree := newWireRecorder(cs)
+	var levels [][]int
+	var edges [][2]int
+	for i := 0; i < cs.GetNbInstructions(); i++ {
+		inst := cs.GetInstruction(i)
+		b := cs.GetInstructionBlueprint(i)
+		level := int(tree.update(i, b, inst))
+		for len(levels) <= level {
+			levels = append(levels, nil)
+		}
+		levels[level] = append(levels[level], i)
+		for _, from := range tree.dependencies {
+			edges = append(edges, [2]int{from, i})
+		}
+	}
+
+	bw.WriteString("digraph constraints {\n\tnode [shape=box];\n")
+	for level, instructions := range levels {
+		fmt.Fprintf(bw, "\tsubgraph cluster_level_%d {\n\t\tlabel=\"level %d\";\n", level, level)
+		for _, i := range instructions {
+			fmt.Fprintf(bw, "\t\ti%d [label=\"#%d %s\"];\n", i, i, blueprintName(cs.GetInstructionBlueprint(i)))
+		}
+		bw.WriteString("\t}\n")
+	}
+	for _, e := range edges {
+		fmt.Fprintf(bw, "\ti%d -> i%d;\n", e[0], e[1])
+	}
+	bw.WriteString("}\n")
+
+	return bw.Flush()
+}
+
+// writeHint formats the hint call, with its inputs and the range of its
+// outputs.
+func writeHint(sbb *strings.Builder, cs ConstraintSystem, hint *HintMapping) {
+	name := cs.GetHintName(hint.HintID)
+	if name == "" {
+		name = strconv.FormatUint(uint64(hint.HintID), 10)
+	}
+	sbb.WriteString(name)
+	sbb.WriteByte('(')
+	for i, in := range hint.Inputs {
+		if i != 0 {
+			sbb.WriteString(", ")
+		}
+		sbb.WriteString(in.String(cs))
+	}
+	sbb.WriteString(") → ")
+	switch hint.OutputRange.End - hint.OutputRange.Start {
+	case 0:
+		sbb.WriteString("∅")
+	case 1:
+		sbb.WriteString(cs.VariableToString(int(hint.OutputRange.Start)))
+	default:
+		sbb.WriteString(cs.VariableToString(int(hint.OutputRange.Start)))
+		sbb.WriteString("..")
+		sbb.WriteString(cs.VariableToString(int(hint.OutputRange.End - 1)))
+	}
+}
+
+// Location returns the source location of the entry: the outermost frame of
+// its stack outside of the constraint system builders, which is the closest
+// to the circuit definition. It returns an empty string if the stack is
+// empty.
+func (l *LogEntry) Location(symbols *debug.SymbolTable) string {
+	if len(l.Stack) == 0 {
+		return ""
+	}
+	location := symbols.Locations[l.Stack[len(l.Stack)-1]]
+	for i := len(l.Stack) - 1; i >= 0; i-- {
+		f := symbols.Functions[symbols.Locations[l.Stack[i]].FunctionID]
+		if !strings.HasPrefix(f.SystemName, "github.com/consensys/gnark/frontend/cs/") {
+			location = symbols.Locations[l.Stack[i]]
+			break
+		}
+	}
+	f := symbols.Functions[location.FunctionID]
+	return fmt.Sprintf("%s:%d %s", f.Filename, location.Line, f.Name)
+}
+
+// instructionLocation returns the location of the last constraint of the
+// instruction with debug information, if any.
+func instructionLocation(cs interface{ GetDebugInfo(int) (LogEntry, bool) }, symbols *debug.SymbolTable, b Blueprint, inst Instruction) string {
+	for c := b.NbConstraints() - 1; c >= 0; c-- {
+		if d, ok := cs.GetDebugInfo(int(inst.ConstraintOffset) + c); ok {
+			if location := d.Location(symbols); location != "" {
+				return location
+			}
+		}
+	}
+	return ""
+}
+
+func systemTypeName(cs ConstraintSystem) string {
+	if s, ok := cs.(interface{ core() *System }); ok {
+		switch s.core().Type {
+		case SystemR1CS:
+			return "R1CS"
+		case SystemSparseR1CS:
+			return "SparseR1CS"
+		}
+	}
+	return "constraint system"
+}
+
+func blueprintName(b Blueprint) string {
+	t := reflect.TypeOf(b)
+	for t.Kind() == reflect.Pointer {
+		t = t.Elem()
+	}
+	return t.Name()
+}
+
+// wireRecorder is an InstructionTree recording, for each instruction, the
+// instructions introducing the wires it depends on.
+type wireRecorder struct {
+	offset       uint32
+	levels       []Level
+	producers    []int // instruction introducing each internal wire
+	current      int
+	dependencies []int // instructions the current instruction depends on
+}
+
+func newWireRecorder(cs ConstraintSystem) *wireRecorder {
+	r := &wireRecorder{
+		offset:    uint32(cs.GetNbPublicVariables() + cs.GetNbSecretVariables()),
+		levels:    make([]Level, cs.GetNbInternalVariables()),
+		producers: make([]int, cs.GetNbInternalVariables()),
+	}
+	for i := range r.levels {
+		r.levels[i] = LevelUnset
+	}
+	return r
+}
+
+// update inserts the wires of the instruction and returns its level
+func (r *wireRecorder) update(iID int, b Blueprint, inst Instruction) Level {
+	r.current = iID
+	r.dependencies = r.dependencies[:0]
+	return b.UpdateInstructionTree(inst, r)
+}
+
+func (r *wireRecorder) HasWire(wireID uint32) bool {
+	return wireID >= r.offset && wireID-r.offset < uint32(len(r.levels))
+}
+
+func (r *wireRecorder) GetWireLevel(wireID uint32) Level {
+	wireID -= r.offset
+	if level := r.levels[wireID]; level != LevelUnset {
+		producer := r.producers[wireID]
+		if producer != r.current && !slices.Contains(r.dependencies, producer) {
+			r.dependencies = append(r.dependencies, producer)
+		}
+		return level
+	}
+	return LevelUnset
+}
+
+func (r *wireRecorder) InsertWire(wireID uint32, level Level) {
+	wireID -= r.offset
+	r.levels[wireID] = level
+	r.producers[wireID] = r.current
+}
@@ -0,0 +1,77 @@
+package constraint_test
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/consensys/gnark-crypto/ecc"
+	"github.com/consensys/gnark/constraint"
+	"github.com/consensys/gnark/debug"
+	"github.com/consensys/gnark/frontend"
+	"github.com/consensys/gnark/frontend/cs/r1cs"
+	"github.com/consensys/gnark/frontend/cs/scs"
+	"github.com/stretchr/testify/require"
+)
+
+type exportCircuit struct {
+	X, Y frontend.Variable
+	Z    frontend.Variable `gnark:",public"`
+}
+
+func (c *exportCircuit) Define(api frontend.API) error {
+	xy := api.Mul(c.X, c.Y)
+	api.AssertIsLessOrEqual(c.X, c.Y)
+	api.AssertIsEqual(api.Add(xy, c.Y), c.Z)
+	return nil
+}
+
+func TestWriteText(t *testing.T) {
+	for _, tc := range []struct {
+		newBuilder frontend.NewBuilder
+		debugInfo  bool
+	}{
+		// the r1cs builder attaches the debug information of the comparison,
+		// the scs one only with the debug build tag
+		{r1cs.NewBuilder, true},
+		{scs.NewBuilder, debug.Debug},
+	} {
+		newBuilder := tc.newBuilder
+		ccs, err := frontend.Compile(ecc.BN254.ScalarField(), newBuilder, &exportCircuit{})
+		require.NoError(t, err)
+
+		var sb strings.Builder
+		require.NoError(t, constraint.WriteText(&sb, ccs))
+		listing := sb.String()
+
+		lines := strings.Split(strings.TrimSuffix(listing, "\n"), "\n")
+		require.Len(t, lines, 4+ccs.GetNbInstructions())
+		require.True(t, strings.HasSuffix(lines[1], " Z"))
+		require.True(t, strings.HasSuffix(lines[2], ": X Y"))
+		require.True(t, strings.HasPrefix(lines[4], "#0\tlevel 0\t"))
+
+		// the schema names and the hint names are used
+		require.Contains(t, lines[4], "X")
+		require.Contains(t, listing, "bits.nBits(X)")
+		if tc.debugInfo {
+			require.Contains(t, listing, "\t// ")
+		}
+	}
+}
+
+func TestWriteDOT(t *testing.T) {
+	for _, newBuilder := range []frontend.NewBuilder{r1cs.NewBuilder, scs.NewBuilder} {
+		ccs, err := frontend.Compile(ecc.BN254.ScalarField(), newBuilder, &exportCircuit{})
+		require.NoError(t, err)
+
+		var sb strings.Builder
+		require.NoError(t, constraint.WriteDOT(&sb, ccs))
+		graph := sb.String()
+
+		require.True(t, strings.HasPrefix(graph, "digraph constraints {\n"))
+		require.True(t, strings.HasSuffix(graph, "}\n"))
+		require.Contains(t, graph, "subgraph cluster_level_0 {")
+		require.Contains(t, graph, "subgraph cluster_level_1 {")
+		require.Equal(t, ccs.GetNbInstructions(), strings.Count(graph, " [label="))
+		require.Contains(t, graph, "-> i")
+	}
+}
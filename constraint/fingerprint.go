@@ -0,0 +1,237 @@
+package constraint
+
+import (
+	"bytes"
+	"crypto/sha256"
+	"encoding/binary"
+	"errors"
+	"hash"
+	"reflect"
+
+	"github.com/fxamacker/cbor/v2"
+)
+
+// fingerprintVersion is hashed first, it must be incremented when the
+// content of the fingerprint changes.
+const fingerprintVersion = "gnark/constraint/fingerprint/v2"
+
+// FingerprintSize is the size in bytes of the fingerprint of a constraint system
+const FingerprintSize = sha256.Size
+
+// Fingerprint returns a SHA-256 digest of the parts of the constraint system
+// which define the relation it encodes: the scalar field, the number of public,
+// secret and internal wires, the blueprints, the instructions, the
+// coefficients, the commitments and the GKR sub-circuit.
+//
+// The debug information, the logs, the symbol table and the names of the
+// inputs are not part of the fingerprint. It is stable across runs and
+// serialization round trips, but changes with any modification of the circuit
+// which affects its constraints.
+//
+// If the fingerprint was stored with [StoreFingerprint], it is returned without
+// hashing the system again.
+func Fingerprint(cs ConstraintSystem) ([]byte, error) {
+	c, ok := cs.(interface{ core() *System })
+	if !ok {
+		return nil, errUnsupportedSystem
+	}
+	system := c.core()
+	if system.fingerprint != nil {
+		return bytes.Clone(system.fingerprint), nil
+	}
+	return fingerprint(cs, system)
+}
+
+// StoreFingerprint computes the fingerprint of the complete constraint system
+// cs and stores it, so that it is not computed again by every prover and
+// setup. It is called when the system is compiled or deserialized.
+//
+// Adding wires, instructions, blueprints or commitments to cs, or optimizing
+// it, drops the stored fingerprint. Modifying the exported fields of cs in
+// place doesn't.
+func StoreFingerprint(cs ConstraintSystem) error {
+	c, ok := cs.(interface{ core() *System })
+	if !ok {
+		return errUnsupportedSystem
+	}
+	system := c.core()
+	system.fingerprint = nil
+	value, err := fingerprint(cs, system)
+	if err != nil {
+		return err
+	}
+	system.fingerprint = value
+	return nil
+}
+
+func fingerprint(cs ConstraintSystem, system *System) ([]byte, error) {
+	h := sha256.New()
+	// nil and empty slices must give the same fingerprint, as they do not
+	// survive serialization round trips
+	encOptions := cbor.CoreDetEncOptions()
+	encOptions.NilContainers = cbor.NilContainerAsEmpty
+	enc, err := encOptions.EncMode()
+	if err != nil {
+		return nil, err
+	}
+	var buf [8]byte
+	writeUint64 := func(v uint64) {
+		binary.BigEndian.PutUint64(buf[:], v)
+		h.Write(buf[:])
+	}
+	writeBytes := func(b []byte) {
+		writeUint64(uint64(len(b)))
+		h.Write(b)
+	}
+	writeObject := func(v any) error {
+		b, err := enc.Marshal(v)
+		if err != nil {
+			return err
+		}
+		writeBytes(b)
+		return nil
+	}
+
+	writeBytes([]byte(fingerprintVersion))
+	writeUint64(uint64(system.Type))
+	q := cs.Field()
+	writeBytes(q.Bytes())
+	writeUint64(uint64(len(system.Public)))
+	writeUint64(uint64(len(system.Secret)))
+	writeUint64(uint64(system.NbInternalVariables))
+
+	// the coefficients are hashed through the instructions referencing them,
+	// as the order of the coefficient table depends on how the system was
+	// built (e.g. with parallel compilation)
+	coeffs := make([][]byte, cs.GetNbCoefficients())
+	coeffSize := (q.BitLen() + 7) / 8
+	writeCoeff := func(cID uint32) {
+		if coeffs[cID] == nil {
+			coeffs[cID] = cs.ToBigInt(cs.GetCoefficient(int(cID))).FillBytes(make([]byte, coeffSize))
+		}
+		h.Write(coeffs[cID])
+	}
+	writeLinearExpression := func(l LinearExpression) {
+		writeUint64(uint64(len(l)))
+		for _, t := range l {
+			writeUint64(uint64(t.VID))
+			writeCoeff(t.CID)
+		}
+	}
+	// writeCalldataExpressions writes the linear expressions encoded in
+	// calldata as (length, (CID, VID)...) until its end
+	writeCalldataExpressions := func(calldata []uint32) {
+		for len(calldata) != 0 {
+			n := int(calldata[0])
+			writeUint64(uint64(n))
+			for k := 0; k < n; k++ {
+				writeUint64(uint64(calldata[2+2*k]))
+				writeCoeff(calldata[1+2*k])
+			}
+			calldata = calldata[1+2*n:]
+		}
+	}
+
+	// blueprints are identified by their type and their exported fields
+	writeUint64(uint64(len(system.Blueprints)))
+	for _, b := range system.Blueprints {
+		writeBytes([]byte(reflect.TypeOf(b).String()))
+		if b, ok := b.(*BlueprintLookupHint); ok {
+			writeCalldataExpressions(b.EntriesCalldata)
+			continue
+		}
+		if err := writeObject(b); err != nil {
+			return nil, err
+		}
+	}
+
+	writeUint64(uint64(len(system.Instructions)))
+	var inst optInstruction
+	for _, pi := range system.Instructions {
+		writeUint64(uint64(pi.BlueprintID))
+		writeUint64(uint64(pi.ConstraintOffset))
+		writeUint64(uint64(pi.WireOffset))
+		if !system.decompress(pi, &inst) {
+			calldata := pi.Unpack(system).Calldata
+			if _, ok := system.Blueprints[pi.BlueprintID].(*BlueprintLookupHint); ok {
+				// number of entries and of queries, then the queries
+				writeUint64(uint64(calldata[1]))
+				writeUint64(uint64(calldata[2]))
+				writeCalldataExpressions(calldata[3:])
+				continue
+			}
+			writeUint32s(h, calldata)
+			continue
+		}
+		switch inst.kind {
+		case kindR1C:
+			writeLinearExpression(inst.r1c.L)
+			writeLinearExpression(inst.r1c.R)
+			writeLinearExpression(inst.r1c.O)
+		case kindSparseR1C:
+			c := &inst.sparse
+			writeUint64(uint64(c.XA))
+			writeUint64(uint64(c.XB))
+			writeUint64(uint64(c.XC))
+			for _, cID := range [5]uint32{c.QL, c.QR, c.QO, c.QM, c.QC} {
+				writeCoeff(cID)
+			}
+			writeUint64(uint64(c.Commitment))
+		case kindHint:
+			writeUint64(uint64(inst.hint.HintID))
+			writeUint64(uint64(len(inst.hint.Inputs)))
+			for _, l := range inst.hint.Inputs {
+				writeLinearExpression(l)
+			}
+			writeUint64(uint64(inst.hint.OutputRange.Start))
+			writeUint64(uint64(inst.hint.OutputRange.End))
+		}
+	}
+
+	if err := writeObject(system.CommitmentInfo); err != nil {
+		return nil, err
+	}
+	if err := writeObject(system.GkrInfo); err != nil {
+		return nil, err
+	}
+
+	return h.Sum(nil), nil
+}
+
+// ErrFingerprintMismatch is returned when a key is used with a constraint
+// system other than the one it was generated for.
+var ErrFingerprintMismatch = errors.New("constraint system fingerprint mismatch")
+
+// CheckFingerprint returns ErrFingerprintMismatch if fingerprint is not empty
+// and differs from the fingerprint of cs.
+func CheckFingerprint(cs ConstraintSystem, fingerprint []byte) error {
+	if len(fingerprint) == 0 {
+		return nil
+	}
+	expected, err := Fingerprint(cs)
+	if err != nil {
+		return err
+	}
+	if !bytes.Equal(expected, fingerprint) {
+		return ErrFingerprintMismatch
+	}
+	return nil
+}
+
+// writeUint32s writes the length of s and its elements to h in big-endian
+func writeUint32s(h hash.Hash, s []uint32) {
+	var buf [4096]byte
+	binary.BigEndian.PutUint64(buf[:8], uint64(len(s)))
+	h.Write(buf[:8])
+	for len(s) != 0 {
+		n := len(buf) / 4
+		if len(s) < n {
+			n = len(s)
+		}
+		for i := 0; i < n; i++ {
+			binary.BigEndian.PutUint32(buf[4*i:], s[i])
+		}
+		h.Write(buf[:4*n])
+		s = s[n:]
+	}
+}
@@ -0,0 +1,116 @@
+package constraint_test
+
+import (
+	"bytes"
+	"testing"
+
+	"github.com/consensys/gnark-crypto/ecc"
+	"github.com/consensys/gnark/constraint"
+	cs "github.com/consensys/gnark/constraint/bn254"
+	"github.com/consensys/gnark/frontend"
+	"github.com/consensys/gnark/frontend/cs/r1cs"
+	"github.com/consensys/gnark/frontend/cs/scs"
+	"github.com/stretchr/testify/require"
+)
+
+type fingerprintCircuit struct {
+	X, Y      frontend.Variable
+	Z         frontend.Variable `gnark:",public"`
+	withLog   bool
+	different bool
+}
+
+func (c *fingerprintCircuit) Define(api frontend.API) error {
+	if c.withLog {
+		api.Println("x =", c.X)
+	}
+	xy := api.Mul(c.X, c.Y)
+	if c.different {
+		xy = api.Add(xy, 1)
+	}
+	api.AssertIsEqual(xy, c.Z)
+	return nil
+}
+
+func TestFingerprint(t *testing.T) {
+	for _, newBuilder := range []frontend.NewBuilder{r1cs.NewBuilder, scs.NewBuilder} {
+		fingerprint := func(circuit frontend.Circuit) []byte {
+			ccs, err := frontend.Compile(ecc.BN254.ScalarField(), newBuilder, circuit)
+			require.NoError(t, err)
+			res, err := constraint.Fingerprint(ccs)
+			require.NoError(t, err)
+			require.Len(t, res, constraint.FingerprintSize)
+			require.NoError(t, constraint.CheckFingerprint(ccs, res))
+
+			// serialization round trip
+			var buf bytes.Buffer
+			_, err = ccs.WriteTo(&buf)
+			require.NoError(t, err)
+			var reconstructed constraint.ConstraintSystem = &cs.R1CS{}
+			if _, ok := ccs.(*cs.SparseR1CS); ok {
+				reconstructed = &cs.SparseR1CS{}
+			}
+			_, err = reconstructed.ReadFrom(&buf)
+			require.NoError(t, err)
+			res2, err := constraint.Fingerprint(reconstructed)
+			require.NoError(t, err)
+			require.Equal(t, res, res2, "fingerprint changed by serialization")
+			return res
+		}
+
+		reference := fingerprint(&fingerprintCircuit{})
+		require.Equal(t, reference, fingerprint(&fingerprintCircuit{}), "fingerprint is not deterministic")
+		require.Equal(t, reference, fingerprint(&fingerprintCircuit{withLog: true}), "fingerprint depends on the logs")
+		require.NotEqual(t, reference, fingerprint(&fingerprintCircuit{different: true}))
+
+		ccs, err := frontend.Compile(ecc.BN254.ScalarField(), newBuilder, &fingerprintCircuit{different: true})
+		require.NoError(t, err)
+		require.ErrorIs(t, constraint.CheckFingerprint(ccs, reference), constraint.ErrFingerprintMismatch)
+	}
+}
+
+func TestFingerprintModification(t *testing.T) {
+	assert := require.New(t)
+	ccs, err := frontend.Compile(ecc.BN254.ScalarField(), r1cs.NewBuilder, &optimizableCircuit{})
+	assert.NoError(err)
+	before, err := constraint.Fingerprint(ccs)
+	assert.NoError(err)
+
+	// the fingerprint stored at compilation is dropped when the system grows
+	grown, err := frontend.Compile(ecc.BN254.ScalarField(), r1cs.NewBuilder, &optimizableCircuit{})
+	assert.NoError(err)
+	grown.AddInternalVariable()
+	modified, err := constraint.Fingerprint(grown)
+	assert.NoError(err)
+	assert.NotEqual(before, modified)
+	assert.ErrorIs(constraint.CheckFingerprint(grown, before), constraint.ErrFingerprintMismatch)
+
+	// modifications in place must be followed by StoreFingerprint
+	system := ccs.(*cs.R1CS)
+	system.CallData[len(system.CallData)-1] ^= 1
+	assert.NoError(constraint.StoreFingerprint(ccs))
+	modified, err = constraint.Fingerprint(ccs)
+	assert.NoError(err)
+	assert.NotEqual(before, modified)
+	system.CallData[len(system.CallData)-1] ^= 1
+	assert.NoError(constraint.StoreFingerprint(ccs))
+	restored, err := constraint.Fingerprint(ccs)
+	assert.NoError(err)
+	assert.Equal(before, restored)
+
+	_, err = constraint.Optimize(ccs, constraint.OptimizationFull)
+	assert.NoError(err)
+	after, err := constraint.Fingerprint(ccs)
+	assert.NoError(err)
+	assert.NotEqual(before, after)
+
+	var buf bytes.Buffer
+	_, err = ccs.WriteTo(&buf)
+	assert.NoError(err)
+	decoded := &cs.R1CS{}
+	_, err = decoded.ReadFrom(&buf)
+	assert.NoError(err)
+	fromDecoded, err := constraint.Fingerprint(decoded)
+	assert.NoError(err)
+	assert.Equal(after, fromDecoded)
+}
@@ -0,0 +1,771 @@
+package constraint
+
+import (
+	"encoding/binary"
+	"errors"
+	"math"
+)
+
+// OptimizationLevel selects the passes run by Optimize.
+type OptimizationLevel uint8
+
+const (
+	// OptimizationNone leaves the constraint system as built by the frontend.
+	OptimizationNone OptimizationLevel = iota
+
+	// OptimizationBasic removes the instructions whose only purpose is to
+	// define internal wires which are not used anywhere else (dead wires), and
+	// the constraints which are duplicates of a previous one.
+	OptimizationBasic
+
+	// OptimizationFull runs the passes of OptimizationBasic after
+	// substituting the internal wires defined by a linear R1C, when they are
+	// used only once or their value is a constant, and removing the R1Cs then
+	// left with constants only and always satisfied. There is no such pass
+	// for SparseR1CS, on which OptimizationFull is the same as
+	// OptimizationBasic.
+	OptimizationFull
+)
+
+// OptimizationStats reports the size of a constraint system before and after
+// Optimize.
+type OptimizationStats struct {
+	NbConstraintsBefore, NbConstraintsAfter             int
+	NbInternalVariablesBefore, NbInternalVariablesAfter int
+	NbInstructionsBefore, NbInstructionsAfter           int
+}
+
+var errUnsupportedSystem = errors.New("unsupported constraint system")
+
+// core gives Optimize and Fingerprint access to the System embedded in the curve-typed
+// constraint systems.
+func (system *System) core() *System {
+	return system
+}
+
+// Optimize rewrites cs in place into an equivalent constraint system: for any
+// assignment of the public and secret inputs, the optimized system is
+// satisfiable if and only if the original one is.
+//
+// The internal wires are renumbered, so the solution of the optimized system
+// can't be mapped to the one of the original system; the public and secret
+// inputs keep their index.
+//
+// Constraint systems with a GKR sub-circuit, or with instructions other than
+// constraints and hints (for example lookups), are left unchanged. The wires
+// and constraints involved in commitments, and the wires referenced by logs,
+// are never removed.
+func Optimize(cs ConstraintSystem, level OptimizationLevel) (OptimizationStats, error) {
+	c, ok := cs.(interface{ core() *System })
+	if !ok {
+		return OptimizationStats{}, errUnsupportedSystem
+	}
+	system := c.core()
+	stats := OptimizationStats{
+		NbConstraintsBefore:       system.GetNbConstraints(),
+		NbInternalVariablesBefore: system.GetNbInternalVariables(),
+		NbInstructionsBefore:      system.GetNbInstructions(),
+	}
+	if level != OptimizationNone && !system.GkrInfo.Is() {
+		if o, ok := newOptimizer(cs, system); ok {
+			if level >= OptimizationFull && o.oneWire {
+				o.substituteLinearWires()
+			}
+			o.removeDuplicates()
+			o.removeDeadInstructions()
+			stored := system.fingerprint != nil
+			o.rebuild()
+			system.fingerprint = nil
+			if stored {
+				if err := StoreFingerprint(cs); err != nil {
+					return stats, err
+				}
+			}
+		}
+	}
+	stats.NbConstraintsAfter = system.GetNbConstraints()
+	stats.NbInternalVariablesAfter = system.GetNbInternalVariables()
+	stats.NbInstructionsAfter = system.GetNbInstructions()
+	return stats, nil
+}
+
+type instructionKind uint8
+
+const (
+	kindR1C instructionKind = iota
+	kindSparseR1C
+	kindHint
+)
+
+// optInstruction is a decompressed instruction
+type optInstruction struct {
+	bID       BlueprintID
+	kind      instructionKind
+	cID       int // constraint ID in the original system, -1 for hints
+	r1c       R1C
+	sparse    SparseR1C
+	hint      HintMapping
+	removed   bool
+	protected bool // part of a commitment
+}
+
+// decompress sets inst to the decompressed instruction pi. It returns false if
+// the blueprint of pi is neither a constraint nor a hint.
+func (system *System) decompress(pi PackedInstruction, inst *optInstruction) bool {
+	inst.bID = pi.BlueprintID
+	inst.cID = int(pi.ConstraintOffset)
+	unpacked := pi.Unpack(system)
+	switch b := system.Blueprints[pi.BlueprintID].(type) {
+	case BlueprintR1C:
+		inst.kind = kindR1C
+		b.DecompressR1C(&inst.r1c, unpacked)
+	case BlueprintSparseR1C:
+		inst.kind = kindSparseR1C
+		b.DecompressSparseR1C(&inst.sparse, unpacked)
+		inst.protected = inst.sparse.Commitment != NOT
+	case BlueprintHint:
+		inst.kind = kindHint
+		inst.cID = -1
+		b.DecompressHint(&inst.hint, unpacked)
+	default:
+		return false
+	}
+	return true
+}
+
+// debugInfoID returns the ID of the debug info attached to the constraint cID,
+// or -1 if there is none.
+func (system *System) debugInfoID(cID int) int {
+	if cID >= 0 {
+		if id, ok := system.MDebug[cID]; ok {
+			return id
+		}
+	}
+	return -1
+}
+
+// walk calls f on the wires referenced by the instruction, hint outputs excluded
+func (inst *optInstruction) walk(f func(wireID *uint32)) {
+	switch inst.kind {
+	case kindR1C:
+		for _, l := range [3]LinearExpression{inst.r1c.L, inst.r1c.R, inst.r1c.O} {
+			for i := range l {
+				f(&l[i].VID)
+			}
+		}
+	case kindSparseR1C:
+		f(&inst.sparse.XA)
+		f(&inst.sparse.XB)
+		f(&inst.sparse.XC)
+	case kindHint:
+		for _, l := range inst.hint.Inputs {
+			for i := range l {
+				f(&l[i].VID)
+			}
+		}
+	}
+}
+
+type optimizer struct {
+	cs     ConstraintSystem
+	system *System
+
+	instructions []optInstruction
+	nbInputs     uint32 // number of public and secret wires
+	nbWires      int
+
+	// oneWire is set for R1CS whose first public wire is the constant 1, the
+	// constant terms of the linear expressions then reference this wire
+	oneWire bool
+
+	protected []bool // wires part of a commitment or referenced by a log
+	debugInfo []int  // debug info ID of each instruction, -1 if none
+}
+
+func newOptimizer(cs ConstraintSystem, system *System) (*optimizer, bool) {
+	o := optimizer{
+		cs:           cs,
+		system:       system,
+		instructions: make([]optInstruction, len(system.Instructions)),
+		debugInfo:    make([]int, len(system.Instructions)),
+		nbInputs:     uint32(len(system.Public) + len(system.Secret)),
+		nbWires:      len(system.Public) + len(system.Secret) + system.NbInternalVariables,
+		oneWire:      system.Type == SystemR1CS && len(system.Public) != 0 && system.Public[0] == "1",
+	}
+
+	for i, pi := range system.Instructions {
+		inst := &o.instructions[i]
+		if !system.decompress(pi, inst) {
+			// we don't know which wires the instruction references
+			return nil, false
+		}
+		o.debugInfo[i] = system.debugInfoID(inst.cID)
+	}
+
+	o.protected = make([]bool, o.nbWires)
+	protectLinearExpression := func(l LinearExpression) {
+		for _, t := range l {
+			if !t.IsConstant() {
+				o.protected[t.VID] = true
+			}
+		}
+	}
+	for _, l := range system.Logs {
+		for _, le := range l.ToResolve {
+			protectLinearExpression(le)
+		}
+	}
+	switch c := system.CommitmentInfo.(type) {
+	case Groth16Commitments:
+		for i := range c {
+			for _, w := range c[i].PublicAndCommitmentCommitted {
+				o.protected[w] = true
+			}
+			for _, w := range c[i].PrivateCommitted {
+				o.protected[w] = true
+			}
+			o.protected[c[i].CommitmentIndex] = true
+		}
+	case PlonkCommitments:
+		protectedConstraints := make(map[int]bool)
+		for i := range c {
+			for _, cID := range c[i].Committed {
+				protectedConstraints[cID] = true
+			}
+			protectedConstraints[c[i].CommitmentIndex] = true
+		}
+		for i := range o.instructions {
+			if protectedConstraints[o.instructions[i].cID] {
+				o.instructions[i].protected = true
+			}
+		}
+	}
+
+	return &o, true
+}
+
+// definitions returns, for each instruction, the internal wires it solves,
+// that is the wires it references before any other instruction. As for the
+// instruction tree, all the unsolved wires of a constraint are considered as
+// solved by it.
+func (o *optimizer) definitions() [][]uint32 {
+	solved := make([]bool, o.nbWires)
+	res := make([][]uint32, len(o.instructions))
+	for i := range o.instructions {
+		inst := &o.instructions[i]
+		if inst.removed {
+			continue
+		}
+		if inst.kind == kindHint {
+			for w := inst.hint.OutputRange.Start; w < inst.hint.OutputRange.End; w++ {
+				res[i] = append(res[i], w)
+				solved[w] = true
+			}
+			continue
+		}
+		inst.walk(func(w *uint32) {
+			if *w < o.nbInputs || *w == math.MaxUint32 || solved[*w] {
+				return
+			}
+			solved[*w] = true
+			res[i] = append(res[i], *w)
+		})
+	}
+	return res
+}
+
+// substituteLinearWires replaces the internal wires defined by a linear R1C
+// by their definition, when their value is constant or they are used once.
+// The defining constraints are then removed.
+func (o *optimizer) substituteLinearWires() {
+	defs := o.definitions()
+
+	// number of occurrences of each wire, and the instructions referencing it
+	occurrences := make([]int, o.nbWires)
+	uses := make([][]int, o.nbWires)
+	count := func(i int, delta int) {
+		o.instructions[i].walk(func(w *uint32) {
+			if *w == math.MaxUint32 {
+				return
+			}
+			occurrences[*w] += delta
+			if delta > 0 {
+				if n := len(uses[*w]); n == 0 || uses[*w][n-1] != i {
+					uses[*w] = append(uses[*w], i)
+				}
+			}
+		})
+	}
+	for i := range o.instructions {
+		count(i, 1)
+	}
+
+	substituted := make(map[uint32]LinearExpression)
+	for i := range o.instructions {
+		def := &o.instructions[i]
+		if def.removed || def.protected || def.kind != kindR1C || len(defs[i]) != 1 {
+			continue
+		}
+		w := defs[i][0]
+		if o.protected[w] {
+			continue
+		}
+		expr, ok := o.linearDefinition(&def.r1c, w)
+		if !ok {
+			continue
+		}
+		nbUses := occurrences[w]
+		def.walk(func(v *uint32) {
+			if *v == w {
+				nbUses--
+			}
+		})
+		if nbUses != 1 && !o.isConstant(expr) {
+			continue
+		}
+
+		for _, j := range uses[w] {
+			inst := &o.instructions[j]
+			if j == i || inst.removed {
+				continue
+			}
+			count(j, -1)
+			switch inst.kind {
+			case kindR1C:
+				inst.r1c.L = o.replace(inst.r1c.L, w, expr)
+				inst.r1c.R = o.replace(inst.r1c.R, w, expr)
+				inst.r1c.O = o.replace(inst.r1c.O, w, expr)
+			case kindHint:
+				for k := range inst.hint.Inputs {
+					inst.hint.Inputs[k] = o.replace(inst.hint.Inputs[k], w, expr)
+				}
+			}
+			count(j, 1)
+		}
+		count(i, -1)
+		def.removed = true
+		substituted[w] = expr
+	}
+
+	// the constraints left with constants only are either always satisfied, or
+	// never; we only keep the latter for the solver to report them
+	for i := range o.instructions {
+		inst := &o.instructions[i]
+		if inst.removed || inst.protected || inst.kind != kindR1C {
+			continue
+		}
+		r := &inst.r1c
+		if o.isConstant(r.L) && o.isConstant(r.R) && o.isConstant(r.O) {
+			lr := o.cs.Mul(o.constantValue(r.L), o.constantValue(r.R))
+			if diff := o.cs.Sub(lr, o.constantValue(r.O)); diff.IsZero() {
+				inst.removed = true
+			}
+		}
+	}
+
+	// the debug information may reference the substituted wires
+	for i := range o.system.DebugInfo {
+		for j := range o.system.DebugInfo[i].ToResolve {
+			o.system.DebugInfo[i].ToResolve[j] = o.replaceAll(o.system.DebugInfo[i].ToResolve[j], substituted)
+		}
+	}
+}
+
+// removeDuplicates removes the constraints identical to a previous one.
+func (o *optimizer) removeDuplicates() {
+	defs := o.definitions()
+	seen := make(map[string]struct{})
+	buf := getBuffer()
+	defer putBuffer(buf)
+	for i := range o.instructions {
+		inst := &o.instructions[i]
+		if inst.removed || inst.protected || inst.kind == kindHint || len(defs[i]) != 0 {
+			continue
+		}
+		*buf = (*buf)[:0]
+		*buf = append(*buf, uint32(inst.bID))
+		o.compress(inst, buf)
+		key := make([]byte, 4*len(*buf))
+		for k, v := range *buf {
+			binary.LittleEndian.PutUint32(key[4*k:], v)
+		}
+		if _, ok := seen[string(key)]; ok {
+			inst.removed = true
+			continue
+		}
+		seen[string(key)] = struct{}{}
+	}
+}
+
+// removeDeadInstructions removes the instructions defining internal wires
+// which are not used anywhere, if they don't constrain the other wires.
+func (o *optimizer) removeDeadInstructions() {
+	defs := o.definitions()
+	live := make([]bool, o.nbWires)
+	copy(live, o.protected)
+
+	markLinearExpression := func(l LinearExpression) {
+		for _, t := range l {
+			if !t.IsConstant() {
+				live[t.VID] = true
+			}
+		}
+	}
+
+	for i := len(o.instructions) - 1; i >= 0; i-- {
+		inst := &o.instructions[i]
+		if inst.removed {
+			continue
+		}
+		keep := inst.protected || len(defs[i]) == 0 || !o.removable(inst, defs[i])
+		for _, w := range defs[i] {
+			keep = keep || live[w]
+		}
+		if !keep {
+			inst.removed = true
+			continue
+		}
+		inst.walk(func(w *uint32) {
+			if *w != math.MaxUint32 {
+				live[*w] = true
+			}
+		})
+		if id := o.debugInfo[i]; id >= 0 {
+			for _, l := range o.system.DebugInfo[id].ToResolve {
+				markLinearExpression(l)
+			}
+		}
+	}
+}
+
+// removable returns true if the instruction is satisfiable for any value of
+// the wires it doesn't define, that is, if it doesn't constrain them.
+func (o *optimizer) removable(inst *optInstruction, defs []uint32) bool {
+	switch inst.kind {
+	case kindHint:
+		return true
+	case kindR1C:
+		if len(defs) != 1 {
+			return false
+		}
+		if _, ok := o.linearDefinition(&inst.r1c, defs[0]); ok {
+			return true
+		}
+		// L⋅R = a⋅w + O', a ≠ 0
+		w, r := defs[0], &inst.r1c
+		if references(r.L, w) || references(r.R, w) {
+			return false
+		}
+		var a Element
+		for _, t := range r.O {
+			if t.VID == w {
+				a = o.cs.Add(a, o.cs.GetCoefficient(int(t.CID)))
+			}
+		}
+		return !a.IsZero()
+	case kindSparseR1C:
+		if len(defs) != 1 {
+			return false
+		}
+		w, c := defs[0], &inst.sparse
+		qM := o.cs.GetCoefficient(int(c.QM))
+		if !qM.IsZero() && (c.XA == w || c.XB == w) {
+			return false
+		}
+		var coeff Element
+		for _, t := range [3]Term{{CID: c.QL, VID: c.XA}, {CID: c.QR, VID: c.XB}, {CID: c.QO, VID: c.XC}} {
+			if t.VID == w {
+				coeff = o.cs.Add(coeff, o.cs.GetCoefficient(int(t.CID)))
+			}
+		}
+		return !coeff.IsZero()
+	}
+	return false
+}
+
+// linearDefinition returns the linear expression equal to w if r is a linear
+// equation in which w has a non-zero coefficient.
+func (o *optimizer) linearDefinition(r *R1C, w uint32) (LinearExpression, bool) {
+	var e LinearExpression
+	if o.isConstant(r.L) {
+		e = o.axpy(nil, r.R, o.constantValue(r.L))
+	} else if o.isConstant(r.R) {
+		e = o.axpy(nil, r.L, o.constantValue(r.R))
+	} else {
+		return nil, false
+	}
+	// e - O == 0
+	e = o.axpy(e, r.O, o.cs.Neg(o.cs.One()))
+
+	var a Element
+	rest := make(LinearExpression, 0, len(e))
+	for _, t := range e {
+		if t.VID == w {
+			a = o.cs.Add(a, o.cs.GetCoefficient(int(t.CID)))
+		} else {
+			rest = append(rest, t)
+		}
+	}
+	aInv, ok := o.cs.Inverse(a)
+	if !ok {
+		return nil, false
+	}
+	// w = -rest / a
+	return o.axpy(nil, rest, o.cs.Neg(aInv)), true
+}
+
+// references returns true if l has a term on w
+func references(l LinearExpression, w uint32) bool {
+	for _, t := range l {
+		if t.VID == w {
+			return true
+		}
+	}
+	return false
+}
+
+// isConstant returns true if l only references the constant wire
+func (o *optimizer) isConstant(l LinearExpression) bool {
+	for _, t := range l {
+		if !(t.IsConstant() || (o.oneWire && t.VID == 0) || t.CID == CoeffIdZero) {
+			return false
+		}
+	}
+	return true
+}
+
+// constantValue returns the value of a linear expression for which isConstant
+// returns true
+func (o *optimizer) constantValue(l LinearExpression) Element {
+	var res Element
+	for _, t := range l {
+		res = o.cs.Add(res, o.cs.GetCoefficient(int(t.CID)))
+	}
+	return res
+}
+
+// axpy returns dst + s⋅src, merging the terms on the same wire
+func (o *optimizer) axpy(dst, src LinearExpression, s Element) LinearExpression {
+	res := make(LinearExpression, len(dst), len(dst)+len(src))
+	copy(res, dst)
+	index := make(map[uint32]int, len(res))
+	for i, t := range res {
+		index[o.wireKey(t)] = i
+	}
+	for _, t := range src {
+		v := o.cs.Mul(o.cs.GetCoefficient(int(t.CID)), s)
+		key := o.wireKey(t)
+		if i, ok := index[key]; ok {
+			v = o.cs.Add(v, o.cs.GetCoefficient(int(res[i].CID)))
+			res[i].CID = o.cs.AddCoeff(v)
+			continue
+		}
+		index[key] = len(res)
+		res = append(res, Term{CID: o.cs.AddCoeff(v), VID: t.VID})
+	}
+
+	// remove the cancelled terms
+	n := 0
+	for _, t := range res {
+		if t.CID != CoeffIdZero {
+			res[n] = t
+			n++
+		}
+	}
+	return res[:n]
+}
+
+// wireKey returns the wire of t, the constant terms being all mapped to the
+// same key
+func (o *optimizer) wireKey(t Term) uint32 {
+	if o.oneWire && t.VID == 0 {
+		return math.MaxUint32
+	}
+	return t.VID
+}
+
+// replace substitutes expr to w in l
+func (o *optimizer) replace(l LinearExpression, w uint32, expr LinearExpression) LinearExpression {
+	var a Element
+	found := false
+	rest := make(LinearExpression, 0, len(l))
+	for _, t := range l {
+		if t.VID == w {
+			a = o.cs.Add(a, o.cs.GetCoefficient(int(t.CID)))
+			found = true
+		} else {
+			rest = append(rest, t)
+		}
+	}
+	if !found {
+		return l
+	}
+	return o.axpy(rest, expr, a)
+}
+
+// replaceAll substitutes the expressions of substituted to their wire in l
+func (o *optimizer) replaceAll(l LinearExpression, substituted map[uint32]LinearExpression) LinearExpression {
+	for _, t := range l {
+		if expr, ok := substituted[t.VID]; ok {
+			// expr doesn't reference substituted wires defined after t.VID, but
+			// may reference wires substituted before
+			return o.replaceAll(o.replace(l, t.VID, expr), substituted)
+		}
+	}
+	return l
+}
+
+// compress encodes the instruction in buf
+func (o *optimizer) compress(inst *optInstruction, buf *[]uint32) {
+	switch b := o.system.Blueprints[inst.bID].(type) {
+	case BlueprintR1C:
+		b.CompressR1C(&inst.r1c, buf)
+	case BlueprintSparseR1C:
+		b.CompressSparseR1C(&inst.sparse, buf)
+	case BlueprintHint:
+		b.CompressHint(inst.hint, buf)
+	}
+}
+
+// rebuild replaces the system with its remaining instructions, renumbering
+// the internal wires.
+func (o *optimizer) rebuild() {
+	old := o.system
+
+	// the internal wires still referenced keep their relative order
+	used := make([]bool, o.nbWires)
+	copy(used, o.protected)
+	markLinearExpression := func(l LinearExpression) {
+		for _, t := range l {
+			if !t.IsConstant() {
+				used[t.VID] = true
+			}
+		}
+	}
+	for i := range o.instructions {
+		inst := &o.instructions[i]
+		if inst.removed {
+			continue
+		}
+		inst.walk(func(w *uint32) {
+			if *w != math.MaxUint32 {
+				used[*w] = true
+			}
+		})
+		if inst.kind == kindHint {
+			for w := inst.hint.OutputRange.Start; w < inst.hint.OutputRange.End; w++ {
+				used[w] = true
+			}
+		}
+		if id := o.debugInfo[i]; id >= 0 {
+			for _, l := range old.DebugInfo[id].ToResolve {
+				markLinearExpression(l)
+			}
+		}
+	}
+	wireID := make([]uint32, o.nbWires)
+	nbInternal := 0
+	for w := range wireID {
+		if uint32(w) < o.nbInputs {
+			wireID[w] = uint32(w)
+		} else if used[w] {
+			wireID[w] = o.nbInputs + uint32(nbInternal)
+			nbInternal++
+		}
+	}
+	remap := func(w *uint32) {
+		if *w != math.MaxUint32 {
+			*w = wireID[*w]
+		}
+	}
+	remapLinearExpression := func(l LinearExpression) {
+		for i := range l {
+			remap(&l[i].VID)
+		}
+	}
+
+	res := NewSystem(old.q, len(o.instructions), old.Type)
+	res.GnarkVersion = old.GnarkVersion
+	res.ScalarField = old.ScalarField
+	res.Blueprints = old.Blueprints
+	res.genericHint = old.genericHint
+	res.Public = old.Public
+	res.Secret = old.Secret
+	res.SymbolTable = old.SymbolTable
+	res.GkrInfo = old.GkrInfo
+	for i := 0; i < nbInternal; i++ {
+		res.AddInternalVariable()
+	}
+
+	constraintID := make(map[int]int)
+	debugInfoID := make(map[int]int)
+	buf := getBuffer()
+	defer putBuffer(buf)
+	for i := range o.instructions {
+		inst := &o.instructions[i]
+		if inst.removed {
+			continue
+		}
+		inst.walk(remap)
+		if inst.kind == kindHint {
+			// the outputs are all used, they stay contiguous
+			nbOutputs := inst.hint.OutputRange.End - inst.hint.OutputRange.Start
+			remap(&inst.hint.OutputRange.Start)
+			inst.hint.OutputRange.End = inst.hint.OutputRange.Start + nbOutputs
+			if name, ok := old.MHintsDependencies[inst.hint.HintID]; ok {
+				res.MHintsDependencies[inst.hint.HintID] = name
+			}
+		}
+		*buf = (*buf)[:0]
+		o.compress(inst, buf)
+		cID := res.NbConstraints
+		res.AddInstruction(inst.bID, *buf)
+		if inst.cID < 0 {
+			continue
+		}
+		constraintID[inst.cID] = cID
+		if id := o.debugInfo[i]; id >= 0 {
+			newID, ok := debugInfoID[id]
+			if !ok {
+				d := old.DebugInfo[id]
+				for _, l := range d.ToResolve {
+					remapLinearExpression(l)
+				}
+				res.DebugInfo = append(res.DebugInfo, d)
+				newID = len(res.DebugInfo) - 1
+				debugInfoID[id] = newID
+			}
+			res.MDebug[cID] = newID
+		}
+	}
+
+	res.Logs = old.Logs
+	for i := range res.Logs {
+		for _, l := range res.Logs[i].ToResolve {
+			remapLinearExpression(l)
+		}
+	}
+
+	switch c := old.CommitmentInfo.(type) {
+	case Groth16Commitments:
+		for i := range c {
+			for j := range c[i].PublicAndCommitmentCommitted {
+				c[i].PublicAndCommitmentCommitted[j] = int(wireID[c[i].PublicAndCommitmentCommitted[j]])
+			}
+			for j := range c[i].PrivateCommitted {
+				c[i].PrivateCommitted[j] = int(wireID[c[i].PrivateCommitted[j]])
+			}
+			c[i].CommitmentIndex = int(wireID[c[i].CommitmentIndex])
+		}
+	case PlonkCommitments:
+		for i := range c {
+			for j := range c[i].Committed {
+				c[i].Committed[j] = constraintID[c[i].Committed[j]]
+			}
+			c[i].CommitmentIndex = constraintID[c[i].CommitmentIndex]
+		}
+	}
+	res.CommitmentInfo = old.CommitmentInfo
+
+	*o.system = res
+}
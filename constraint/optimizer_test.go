@@ -0,0 +1,224 @@
+package constraint_test
+
+import (
+	"fmt"
+	"math/big"
+	"sort"
+	"testing"
+
+	"github.com/consensys/gnark-crypto/ecc"
+	"github.com/consensys/gnark/backend"
+	"github.com/consensys/gnark/backend/groth16"
+	"github.com/consensys/gnark/backend/plonk"
+	"github.com/consensys/gnark/backend/witness"
+	"github.com/consensys/gnark/constraint"
+	"github.com/consensys/gnark/constraint/solver"
+	"github.com/consensys/gnark/frontend"
+	"github.com/consensys/gnark/frontend/cs/r1cs"
+	"github.com/consensys/gnark/frontend/cs/scs"
+	"github.com/consensys/gnark/internal/backend/circuits"
+	"github.com/consensys/gnark/test/unsafekzg"
+)
+
+func TestOptimizerEquivalence(t *testing.T) {
+	names := make([]string, 0, len(circuits.Circuits))
+	for name := range circuits.Circuits {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	builders := map[backend.ID]frontend.NewBuilder{backend.GROTH16: r1cs.NewBuilder, backend.PLONK: scs.NewBuilder}
+	for _, name := range names {
+		tc := circuits.Circuits[name]
+		field := ecc.BN254.ScalarField()
+		if len(tc.Curves) != 0 {
+			field = tc.Curves[0].ScalarField()
+		}
+		for b, newBuilder := range builders {
+			for _, level := range []constraint.OptimizationLevel{constraint.OptimizationBasic, constraint.OptimizationFull} {
+				t.Run(fmt.Sprintf("%s/%s/level=%d", name, b, level), func(t *testing.T) {
+					ccs, err := frontend.Compile(field, newBuilder, tc.Circuit)
+					if err != nil {
+						t.Fatal(err)
+					}
+					optimized, err := frontend.Compile(field, newBuilder, tc.Circuit, frontend.WithOptimizer(level))
+					if err != nil {
+						t.Fatal(err)
+					}
+					if optimized.GetNbConstraints() > ccs.GetNbConstraints() {
+						t.Fatalf("optimizer added constraints: %d > %d", optimized.GetNbConstraints(), ccs.GetNbConstraints())
+					}
+					if optimized.GetNbInternalVariables() > ccs.GetNbInternalVariables() {
+						t.Fatalf("optimizer added internal variables: %d > %d", optimized.GetNbInternalVariables(), ccs.GetNbInternalVariables())
+					}
+					checkSolve(t, optimized, field, tc)
+					checkProve(t, b, optimized, field, tc)
+				})
+			}
+		}
+	}
+}
+
+func checkSolve(t *testing.T, ccs constraint.ConstraintSystem, field *big.Int, tc circuits.TestCircuit) {
+	t.Helper()
+	for i, assignment := range tc.ValidAssignments {
+		w, err := frontend.NewWitness(assignment, field)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if _, err := ccs.Solve(w, solver.WithHints(tc.HintFunctions...)); err != nil {
+			t.Fatalf("valid assignment %d: %v", i, err)
+		}
+	}
+	for i, assignment := range tc.InvalidAssignments {
+		w, err := frontend.NewWitness(assignment, field)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if _, err := ccs.Solve(w, solver.WithHints(tc.HintFunctions...)); err == nil {
+			t.Fatalf("invalid assignment %d: solved", i)
+		}
+	}
+}
+
+// checkProve runs the setup of the backend b on ccs, and proves and verifies
+// the valid assignments of tc.
+func checkProve(t *testing.T, b backend.ID, ccs constraint.ConstraintSystem, field *big.Int, tc circuits.TestCircuit) {
+	t.Helper()
+	var prove func(w witness.Witness) error
+	opt := backend.WithSolverOptions(solver.WithHints(tc.HintFunctions...))
+	switch b {
+	case backend.GROTH16:
+		pk, vk, err := groth16.Setup(ccs)
+		if err != nil {
+			t.Fatal(err)
+		}
+		prove = func(w witness.Witness) error {
+			proof, err := groth16.Prove(ccs, pk, w, opt)
+			if err != nil {
+				return err
+			}
+			public, err := w.Public()
+			if err != nil {
+				return err
+			}
+			return groth16.Verify(proof, vk, public)
+		}
+	case backend.PLONK:
+		srs, srsLagrange, err := unsafekzg.NewSRS(ccs)
+		if err != nil {
+			t.Fatal(err)
+		}
+		pk, vk, err := plonk.Setup(ccs, srs, srsLagrange)
+		if err != nil {
+			t.Fatal(err)
+		}
+		prove = func(w witness.Witness) error {
+			proof, err := plonk.Prove(ccs, pk, w, opt)
+			if err != nil {
+				return err
+			}
+			public, err := w.Public()
+			if err != nil {
+				return err
+			}
+			return plonk.Verify(proof, vk, public)
+		}
+	}
+	for i, assignment := range tc.ValidAssignments {
+		w, err := frontend.NewWitness(assignment, field)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if err := prove(w); err != nil {
+			t.Fatalf("valid assignment %d: %v", i, err)
+		}
+	}
+}
+
+type optimizableCircuit struct {
+	X, Y frontend.Variable
+	Z    frontend.Variable `gnark:",public"`
+}
+
+func (c *optimizableCircuit) Define(api frontend.API) error {
+	// dead wire
+	api.Mul(c.X, c.Y, c.Z)
+
+	// duplicated constraint
+	xy := api.Mul(c.X, c.Y)
+	api.AssertIsEqual(xy, c.Z)
+	api.AssertIsEqual(xy, c.Z)
+
+	// linear expressions compressed into single use wires
+	s := api.Add(c.X, c.Y, c.Z, 1)
+	for i := 0; i < 4; i++ {
+		s = api.Add(s, c.X, c.Y, i)
+	}
+	api.AssertIsDifferent(api.Mul(s, s), 0)
+	return nil
+}
+
+func TestOptimizer(t *testing.T) {
+	field := ecc.BN254.ScalarField()
+	tc := circuits.TestCircuit{
+		ValidAssignments:   []frontend.Circuit{&optimizableCircuit{X: 2, Y: 3, Z: 6}},
+		InvalidAssignments: []frontend.Circuit{&optimizableCircuit{X: 2, Y: 3, Z: 5}},
+	}
+
+	for _, level := range []constraint.OptimizationLevel{constraint.OptimizationBasic, constraint.OptimizationFull} {
+		ccs, err := frontend.Compile(field, r1cs.NewBuilder, &optimizableCircuit{}, frontend.WithCompressThreshold(2))
+		if err != nil {
+			t.Fatal(err)
+		}
+		stats, err := constraint.Optimize(ccs, level)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if stats.NbConstraintsAfter != ccs.GetNbConstraints() {
+			t.Fatal("wrong stats")
+		}
+		// the dead multiplications and the duplicated assertion
+		if stats.NbConstraintsAfter > stats.NbConstraintsBefore-3 {
+			t.Fatalf("level %d: expected at least 3 constraints less, got %d -> %d", level, stats.NbConstraintsBefore, stats.NbConstraintsAfter)
+		}
+		if level == constraint.OptimizationFull {
+			basic, err := frontend.Compile(field, r1cs.NewBuilder, &optimizableCircuit{}, frontend.WithCompressThreshold(2), frontend.WithOptimizer(constraint.OptimizationBasic))
+			if err != nil {
+				t.Fatal(err)
+			}
+			if stats.NbConstraintsAfter >= basic.GetNbConstraints() {
+				t.Fatalf("substitution didn't remove constraints: %d >= %d", stats.NbConstraintsAfter, basic.GetNbConstraints())
+			}
+		}
+		checkSolve(t, ccs, field, tc)
+	}
+}
+
+func TestOptimizerSparseR1CS(t *testing.T) {
+	field := ecc.BN254.ScalarField()
+	tc := circuits.TestCircuit{
+		ValidAssignments:   []frontend.Circuit{&optimizableCircuit{X: 2, Y: 3, Z: 6}},
+		InvalidAssignments: []frontend.Circuit{&optimizableCircuit{X: 2, Y: 3, Z: 5}},
+	}
+
+	var stats [2]constraint.OptimizationStats
+	for i, level := range []constraint.OptimizationLevel{constraint.OptimizationBasic, constraint.OptimizationFull} {
+		ccs, err := frontend.Compile(field, scs.NewBuilder, &optimizableCircuit{})
+		if err != nil {
+			t.Fatal(err)
+		}
+		if stats[i], err = constraint.Optimize(ccs, level); err != nil {
+			t.Fatal(err)
+		}
+		checkSolve(t, ccs, field, tc)
+	}
+	// the dead multiplication and the duplicated assertion
+	if stats[0].NbConstraintsAfter >= stats[0].NbConstraintsBefore {
+		t.Fatalf("basic level didn't remove constraints: %d -> %d", stats[0].NbConstraintsBefore, stats[0].NbConstraintsAfter)
+	}
+	// the substitution of linear wires is not implemented for SparseR1CS
+	if stats[1] != stats[0] {
+		t.Fatalf("full level differs from basic level on SparseR1CS: %+v != %+v", stats[1], stats[0])
+	}
+}
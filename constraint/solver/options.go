@@ -1,7 +1,10 @@
 package solver
 
 import (
+	"context"
+	"errors"
 	"fmt"
+	"io"
 	"runtime"
 
 	"github.com/consensys/gnark/logger"
@@ -18,6 +21,10 @@ type Config struct {
 	HintFunctions map[HintID]Hint // defaults to all built-in hint functions
 	Logger        zerolog.Logger  // defaults to gnark.Logger
 	NbTasks       int             // defaults to runtime.NumCPU()
+	Ctx           context.Context // defaults to context.Background()
+	ProgressHook  func(done, total int)
+	Trace         *Trace    // records the solved wires when not nil
+	TraceWriter   io.Writer // receives the trace in JSON when not nil
 }
 
 // WithHints is a solver option that specifies additional hint functions to be used
@@ -79,12 +86,62 @@ func WithNbTasks(nbTasks int) Option {
 	}
 }
 
+// WithContext sets the context of the solver. The solver checks it between
+// the levels of the constraint system and returns ctx.Err() when it is done.
+// ctx must not be nil.
+func WithContext(ctx context.Context) Option {
+	return func(opt *Config) error {
+		if ctx == nil {
+			return errors.New("nil context")
+		}
+		opt.Ctx = ctx
+		return nil
+	}
+}
+
+// WithProgressHook sets a function called by the solver before solving each
+// level of the constraint system, with the number of solved levels out of the
+// total number of levels, and once all the levels are solved.
+func WithProgressHook(hook func(done, total int)) Option {
+	return func(opt *Config) error {
+		opt.ProgressHook = hook
+		return nil
+	}
+}
+
+// WithTrace records the execution of the solver and writes it to w in JSON
+// once the solver returns, whether it succeeded or not. See Trace for the
+// recorded information. Tracing forces the solver to run sequentially.
+func WithTrace(w io.Writer) Option {
+	return func(opt *Config) error {
+		if opt.Trace == nil {
+			opt.Trace = NewTrace()
+		}
+		opt.TraceWriter = w
+		return nil
+	}
+}
+
+// WithTraceRecorder records the execution of the solver in t, which can then
+// be queried for the values of the wires and the instructions leading to a
+// failing constraint. Tracing forces the solver to run sequentially.
+func WithTraceRecorder(t *Trace) Option {
+	return func(opt *Config) error {
+		if t == nil {
+			return errors.New("nil trace")
+		}
+		opt.Trace = t
+		return nil
+	}
+}
+
 // NewConfig returns a default SolverConfig with given prover options opts applied.
 func NewConfig(opts ...Option) (Config, error) {
 	log := logger.Logger()
 	opt := Config{Logger: log}
 	opt.HintFunctions = cloneHintRegistry()
 	opt.NbTasks = runtime.NumCPU()
+	opt.Ctx = context.Background()
 	for _, option := range opts {
 		if err := option(&opt); err != nil {
 			return Config{}, err
@@ -0,0 +1,161 @@
+package solver
+
+import (
+	"encoding/json"
+	"io"
+	"sort"
+)
+
+// Trace records the execution of the solver: the value of every solved wire,
+// with the instruction which solved it and its source location, and the
+// instruction which failed, if any.
+//
+// A Trace is filled by the solver when passed with WithTraceRecorder. Tracing
+// forces the solver to run sequentially, so that the instructions are
+// recorded in solving order. The zero value is an empty trace ready to use.
+type Trace struct {
+	Wires        []TracedWire        `json:"wires"`
+	Instructions []TracedInstruction `json:"instructions"`
+	Failure      *TracedFailure      `json:"failure,omitempty"`
+
+	wires        map[int]int    // wire ID to index in Wires
+	names        map[string]int // wire name to index in Wires
+	instructions map[int]int    // instruction ID to index in Instructions
+}
+
+// TracedWire is a wire solved by the solver.
+type TracedWire struct {
+	ID    int    `json:"id"`
+	Name  string `json:"name"`
+	Value string `json:"value"`
+	// Instruction is the ID of the instruction which solved the wire, or -1
+	// for the inputs of the circuit.
+	Instruction int `json:"instruction"`
+}
+
+// TracedInstruction is an instruction executed by the solver.
+type TracedInstruction struct {
+	ID        int    `json:"id"`
+	Blueprint string `json:"blueprint"`
+	// Location is the source location of the instruction, if the constraint
+	// system has debug information for it.
+	Location string `json:"location,omitempty"`
+	Inputs   []int  `json:"inputs,omitempty"`  // IDs of the wires read by the instruction
+	Outputs  []int  `json:"outputs,omitempty"` // IDs of the wires solved by the instruction
+}
+
+// TracedFailure is the instruction on which the solver failed.
+type TracedFailure struct {
+	Instruction int    `json:"instruction"`
+	Error       string `json:"error"`
+}
+
+// NewTrace returns an empty trace, to be passed to WithTraceRecorder.
+func NewTrace() *Trace {
+	return new(Trace)
+}
+
+// AddWire records a solved wire.
+func (t *Trace) AddWire(w TracedWire) {
+	if t.wires == nil {
+		t.wires = make(map[int]int)
+		t.names = make(map[string]int)
+	}
+	t.wires[w.ID] = len(t.Wires)
+	if _, ok := t.names[w.Name]; !ok {
+		t.names[w.Name] = len(t.Wires)
+	}
+	t.Wires = append(t.Wires, w)
+}
+
+// AddInstruction records an executed instruction.
+func (t *Trace) AddInstruction(inst TracedInstruction) {
+	if t.instructions == nil {
+		t.instructions = make(map[int]int)
+	}
+	t.instructions[inst.ID] = len(t.Instructions)
+	t.Instructions = append(t.Instructions, inst)
+}
+
+// Wire returns the solved wire with the given name: the name of a public or
+// secret input in the circuit schema, or v0, v1, ... for the internal wires.
+func (t *Trace) Wire(name string) (TracedWire, bool) {
+	i, ok := t.names[name]
+	if !ok {
+		return TracedWire{}, false
+	}
+	return t.Wires[i], true
+}
+
+// Value returns the value of the solved wire with the given name, see Wire.
+func (t *Trace) Value(name string) (string, bool) {
+	w, ok := t.Wire(name)
+	return w.Value, ok
+}
+
+// Chain returns the instructions the instruction iID depends on, directly
+// or not, followed by iID itself, in solving order.
+func (t *Trace) Chain(iID int) []TracedInstruction {
+	idx, ok := t.instructions[iID]
+	if !ok {
+		return nil
+	}
+	visited := map[int]bool{idx: true}
+	res := []int{idx}
+	for todo := []int{idx}; len(todo) != 0; {
+		inst := &t.Instructions[todo[len(todo)-1]]
+		todo = todo[:len(todo)-1]
+		for _, in := range inst.Inputs {
+			w, ok := t.wires[in]
+			if !ok || t.Wires[w].Instruction == -1 {
+				continue
+			}
+			if dep, ok := t.instructions[t.Wires[w].Instruction]; ok && !visited[dep] {
+				visited[dep] = true
+				res = append(res, dep)
+				todo = append(todo, dep)
+			}
+		}
+	}
+
+	// the indexes in Instructions are in solving order
+	sort.Ints(res)
+	chain := make([]TracedInstruction, len(res))
+	for i, idx := range res {
+		chain[i] = t.Instructions[idx]
+	}
+	return chain
+}
+
+// FailureChain returns the chain of instructions leading to the failing
+// instruction, see Chain. It returns nil if the solver didn't fail.
+func (t *Trace) FailureChain() []TracedInstruction {
+	if t.Failure == nil {
+		return nil
+	}
+	return t.Chain(t.Failure.Instruction)
+}
+
+// WriteJSON writes the trace to w in JSON.
+func (t *Trace) WriteJSON(w io.Writer) error {
+	enc := json.NewEncoder(w)
+	enc.SetIndent("", "\t")
+	return enc.Encode(t)
+}
+
+// ReadTrace reads a trace written with WriteJSON.
+func ReadTrace(r io.Reader) (*Trace, error) {
+	t := NewTrace()
+	var decoded Trace
+	if err := json.NewDecoder(r).Decode(&decoded); err != nil {
+		return nil, err
+	}
+	for _, w := range decoded.Wires {
+		t.AddWire(w)
+	}
+	for _, inst := range decoded.Instructions {
+		t.AddInstruction(inst)
+	}
+	t.Failure = decoded.Failure
+	return t, nil
+}
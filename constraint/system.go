@@ -6,6 +6,7 @@ import (
 
 	"github.com/consensys/gnark/backend/witness"
 	"github.com/consensys/gnark/constraint/solver"
+	"github.com/consensys/gnark/debug"
 )
 
 // ConstraintSystem interface that all constraint systems implement.
@@ -77,7 +78,28 @@ type ConstraintSystem interface {
 
 	GetInstruction(int) Instruction
 
+	// GetInstructionBlueprint returns the blueprint of the instruction at index id.
+	GetInstructionBlueprint(id int) Blueprint
+
 	GetCoefficient(i int) Element
+
+	// GetDebugInfo returns the debug information attached to the constraint
+	// cID, if any.
+	GetDebugInfo(cID int) (LogEntry, bool)
+
+	// GetSymbolTable returns the symbol table referenced by the debug
+	// information and the logs.
+	GetSymbolTable() *debug.SymbolTable
+
+	// GetHintName returns the name of the hint registered with the given ID,
+	// or an empty string if the system doesn't use it.
+	GetHintName(id solver.HintID) string
+
+	// StartTemplate, RecordTemplate and AddTemplate record a sequence of
+	// instructions and add it again with other input wires.
+	StartTemplate() TemplateStart
+	RecordTemplate(start TemplateStart, inputs []uint32) (*Template, error)
+	AddTemplate(t *Template, inputs []uint32) func(wireID uint32) uint32
 }
 
 type CustomizableSystem interface {
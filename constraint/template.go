@@ -0,0 +1,304 @@
+package constraint
+
+import (
+	"errors"
+	"fmt"
+	"reflect"
+)
+
+// TemplateStart marks the state of a constraint system before the instructions
+// recorded by RecordTemplate.
+type TemplateStart struct {
+	nbInstructions int
+	nbWires        int
+	nbLogs         int
+	nbCommitments  int
+	gkr            bool
+}
+
+// Template is a sequence of instructions recorded from a constraint system by
+// RecordTemplate, which AddTemplate adds again to the system with other input
+// wires.
+type Template struct {
+	inputs       []uint32 // the input wires of the recorded instructions
+	start        uint32   // the first wire created by the recorded instructions
+	instructions []templateInstruction
+	nbWires      int // number of wires created after the last instruction
+}
+
+type templateInstruction struct {
+	optInstruction
+	nbWires   int // number of wires created before the instruction
+	debugInfo int // debug info ID, -1 if none
+}
+
+// StartTemplate returns the current state of the system, to record the
+// instructions added from now on with RecordTemplate.
+func (system *System) StartTemplate() TemplateStart {
+	return TemplateStart{
+		nbInstructions: len(system.Instructions),
+		nbWires:        system.GetNbPublicVariables() + system.GetNbSecretVariables() + system.NbInternalVariables,
+		nbLogs:         len(system.Logs),
+		nbCommitments:  len(system.CommitmentInfo.CommitmentIndexes()),
+		gkr:            system.GkrInfo.Is(),
+	}
+}
+
+// RecordTemplate returns the template of the instructions added since start.
+// inputs are the wires, created before start, which differ between the
+// instances of the template; the other wires created before start are kept as
+// is.
+//
+// It returns an error if the instructions can't be replayed: if the system
+// has been given logs, commitments or a GKR sub-circuit since start, or if
+// some instructions are neither constraints nor hints.
+func (system *System) RecordTemplate(start TemplateStart, inputs []uint32) (*Template, error) {
+	if len(system.Logs) != start.nbLogs {
+		return nil, errors.New("template with logs")
+	}
+	if len(system.CommitmentInfo.CommitmentIndexes()) != start.nbCommitments || system.GkrInfo.Is() != start.gkr {
+		return nil, errors.New("template with commitments")
+	}
+
+	t := &Template{
+		inputs:       append([]uint32(nil), inputs...),
+		start:        uint32(start.nbWires),
+		instructions: make([]templateInstruction, len(system.Instructions)-start.nbInstructions),
+	}
+	next := start.nbWires // the next wire to be created
+	for i, pi := range system.Instructions[start.nbInstructions:] {
+		inst := &t.instructions[i]
+		if !system.decompress(pi, &inst.optInstruction) {
+			return nil, fmt.Errorf("template with a %T instruction", system.Blueprints[pi.BlueprintID])
+		}
+		if inst.protected {
+			return nil, errors.New("template with commitments")
+		}
+		inst.debugInfo = system.debugInfoID(inst.cID)
+		inst.nbWires = int(pi.WireOffset) - next
+		next = int(pi.WireOffset) + system.Blueprints[pi.BlueprintID].NbOutputs(pi.Unpack(system))
+	}
+	t.nbWires = system.GetNbPublicVariables() + system.GetNbSecretVariables() + system.NbInternalVariables - next
+
+	return t, nil
+}
+
+// AddTemplate adds the instructions of the template t, with the given input
+// wires in place of the recorded ones. It returns the function mapping the
+// wires of the recorded instructions to the wires of the added ones.
+func (system *System) AddTemplate(t *Template, inputs []uint32) func(wireID uint32) uint32 {
+	if len(inputs) != len(t.inputs) {
+		panic(fmt.Sprintf("template with %d inputs instantiated with %d", len(t.inputs), len(inputs)))
+	}
+	return system.addTemplate(t, inputs, nil)
+}
+
+// AppendSystem adds to dst the instructions added to src since start, src
+// being a constraint system whose wires created before start are those of
+// dst. It returns the function mapping the wires of src to the wires of dst.
+//
+// The coefficients, hint names and debug information of the instructions are
+// copied to dst. It returns an error if RecordTemplate fails on src, or if the
+// blueprints of the instructions differ between src and dst.
+func AppendSystem(dst, src ConstraintSystem, start TemplateStart) (func(wireID uint32) uint32, error) {
+	d, ok := dst.(interface{ core() *System })
+	if !ok {
+		return nil, fmt.Errorf("unsupported constraint system %T", dst)
+	}
+	s, ok := src.(interface{ core() *System })
+	if !ok {
+		return nil, fmt.Errorf("unsupported constraint system %T", src)
+	}
+	t, err := s.core().RecordTemplate(start, nil)
+	if err != nil {
+		return nil, err
+	}
+	checked := make(map[BlueprintID]bool)
+	for i := range t.instructions {
+		bID := t.instructions[i].bID
+		if checked[bID] {
+			continue
+		}
+		if int(bID) >= len(d.core().Blueprints) || reflect.TypeOf(d.core().Blueprints[bID]) != reflect.TypeOf(s.core().Blueprints[bID]) {
+			return nil, fmt.Errorf("blueprint %d differs between the constraint systems", bID)
+		}
+		checked[bID] = true
+	}
+
+	f := &foreignSystem{
+		src:        src,
+		dst:        dst,
+		system:     s.core(),
+		coeffs:     make(map[uint32]uint32),
+		debugInfos: make(map[int]int),
+		locations:  make(map[int]int),
+	}
+	return d.core().addTemplate(t, nil, f), nil
+}
+
+// foreignSystem translates the coefficients and debug information of the
+// instructions recorded from another constraint system.
+type foreignSystem struct {
+	src, dst   ConstraintSystem
+	system     *System // the core of src
+	coeffs     map[uint32]uint32
+	debugInfos map[int]int
+	locations  map[int]int
+}
+
+// coeff returns the ID in dst of the coefficient cID of src.
+func (f *foreignSystem) coeff(cID uint32) uint32 {
+	if c, ok := f.coeffs[cID]; ok {
+		return c
+	}
+	c := f.dst.AddCoeff(f.src.GetCoefficient(int(cID)))
+	f.coeffs[cID] = c
+	return c
+}
+
+// debugInfo adds to dst the debug information id of src and returns its ID.
+func (f *foreignSystem) debugInfo(dst *System, id int, remap func(uint32) uint32) int {
+	if d, ok := f.debugInfos[id]; ok {
+		return d
+	}
+	l := f.system.DebugInfo[id]
+	entry := LogEntry{
+		Caller:    l.Caller,
+		Format:    l.Format,
+		ToResolve: make([]LinearExpression, len(l.ToResolve)),
+		Stack:     make([]int, len(l.Stack)),
+	}
+	for i, le := range l.ToResolve {
+		entry.ToResolve[i] = f.linearExpression(nil, le, remap)
+	}
+	for i, lID := range l.Stack {
+		d, ok := f.locations[lID]
+		if !ok {
+			d = dst.SymbolTable.ImportLocation(&f.system.SymbolTable, lID)
+			f.locations[lID] = d
+		}
+		entry.Stack[i] = d
+	}
+	dst.DebugInfo = append(dst.DebugInfo, entry)
+	f.debugInfos[id] = len(dst.DebugInfo) - 1
+	return len(dst.DebugInfo) - 1
+}
+
+// linearExpression appends to dst the terms of l with their wires mapped by
+// remap, and their coefficients translated if f is not nil.
+func (f *foreignSystem) linearExpression(dst, l LinearExpression, remap func(uint32) uint32) LinearExpression {
+	n := len(dst)
+	dst = remapLinearExpression(dst, l, remap)
+	if f != nil {
+		for i := n; i < len(dst); i++ {
+			dst[i].CID = f.coeff(dst[i].CID)
+		}
+	}
+	return dst
+}
+
+// addTemplate adds the instructions of the template t, recorded from the
+// system itself if f is nil.
+func (system *System) addTemplate(t *Template, inputs []uint32, f *foreignSystem) func(wireID uint32) uint32 {
+	remap := t.wireMapper(inputs, uint32(system.GetNbPublicVariables()+system.GetNbSecretVariables()+system.NbInternalVariables))
+
+	calldata := getBuffer()
+	var r1c R1C
+	var sparse SparseR1C
+	var hint HintMapping
+	for i := range t.instructions {
+		inst := &t.instructions[i]
+		for j := 0; j < inst.nbWires; j++ {
+			system.AddInternalVariable()
+		}
+
+		*calldata = (*calldata)[:0]
+		switch b := system.Blueprints[inst.bID]; inst.kind {
+		case kindR1C:
+			r1c.L = f.linearExpression(r1c.L[:0], inst.r1c.L, remap)
+			r1c.R = f.linearExpression(r1c.R[:0], inst.r1c.R, remap)
+			r1c.O = f.linearExpression(r1c.O[:0], inst.r1c.O, remap)
+			b.(BlueprintR1C).CompressR1C(&r1c, calldata)
+		case kindSparseR1C:
+			sparse = inst.sparse
+			// the wires of unused terms are left as is
+			if sparse.QL != CoeffIdZero || sparse.QM != CoeffIdZero {
+				sparse.XA = remap(sparse.XA)
+			}
+			if sparse.QR != CoeffIdZero || sparse.QM != CoeffIdZero {
+				sparse.XB = remap(sparse.XB)
+			}
+			if sparse.QO != CoeffIdZero {
+				sparse.XC = remap(sparse.XC)
+			}
+			if f != nil {
+				sparse.QL, sparse.QR, sparse.QO = f.coeff(sparse.QL), f.coeff(sparse.QR), f.coeff(sparse.QO)
+				sparse.QM, sparse.QC = f.coeff(sparse.QM), f.coeff(sparse.QC)
+			}
+			b.(BlueprintSparseR1C).CompressSparseR1C(&sparse, calldata)
+		case kindHint:
+			hint = inst.hint
+			hint.Inputs = make([]LinearExpression, len(inst.hint.Inputs))
+			for j, l := range inst.hint.Inputs {
+				hint.Inputs[j] = f.linearExpression(nil, l, remap)
+			}
+			if f != nil {
+				if _, ok := system.MHintsDependencies[hint.HintID]; !ok {
+					if name, ok := f.system.MHintsDependencies[hint.HintID]; ok {
+						system.MHintsDependencies[hint.HintID] = name
+					}
+				}
+			}
+			hint.OutputRange.Start = remap(inst.hint.OutputRange.Start)
+			hint.OutputRange.End = remap(inst.hint.OutputRange.End-1) + 1
+			b.(BlueprintHint).CompressHint(hint, calldata)
+		}
+
+		cID := system.NbConstraints
+		system.AddInstruction(inst.bID, *calldata)
+		if inst.debugInfo >= 0 {
+			if f != nil {
+				system.MDebug[cID] = f.debugInfo(system, inst.debugInfo, remap)
+			} else {
+				system.MDebug[cID] = inst.debugInfo
+			}
+		}
+	}
+	putBuffer(calldata)
+
+	for j := 0; j < t.nbWires; j++ {
+		system.AddInternalVariable()
+	}
+	return remap
+}
+
+// wireMapper returns the function mapping the wires of the recorded
+// instructions to the wires of an instance with the given inputs, whose first
+// created wire is start.
+func (t *Template) wireMapper(inputs []uint32, start uint32) func(uint32) uint32 {
+	m := make(map[uint32]uint32, len(inputs))
+	for i, w := range t.inputs {
+		m[w] = inputs[i]
+	}
+	return func(wireID uint32) uint32 {
+		if wireID >= t.start {
+			return wireID - t.start + start
+		}
+		if w, ok := m[wireID]; ok {
+			return w
+		}
+		return wireID
+	}
+}
+
+// remapLinearExpression appends to dst the terms of l with their wires mapped
+// by remap.
+func remapLinearExpression(dst, l LinearExpression, remap func(uint32) uint32) LinearExpression {
+	for _, t := range l {
+		if !t.IsConstant() {
+			t.VID = remap(t.VID)
+		}
+		dst = append(dst, t)
+	}
+	return dst
+}
@@ -0,0 +1,77 @@
+package constraint
+
+import (
+	"github.com/consensys/gnark/constraint/solver"
+	"golang.org/x/exp/slices"
+)
+
+// TraceInputs records the inputs of the circuit, solved with the given values,
+// in the solver trace.
+func (system *System) TraceInputs(t *solver.Trace, values []string) {
+	for i, v := range values {
+		t.AddWire(solver.TracedWire{
+			ID:          i,
+			Name:        system.VariableToString(i),
+			Value:       v,
+			Instruction: -1,
+		})
+	}
+}
+
+// TraceInstruction records the instruction iID, which solved the wires with the
+// given values, in the solver trace. err is the error returned by the solver
+// for the instruction, if any.
+func (system *System) TraceInstruction(t *solver.Trace, iID int, wires []int, values []string, err error) {
+	inst := system.GetInstruction(iID)
+	b := system.GetInstructionBlueprint(iID)
+
+	traced := solver.TracedInstruction{
+		ID:        iID,
+		Blueprint: blueprintName(b),
+		Location:  instructionLocation(system, &system.SymbolTable, b, inst),
+		Outputs:   append([]int(nil), wires...),
+	}
+
+	// the wires the instruction reads are the ones it references and doesn't
+	// solve.
+	var collector wireCollector
+	b.UpdateInstructionTree(inst, &collector)
+	for _, w := range collector.wires {
+		if !slices.Contains(traced.Inputs, w) && !slices.Contains(wires, w) {
+			traced.Inputs = append(traced.Inputs, w)
+		}
+	}
+	t.AddInstruction(traced)
+
+	for i, w := range wires {
+		t.AddWire(solver.TracedWire{
+			ID:          w,
+			Name:        system.VariableToString(w),
+			Value:       values[i],
+			Instruction: iID,
+		})
+	}
+
+	if err != nil {
+		t.Failure = &solver.TracedFailure{Instruction: iID, Error: err.Error()}
+	}
+}
+
+// wireCollector is an InstructionTree collecting the wires referenced by an
+// instruction.
+type wireCollector struct {
+	wires []int
+}
+
+func (c *wireCollector) HasWire(wireID uint32) bool {
+	if wireID != ^uint32(0) { // the constant wire
+		c.wires = append(c.wires, int(wireID))
+	}
+	return false
+}
+
+func (c *wireCollector) GetWireLevel(wireID uint32) Level {
+	return LevelUnset
+}
+
+func (c *wireCollector) InsertWire(wireID uint32, level Level) {}
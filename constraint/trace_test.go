@@ -0,0 +1,102 @@
+package constraint_test
+
+import (
+	"bytes"
+	"testing"
+
+	"github.com/consensys/gnark-crypto/ecc"
+	"github.com/consensys/gnark/constraint/solver"
+	"github.com/consensys/gnark/frontend"
+	"github.com/consensys/gnark/frontend/cs/r1cs"
+	"github.com/consensys/gnark/frontend/cs/scs"
+	"github.com/stretchr/testify/require"
+)
+
+type traceCircuit struct {
+	X, Y, W frontend.Variable
+	Z       frontend.Variable `gnark:",public"`
+}
+
+func (c *traceCircuit) Define(api frontend.API) error {
+	api.AssertIsDifferent(c.W, 0)
+	xy := api.Mul(c.X, c.Y)
+	api.AssertIsEqual(api.Mul(xy, xy), c.Z)
+	return nil
+}
+
+func TestTrace(t *testing.T) {
+	for _, newBuilder := range []frontend.NewBuilder{r1cs.NewBuilder, scs.NewBuilder} {
+		ccs, err := frontend.Compile(ecc.BN254.ScalarField(), newBuilder, &traceCircuit{})
+		require.NoError(t, err)
+
+		solve := func(z int) (*solver.Trace, error) {
+			w, err := frontend.NewWitness(&traceCircuit{X: 2, Y: 3, W: 1, Z: z}, ecc.BN254.ScalarField())
+			require.NoError(t, err)
+			trace := solver.NewTrace()
+			var buf bytes.Buffer
+			_, err = ccs.Solve(w, solver.WithTraceRecorder(trace), solver.WithTrace(&buf))
+
+			// the exported trace matches the recorded one
+			exported, errRead := solver.ReadTrace(&buf)
+			require.NoError(t, errRead)
+			require.Equal(t, trace.Wires, exported.Wires)
+			require.Equal(t, trace.Instructions, exported.Instructions)
+			require.Equal(t, trace.Failure, exported.Failure)
+			return exported, err
+		}
+
+		trace, err := solve(36)
+		require.NoError(t, err)
+		require.Nil(t, trace.Failure)
+		require.Nil(t, trace.FailureChain())
+		require.Len(t, trace.Wires, ccs.GetNbPublicVariables()+ccs.GetNbSecretVariables()+ccs.GetNbInternalVariables())
+		require.Len(t, trace.Instructions, ccs.GetNbInstructions())
+		for name, expected := range map[string]string{"X": "2", "Y": "3", "Z": "36"} {
+			v, ok := trace.Value(name)
+			require.True(t, ok, name)
+			require.Equal(t, expected, v, name)
+		}
+		w, ok := trace.Wire("X")
+		require.True(t, ok)
+		require.Equal(t, -1, w.Instruction)
+		_, ok = trace.Value("unknown")
+		require.False(t, ok)
+
+		// the failure depends on the product of X and Y, not on W
+		trace, err = solve(35)
+		require.Error(t, err)
+		require.NotNil(t, trace.Failure)
+		require.NotEmpty(t, trace.Failure.Error)
+		chain := trace.FailureChain()
+		require.NotEmpty(t, chain)
+		require.Equal(t, trace.Failure.Instruction, chain[len(chain)-1].ID)
+		require.Less(t, len(chain), len(trace.Instructions))
+
+		wX, _ := trace.Wire("X")
+		wW, _ := trace.Wire("W")
+		readsX, readsW := false, false
+		for _, inst := range chain {
+			for _, in := range inst.Inputs {
+				readsX = readsX || in == wX.ID
+				readsW = readsW || in == wW.ID
+			}
+		}
+		require.True(t, readsX)
+		require.False(t, readsW)
+	}
+}
+
+func TestTraceZeroValue(t *testing.T) {
+	ccs, err := frontend.Compile(ecc.BN254.ScalarField(), r1cs.NewBuilder, &traceCircuit{})
+	require.NoError(t, err)
+	w, err := frontend.NewWitness(&traceCircuit{X: 2, Y: 3, W: 1, Z: 35}, ecc.BN254.ScalarField())
+	require.NoError(t, err)
+
+	var trace solver.Trace
+	_, err = ccs.Solve(w, solver.WithTraceRecorder(&trace))
+	require.Error(t, err)
+	v, ok := trace.Value("Z")
+	require.True(t, ok)
+	require.Equal(t, "35", v)
+	require.NotEmpty(t, trace.FailureChain())
+}
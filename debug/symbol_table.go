@@ -111,3 +111,17 @@ func (st *SymbolTable) locationID(frame *runtime.Frame) int {
 
 	return lID
 }
+
+// ImportLocation adds the location lID of the table from, and returns its id.
+func (st *SymbolTable) ImportLocation(from *SymbolTable, lID int) int {
+	l := from.Locations[lID]
+	f := from.Functions[l.FunctionID]
+	fID, ok := st.mFunctions[f.Filename+f.SystemName]
+	if !ok {
+		st.Functions = append(st.Functions, f)
+		fID = len(st.Functions) - 1
+		st.mFunctions[f.Filename+f.SystemName] = fID
+	}
+	st.Locations = append(st.Locations, Location{FunctionID: fID, Line: l.Line})
+	return len(st.Locations) - 1
+}
@@ -5,6 +5,7 @@ import (
 	"fmt"
 	"math/big"
 	"reflect"
+	"runtime"
 
 	"github.com/consensys/gnark/constraint"
 	"github.com/consensys/gnark/debug"
@@ -54,21 +55,52 @@ func Compile(field *big.Int, newBuilder NewBuilder, circuit Circuit, opts ...Com
 
 	// parse the circuit builds a schema of the circuit
 	// and call circuit.Define() method to initialize a list of constraints in the compiler
-	if err = parseCircuit(builder, circuit); err != nil {
+	if err = parseCircuit(builder, circuit, opt.PublicInputHashing); err != nil {
 		log.Err(err).Msg("parsing circuit")
 		return nil, fmt.Errorf("parse circuit: %w", err)
 
 	}
 
 	// compile the circuit into its final form
-	return builder.Compile()
+	ccs, err := builder.Compile()
+	if err != nil {
+		return ccs, err
+	}
+
+	if opt.Optimizer != constraint.OptimizationNone {
+		stats, err := constraint.Optimize(ccs, opt.Optimizer)
+		if err != nil {
+			log.Err(err).Msg("optimizing constraint system")
+			return nil, fmt.Errorf("optimize: %w", err)
+		}
+		log.Info().
+			Int("nbConstraintsBefore", stats.NbConstraintsBefore).
+			Int("nbConstraintsAfter", stats.NbConstraintsAfter).
+			Int("nbInternalVariablesBefore", stats.NbInternalVariablesBefore).
+			Int("nbInternalVariablesAfter", stats.NbInternalVariablesAfter).
+			Msg("optimized constraint system")
+	}
+
+	// the system is complete, its fingerprint is computed once for the setups
+	// and the provers
+	if err := constraint.StoreFingerprint(ccs); err != nil {
+		return nil, fmt.Errorf("fingerprint: %w", err)
+	}
+	return ccs, nil
 }
 
-func parseCircuit(builder Builder, circuit Circuit) (err error) {
+func parseCircuit(builder Builder, circuit Circuit, publicInputHashing string) (err error) {
 	// ensure circuit.Define has pointer receiver
 	if reflect.ValueOf(circuit).Kind() != reflect.Ptr {
 		return errors.New("frontend.Circuit methods must be defined on pointer receiver")
 	}
+	var hasher PublicInputHasher
+	if publicInputHashing != "" {
+		var ok bool
+		if hasher, ok = getPublicInputHasher(publicInputHashing); !ok {
+			return fmt.Errorf("public input hasher %q not registered", publicInputHashing)
+		}
+	}
 
 	s, err := schema.Walk(circuit, tVariable, nil)
 	if err != nil {
@@ -101,7 +133,25 @@ func parseCircuit(builder Builder, circuit Circuit) (err error) {
 	}
 
 	// add public inputs first to compute correct offsets
-	_, err = schema.Walk(circuit, tVariable, variableAdder(schema.Public))
+	var digest Variable
+	var publicInputs []Variable
+	if hasher == nil {
+		_, err = schema.Walk(circuit, tVariable, variableAdder(schema.Public))
+	} else {
+		// the public inputs are secret inputs following the public digest
+		digest = builder.PublicVariable(schema.LeafInfo{Visibility: schema.Public, FullName: func() string { return "digest" }})
+		_, err = schema.Walk(circuit, tVariable, func(f schema.LeafInfo, tInput reflect.Value) error {
+			if !tInput.CanSet() {
+				return errors.New("can't set val " + f.FullName())
+			}
+			if f.Visibility == schema.Public {
+				v := builder.SecretVariable(f)
+				publicInputs = append(publicInputs, v)
+				tInput.Set(reflect.ValueOf(v))
+			}
+			return nil
+		})
+	}
 	if err != nil {
 		return err
 	}
@@ -123,6 +173,13 @@ func parseCircuit(builder Builder, circuit Circuit) (err error) {
 	if err = circuit.Define(builder); err != nil {
 		return fmt.Errorf("define circuit: %w", err)
 	}
+	if hasher != nil {
+		h, err := hasher(builder, publicInputs)
+		if err != nil {
+			return fmt.Errorf("hash public inputs: %w", err)
+		}
+		builder.AssertIsEqual(h, digest)
+	}
 	if err = callDeferred(builder); err != nil {
 		return fmt.Errorf("deferred: %w", err)
 	}
@@ -151,9 +208,13 @@ func defaultCompileConfig() CompileConfig {
 }
 
 type CompileConfig struct {
-	Capacity                  int
-	IgnoreUnconstrainedInputs bool
-	CompressThreshold         int
+	Capacity                    int
+	IgnoreUnconstrainedInputs   bool
+	CompressThreshold           int
+	Optimizer                   constraint.OptimizationLevel
+	DisableComponentMemoization bool
+	Parallelism                 int
+	PublicInputHashing          string
 }
 
 // WithCapacity is a compile option that specifies the estimated capacity needed
@@ -203,6 +264,71 @@ func WithCompressThreshold(threshold int) CompileOption {
 	}
 }
 
+// WithOptimizer is a compile option which runs the constraint system optimizer
+// at the given level once the circuit is compiled. See [constraint.Optimize]
+// for the list of passes.
+//
+// The optimized constraint system is equivalent to the original one, but the
+// internal variables are renumbered: the setup must be run on the optimized
+// constraint system.
+//
+// The substitution of linear wires of [constraint.OptimizationFull] is only
+// implemented for R1CS. On a SparseR1CS (PLONK), OptimizationFull runs the
+// same passes as [constraint.OptimizationBasic].
+func WithOptimizer(level constraint.OptimizationLevel) CompileOption {
+	return func(opt *CompileConfig) error {
+		opt.Optimizer = level
+		return nil
+	}
+}
+
+// DisableComponentMemoization is a compile option which traces the definition
+// of the components at every call instead of adding the recorded instructions
+// again. The constraint system is the same, only the compilation is slower.
+// See [Component].
+func DisableComponentMemoization() CompileOption {
+	return func(opt *CompileConfig) error {
+		opt.DisableComponentMemoization = true
+		return nil
+	}
+}
+
+// WithParallelism is a compile option which defines the tasks given to
+// [Parallel] concurrently, on at most nbWorkers goroutines. If nbWorkers is
+// not positive, the number of CPUs is used.
+//
+// The constraint system is equivalent to the one compiled without this option,
+// but the order of the coefficients may differ.
+func WithParallelism(nbWorkers int) CompileOption {
+	return func(opt *CompileConfig) error {
+		if nbWorkers <= 0 {
+			nbWorkers = runtime.NumCPU()
+		}
+		opt.Parallelism = nbWorkers
+		return nil
+	}
+}
+
+// WithPublicInputHashing is a compile option which replaces the public inputs
+// of the circuit by a single public digest of their values, computed with the
+// hasher registered under hashName (see [RegisterPublicInputHasher]). This
+// reduces the cost of the verification when it depends on the number of public
+// inputs, as in the Solidity verifiers.
+//
+// The public inputs become the first secret inputs. The witness of the
+// compiled circuit is obtained with
+// [github.com/consensys/gnark/backend/witness.HashPublicInputs], given the
+// matching native hash function, and its public part is the digest.
+func WithPublicInputHashing(hashName string) CompileOption {
+	return func(opt *CompileConfig) error {
+		if _, ok := getPublicInputHasher(hashName); !ok {
+			return fmt.Errorf("public input hasher %q not registered", hashName)
+		}
+		opt.PublicInputHashing = hashName
+		return nil
+	}
+}
+
 var tVariable reflect.Type
 
 func init() {
@@ -0,0 +1,97 @@
+package frontend
+
+// ComponentFunc defines the constraints of a component. It returns the
+// outputs of the component computed from its inputs.
+type ComponentFunc func(api API, inputs []Variable) []Variable
+
+// Component is a gadget called many times in a circuit. The builders trace
+// its definition once for each shape of the inputs (the constant inputs, and
+// how the other inputs are built from the wires of the circuit) and add the
+// recorded instructions again, with fresh wires, at the following calls. The
+// constraint system is the same as if the definition was traced at every
+// call, which the compile option [DisableComponentMemoization] enforces.
+//
+// The definition must only constrain its inputs, and the variables captured
+// by the function must be the same at every call. The states kept by the
+// gadgets outside of the constraint system, as the variables given to the
+// range checker of [github.com/consensys/gnark/std/rangecheck] or the queries
+// of the lookup tables, are updated through [UpdateState], which records the
+// updates and replays them with the variables of the following calls.
+//
+// The definitions using other values of the builder key-value store (for
+// example through [API.Compiler].Defer), logs or commitments are traced at
+// every call. A definition creating a value of the store, as the first range
+// check of the circuit, is traced again at the next call.
+type Component struct {
+	name   string
+	define ComponentFunc
+}
+
+// NewComponent returns a component with the given name and definition.
+func NewComponent(name string, define ComponentFunc) *Component {
+	return &Component{name: name, define: define}
+}
+
+// Name returns the name of the component.
+func (c *Component) Name() string {
+	return c.name
+}
+
+// Define traces the definition of the component. It is called by the
+// builders, circuits should use [Component.Call].
+func (c *Component) Define(api API, inputs []Variable) []Variable {
+	return c.define(api, inputs)
+}
+
+// Call adds the constraints of the component called with the given inputs and
+// returns its outputs.
+func (c *Component) Call(api API, inputs ...Variable) []Variable {
+	if b, ok := api.Compiler().(componentCaller); ok {
+		return b.CallComponent(c, inputs)
+	}
+	return c.define(api, inputs)
+}
+
+// componentCaller is implemented by the builders memoizing the components.
+type componentCaller interface {
+	CallComponent(c *Component, inputs []Variable) []Variable
+}
+
+// StateUpdate is a modification of a state kept by a gadget outside of the
+// constraint system, for example appending a variable to the ones checked by a
+// range checker. It is given the function mapping the variables of the call of
+// the gadget which made the modification to the ones of the call applying it.
+type StateUpdate func(remap func(Variable) Variable)
+
+// UpdateState applies update with the identity mapping. When a [Component] is
+// traced, the builder records the update and applies it again, with the
+// variables of the call, at the following calls of the component.
+//
+// The gadgets must go through UpdateState to modify a state which is used to
+// define constraints later on, for example in a function given to
+// [API.Compiler].Defer, so that the components using them can be memoized.
+// The gadgets whose updates can't be replayed call UpdateState with a nil
+// update before modifying their state themselves: the components are then
+// traced at every call.
+func UpdateState(api API, update StateUpdate) {
+	if b, ok := api.Compiler().(stateUpdater); ok {
+		b.UpdateState(update)
+		return
+	}
+	if update != nil {
+		update(func(v Variable) Variable { return v })
+	}
+}
+
+// ReplayableState is implemented by the values of the builder key-value store
+// which are only modified through [UpdateState]. Reading them doesn't prevent
+// the memoization of the components.
+type ReplayableState interface {
+	// ReplayableState is a marker method.
+	ReplayableState()
+}
+
+// stateUpdater is implemented by the builders recording the state updates.
+type stateUpdater interface {
+	UpdateState(update StateUpdate)
+}
@@ -0,0 +1,253 @@
+package cs
+
+import (
+	"strings"
+
+	"github.com/consensys/gnark/constraint"
+	"github.com/consensys/gnark/frontend"
+	"github.com/consensys/gnark/internal/kvstore"
+)
+
+// ComponentBuilder is implemented by the builders memoizing the components,
+// see [frontend.Component].
+type ComponentBuilder interface {
+	frontend.Builder
+
+	// WriteComponentKey writes to key a description of the variable v, in
+	// which its wires are replaced by their index in key.
+	WriteComponentKey(v frontend.Variable, key *ComponentKey)
+
+	// RemapWires returns a copy of the variable v with its wires mapped by
+	// remap.
+	RemapWires(v frontend.Variable, remap func(uint32) uint32) frontend.Variable
+
+	// UpdateState implements [frontend.UpdateState].
+	UpdateState(update frontend.StateUpdate)
+
+	// IsolateComponent hides the state of the builder referencing the
+	// instructions added before a component is traced. The returned function
+	// restores it and returns false if the component used values of the
+	// key-value store of the builder which are not a
+	// [frontend.ReplayableState].
+	IsolateComponent() (restore func() (pure bool))
+}
+
+// ComponentKey identifies the inputs of a component: the constant inputs and
+// how the other ones are built from the wires of the constraint system.
+type ComponentKey struct {
+	strings.Builder
+	wires []uint32
+	index map[uint32]int
+}
+
+// Wire returns the index of the wire in the key, adding it if needed.
+func (k *ComponentKey) Wire(wireID uint32) int {
+	if i, ok := k.index[wireID]; ok {
+		return i
+	}
+	k.index[wireID] = len(k.wires)
+	k.wires = append(k.wires, wireID)
+	return len(k.wires) - 1
+}
+
+// Components memoizes the components called in a circuit.
+type Components struct {
+	disabled  bool
+	templates map[*frontend.Component]map[string]*componentTemplate
+
+	// the components being traced, innermost last
+	recordings []*recording
+
+	// an update is being applied, the updates it makes are part of it
+	applying bool
+}
+
+// recording holds the state updates made while a component is traced.
+type recording struct {
+	updates []frontend.StateUpdate
+	impure  bool // some updates can't be replayed
+}
+
+type componentTemplate struct {
+	template *constraint.Template // nil if the component is traced at every call
+
+	outputs        []frontend.Variable
+	booleanOutputs []bool // outputs marked as boolean
+	booleanInputs  []bool // inputs marked as boolean by the component
+	updates        []frontend.StateUpdate
+}
+
+// NewComponents returns the component memoization of a builder.
+func NewComponents(config frontend.CompileConfig) *Components {
+	return &Components{
+		disabled:  config.DisableComponentMemoization,
+		templates: make(map[*frontend.Component]map[string]*componentTemplate),
+	}
+}
+
+// Call adds the constraints of the component c called with the given inputs to
+// the constraint system of the builder b, and returns its outputs.
+func (cc *Components) Call(b ComponentBuilder, system constraint.ConstraintSystem, c *frontend.Component, inputs []frontend.Variable) []frontend.Variable {
+	if cc.disabled {
+		return trace(b, c, inputs)
+	}
+
+	key := ComponentKey{index: make(map[uint32]int)}
+	for _, v := range inputs {
+		if b.IsBoolean(v) {
+			key.WriteByte('b')
+		}
+		b.WriteComponentKey(v, &key)
+		key.WriteByte(';')
+	}
+	templates, ok := cc.templates[c]
+	if !ok {
+		templates = make(map[string]*componentTemplate)
+		cc.templates[c] = templates
+	}
+	if t, ok := templates[key.String()]; ok {
+		if t.template == nil {
+			return trace(b, c, inputs)
+		}
+		return t.instantiate(b, system, key.wires, inputs)
+	}
+
+	// first call with these inputs, we record the instructions
+	start := system.StartTemplate()
+	restore := b.IsolateComponent()
+	rec := new(recording)
+	cc.recordings = append(cc.recordings, rec)
+	outputs := c.Define(b, inputs)
+	cc.recordings = cc.recordings[:len(cc.recordings)-1]
+	if len(cc.recordings) != 0 {
+		// the updates are part of the enclosing component as well
+		outer := cc.recordings[len(cc.recordings)-1]
+		outer.updates = append(outer.updates, rec.updates...)
+		outer.impure = outer.impure || rec.impure
+	}
+	if pure := restore(); !pure || rec.impure {
+		// the component may have created values of the key-value store, so
+		// that it can be recorded at the next call
+		return outputs
+	}
+	t := new(componentTemplate)
+	templates[key.String()] = t
+	template, err := system.RecordTemplate(start, key.wires)
+	if err != nil {
+		return outputs
+	}
+
+	t.template = template
+	t.updates = rec.updates
+	t.outputs = make([]frontend.Variable, len(outputs))
+	t.booleanOutputs = make([]bool, len(outputs))
+	t.booleanInputs = make([]bool, len(inputs))
+	identity := func(wireID uint32) uint32 { return wireID }
+	for i, v := range outputs {
+		if v != nil {
+			t.outputs[i] = b.RemapWires(v, identity)
+			t.booleanOutputs[i] = b.IsBoolean(v)
+		}
+	}
+	for i, v := range inputs {
+		t.booleanInputs[i] = b.IsBoolean(v)
+	}
+	return outputs
+}
+
+// instantiate adds the recorded instructions with the given input wires.
+func (t *componentTemplate) instantiate(b ComponentBuilder, system constraint.ConstraintSystem, wires []uint32, inputs []frontend.Variable) []frontend.Variable {
+	remap := system.AddTemplate(t.template, wires)
+	remapVariable := func(v frontend.Variable) frontend.Variable {
+		return b.RemapWires(v, remap)
+	}
+	for _, update := range t.updates {
+		b.UpdateState(func(outer func(frontend.Variable) frontend.Variable) {
+			update(func(v frontend.Variable) frontend.Variable { return outer(remapVariable(v)) })
+		})
+	}
+	outputs := make([]frontend.Variable, len(t.outputs))
+	for i, v := range t.outputs {
+		if v == nil {
+			continue
+		}
+		outputs[i] = b.RemapWires(v, remap)
+		if t.booleanOutputs[i] {
+			b.MarkBoolean(outputs[i])
+		}
+	}
+	for i, v := range inputs {
+		if t.booleanInputs[i] {
+			b.MarkBoolean(v)
+		}
+	}
+	return outputs
+}
+
+// UpdateState applies update and records it in the components being traced.
+// A nil update makes them traced at every call. The updates made while update
+// is applied are not recorded, as they are made again when it is replayed.
+func (cc *Components) UpdateState(update frontend.StateUpdate) {
+	var rec *recording
+	if len(cc.recordings) != 0 {
+		rec = cc.recordings[len(cc.recordings)-1]
+	}
+	if update == nil {
+		if rec != nil {
+			rec.impure = true
+		}
+		return
+	}
+	if cc.applying {
+		update(func(v frontend.Variable) frontend.Variable { return v })
+		return
+	}
+	cc.applying = true
+	defer func() { cc.applying = false }()
+	update(func(v frontend.Variable) frontend.Variable { return v })
+	if rec != nil {
+		rec.updates = append(rec.updates, update)
+	}
+}
+
+// trace adds the constraints of the component by calling its definition.
+func trace(b ComponentBuilder, c *frontend.Component, inputs []frontend.Variable) []frontend.Variable {
+	restore := b.IsolateComponent()
+	outputs := c.Define(b, inputs)
+	restore()
+	return outputs
+}
+
+// TrackedStore is a key-value store recording whether values which are not a
+// [frontend.ReplayableState] have been used.
+type TrackedStore struct {
+	kvstore.Store
+	used bool
+}
+
+// NewTrackedStore returns a store tracking the uses of s.
+func NewTrackedStore(s kvstore.Store) *TrackedStore {
+	return &TrackedStore{Store: s}
+}
+
+func (s *TrackedStore) SetKeyValue(key, value any) {
+	if _, ok := value.(frontend.ReplayableState); !ok {
+		s.used = true
+	}
+	s.Store.SetKeyValue(key, value)
+}
+
+func (s *TrackedStore) GetKeyValue(key any) any {
+	value := s.Store.GetKeyValue(key)
+	if _, ok := value.(frontend.ReplayableState); !ok {
+		// including the missing values, which the caller may create
+		s.used = true
+	}
+	return value
+}
+
+// Used returns true if values which are not a [frontend.ReplayableState] have
+// been used.
+func (s *TrackedStore) Used() bool {
+	return s.used
+}
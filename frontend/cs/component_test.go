@@ -0,0 +1,301 @@
+package cs_test
+
+import (
+	"crypto/sha256"
+	"math/big"
+	"testing"
+
+	"github.com/consensys/gnark-crypto/ecc"
+	"github.com/consensys/gnark/constraint"
+	"github.com/consensys/gnark/frontend"
+	"github.com/consensys/gnark/frontend/cs/r1cs"
+	"github.com/consensys/gnark/frontend/cs/scs"
+	"github.com/consensys/gnark/std/hash/sha2"
+	"github.com/consensys/gnark/std/math/emulated"
+	"github.com/consensys/gnark/std/math/uints"
+	"github.com/consensys/gnark/std/rangecheck"
+	"github.com/consensys/gnark/test"
+	"github.com/stretchr/testify/require"
+)
+
+// newComponents returns a component mixing constraints and hints, and one
+// using range checks, counting the number of times they are traced.
+func newComponents(nbTraces *int) (gadget, checked *frontend.Component) {
+	gadget = frontend.NewComponent("gadget", func(api frontend.API, inputs []frontend.Variable) []frontend.Variable {
+		*nbTraces++
+		a, b := inputs[0], inputs[1]
+		p := api.Mul(a, b, a)
+		bits := api.ToBinary(api.Add(p, b), 16)
+		return []frontend.Variable{
+			api.Add(p, a),
+			api.IsZero(api.Sub(a, b)),
+			api.FromBinary(bits[:8]...),
+			bits[0],
+		}
+	})
+	checked = frontend.NewComponent("checked", func(api frontend.API, inputs []frontend.Variable) []frontend.Variable {
+		*nbTraces++
+		rangecheck.New(api).Check(inputs[0], 8)
+		return []frontend.Variable{api.Mul(inputs[0], 2)}
+	})
+	return gadget, checked
+}
+
+type componentCircuit struct {
+	X        [4]frontend.Variable
+	Y        frontend.Variable `gnark:",public"`
+	nbTraces int
+}
+
+func (c *componentCircuit) Define(api frontend.API) error {
+	gadget, checked := newComponents(&c.nbTraces)
+	acc := frontend.Variable(0)
+	for i := 1; i < len(c.X); i++ {
+		// inputs from the wires of the circuit, with the same shape
+		out := gadget.Call(api, c.X[i-1], c.X[i])
+		acc = api.Add(acc, out[0], out[1], out[2])
+		api.AssertIsBoolean(out[3])
+	}
+	// inputs of other shapes: constant, scaled wire, same wire twice
+	for _, out := range [][]frontend.Variable{
+		gadget.Call(api, c.X[0], 3),
+		gadget.Call(api, api.Mul(c.X[1], 3), c.X[2]),
+		gadget.Call(api, c.X[3], c.X[3]),
+	} {
+		acc = api.Add(acc, out[0], out[1], out[2])
+	}
+	for i := range c.X {
+		acc = api.Add(acc, checked.Call(api, c.X[i])[0])
+	}
+	api.AssertIsEqual(acc, c.Y)
+	return nil
+}
+
+// evaluate returns the value of Y for the given X.
+func evaluate(x [4]int) int {
+	gadget := func(a, b int) int {
+		p := a * b * a
+		isZero := 0
+		if a == b {
+			isZero = 1
+		}
+		return p + a + isZero + (p+b)&0xff
+	}
+	acc := 0
+	for i := 1; i < len(x); i++ {
+		acc += gadget(x[i-1], x[i])
+	}
+	acc += gadget(x[0], 3) + gadget(3*x[1], x[2]) + gadget(x[3], x[3])
+	for i := range x {
+		acc += 2 * x[i]
+	}
+	return acc
+}
+
+func TestComponent(t *testing.T) {
+	assert := test.NewAssert(t)
+	x := [4]int{3, 5, 2, 7}
+	assert.CheckCircuit(&componentCircuit{},
+		test.WithValidAssignment(&componentCircuit{X: [4]frontend.Variable{x[0], x[1], x[2], x[3]}, Y: evaluate(x)}),
+		test.WithInvalidAssignment(&componentCircuit{X: [4]frontend.Variable{x[0], x[1], x[2], x[3]}, Y: evaluate(x) + 1}),
+		test.WithCurves(ecc.BN254))
+}
+
+func TestComponentMemoization(t *testing.T) {
+	for _, newBuilder := range []frontend.NewBuilder{r1cs.NewBuilder, scs.NewBuilder} {
+		var memoized, traced componentCircuit
+		ccsMemoized, err := frontend.Compile(ecc.BN254.ScalarField(), newBuilder, &memoized)
+		require.NoError(t, err)
+		ccsTraced, err := frontend.Compile(ecc.BN254.ScalarField(), newBuilder, &traced, frontend.DisableComponentMemoization())
+		require.NoError(t, err)
+
+		// the gadget is traced once per shape of its inputs, the component
+		// using range checks at its first call, which creates the range
+		// checker, and at the second one, which is recorded
+		require.Equal(t, 4+2, memoized.nbTraces)
+		require.Equal(t, 6+4, traced.nbTraces)
+
+		// the constraint systems are identical
+		fMemoized, err := constraint.Fingerprint(ccsMemoized)
+		require.NoError(t, err)
+		fTraced, err := constraint.Fingerprint(ccsTraced)
+		require.NoError(t, err)
+		require.Equal(t, fTraced, fMemoized)
+		require.Equal(t, ccsTraced.GetNbConstraints(), ccsMemoized.GetNbConstraints())
+	}
+}
+
+// sha2Circuit hashes messages in a component, whose gadgets keep their range
+// checks and lookups outside of the constraint system.
+type sha2Circuit struct {
+	In       [3][16]frontend.Variable
+	Out      [3][32]frontend.Variable `gnark:",public"`
+	nbTraces int
+}
+
+func (c *sha2Circuit) Define(api frontend.API) error {
+	hasher := frontend.NewComponent("sha2", func(api frontend.API, inputs []frontend.Variable) []frontend.Variable {
+		c.nbTraces++
+		uapi, err := uints.New[uints.U32](api)
+		if err != nil {
+			panic(err)
+		}
+		h, err := sha2.New(api)
+		if err != nil {
+			panic(err)
+		}
+		for _, v := range inputs {
+			h.Write([]uints.U8{uapi.ByteValueOf(v)})
+		}
+		digest := h.Sum()
+		outputs := make([]frontend.Variable, len(digest))
+		for i := range digest {
+			outputs[i] = digest[i].Val
+		}
+		return outputs
+	})
+	for i := range c.In {
+		digest := hasher.Call(api, c.In[i][:]...)
+		for j := range digest {
+			api.AssertIsEqual(digest[j], c.Out[i][j])
+		}
+	}
+	return nil
+}
+
+func TestComponentSha2(t *testing.T) {
+	var assignment sha2Circuit
+	for i := range assignment.In {
+		var msg [16]byte
+		for j := range msg {
+			msg[j] = byte(16*i + j)
+			assignment.In[i][j] = msg[j]
+		}
+		digest := sha256.Sum256(msg[:])
+		for j := range digest {
+			assignment.Out[i][j] = digest[j]
+		}
+	}
+	invalid := assignment
+	invalid.Out[2][5] = 0
+	if assignment.Out[2][5] == 0 {
+		invalid.Out[2][5] = 1
+	}
+	// the range check of the inputs of a memoized call is replayed
+	outOfRange := assignment
+	outOfRange.In[2][3] = 256 + 16*2 + 3
+
+	for _, newBuilder := range []frontend.NewBuilder{r1cs.NewBuilder, scs.NewBuilder} {
+		var memoized, traced sha2Circuit
+		ccsMemoized, err := frontend.Compile(ecc.BN254.ScalarField(), newBuilder, &memoized)
+		require.NoError(t, err)
+		ccsTraced, err := frontend.Compile(ecc.BN254.ScalarField(), newBuilder, &traced, frontend.DisableComponentMemoization())
+		require.NoError(t, err)
+
+		// the first call creates the range checker and the lookup tables
+		require.Equal(t, 2, memoized.nbTraces)
+		require.Equal(t, 3, traced.nbTraces)
+
+		fMemoized, err := constraint.Fingerprint(ccsMemoized)
+		require.NoError(t, err)
+		fTraced, err := constraint.Fingerprint(ccsTraced)
+		require.NoError(t, err)
+		require.Equal(t, fTraced, fMemoized)
+		require.Equal(t, ccsTraced.GetNbConstraints(), ccsMemoized.GetNbConstraints())
+	}
+
+	assert := test.NewAssert(t)
+	assert.CheckCircuit(&sha2Circuit{},
+		test.WithValidAssignment(&assignment),
+		test.WithInvalidAssignment(&invalid),
+		test.WithInvalidAssignment(&outOfRange),
+		test.WithCurves(ecc.BN254), test.NoProverChecks())
+}
+
+// emulatedCircuit evaluates a polynomial over an emulated field in a
+// component, whose multiplication checks and range checks are kept outside of
+// the constraint system.
+type emulatedCircuit struct {
+	X        [4]emulated.Element[emulated.Secp256k1Fp]
+	Y        [4]emulated.Element[emulated.Secp256k1Fp] `gnark:",public"`
+	nbTraces int
+}
+
+func (c *emulatedCircuit) Define(api frontend.API) error {
+	f, err := emulated.NewField[emulated.Secp256k1Fp](api)
+	if err != nil {
+		return err
+	}
+	// captured by the component, shared by all its calls
+	seven := f.NewElement(7)
+	cube := frontend.NewComponent("cube", func(api frontend.API, inputs []frontend.Variable) []frontend.Variable {
+		c.nbTraces++
+		f, err := emulated.NewField[emulated.Secp256k1Fp](api)
+		if err != nil {
+			panic(err)
+		}
+		x := f.NewElement(inputs)
+		x2 := f.Mul(x, x)
+		y := f.Add(f.Mul(x2, x), f.Mul(x2, seven))
+		return f.Reduce(y).Limbs
+	})
+	for i := range c.X {
+		// the last call checks again the limbs of the first one
+		x := c.X[i]
+		if i == len(c.X)-1 {
+			x = c.X[0]
+		}
+		y := f.NewElement(cube.Call(api, x.Limbs...))
+		f.AssertIsEqual(y, &c.Y[i])
+	}
+	return nil
+}
+
+func TestComponentEmulated(t *testing.T) {
+	p := emulated.Secp256k1Fp{}.Modulus()
+	var assignment emulatedCircuit
+	for i := range assignment.X {
+		x := big.NewInt(int64(3*i + 2))
+		if i == len(assignment.X)-1 {
+			x = big.NewInt(2)
+		}
+		// y = x³ + 7x²
+		y := new(big.Int).Exp(x, big.NewInt(3), p)
+		y.Add(y, new(big.Int).Mul(big.NewInt(7), new(big.Int).Mul(x, x)))
+		y.Mod(y, p)
+		assignment.X[i] = emulated.ValueOf[emulated.Secp256k1Fp](x)
+		assignment.Y[i] = emulated.ValueOf[emulated.Secp256k1Fp](y)
+	}
+	invalid := assignment
+	invalid.Y[2] = emulated.ValueOf[emulated.Secp256k1Fp](3)
+	// the range check of the inputs of a memoized call is replayed
+	outOfRange := assignment
+	outOfRange.X[2] = emulated.Element[emulated.Secp256k1Fp]{Limbs: []frontend.Variable{
+		new(big.Int).Add(new(big.Int).Lsh(big.NewInt(1), 64), big.NewInt(8)), -1, 0, 0,
+	}}
+
+	for _, newBuilder := range []frontend.NewBuilder{r1cs.NewBuilder, scs.NewBuilder} {
+		var memoized, traced emulatedCircuit
+		ccsMemoized, err := frontend.Compile(ecc.BN254.ScalarField(), newBuilder, &memoized)
+		require.NoError(t, err)
+		ccsTraced, err := frontend.Compile(ecc.BN254.ScalarField(), newBuilder, &traced, frontend.DisableComponentMemoization())
+		require.NoError(t, err)
+
+		require.Equal(t, 1, memoized.nbTraces)
+		require.Equal(t, 4, traced.nbTraces)
+
+		fMemoized, err := constraint.Fingerprint(ccsMemoized)
+		require.NoError(t, err)
+		fTraced, err := constraint.Fingerprint(ccsTraced)
+		require.NoError(t, err)
+		require.Equal(t, fTraced, fMemoized)
+		require.Equal(t, ccsTraced.GetNbConstraints(), ccsMemoized.GetNbConstraints())
+	}
+
+	assert := test.NewAssert(t)
+	assert.CheckCircuit(&emulatedCircuit{},
+		test.WithValidAssignment(&assignment),
+		test.WithInvalidAssignment(&invalid),
+		test.WithInvalidAssignment(&outOfRange),
+		test.WithCurves(ecc.BN254), test.NoProverChecks())
+}
@@ -0,0 +1,212 @@
+package cs
+
+import (
+	"sync"
+
+	"github.com/consensys/gnark/constraint"
+	"github.com/consensys/gnark/frontend"
+	"github.com/consensys/gnark/internal/kvstore"
+)
+
+// ParallelBuilder is implemented by the builders defining the tasks of
+// [frontend.Parallel] concurrently.
+type ParallelBuilder interface {
+	ComponentBuilder
+	kvstore.Store
+
+	// Fork returns a new builder, and its constraint system, with the same
+	// configuration and wires as the builder and the key-value store store.
+	Fork(store kvstore.Store) (ParallelBuilder, constraint.ConstraintSystem)
+}
+
+// forkStop is the panic value of [ForkStore] stopping a task.
+type forkStop struct {
+	key     any
+	missing bool // the value is missing in the store of the builder
+}
+
+// ForkStore is the key-value store of the forked builders. It returns copies
+// of the [frontend.MergeableState] values of the store of the builder, which
+// are merged into them with the constraints of the task. The other uses of the
+// store stop the task, before the task modifies any state of the gadgets, and
+// the task is then defined on the builder.
+type ForkStore struct {
+	parent kvstore.Store
+	mu     *sync.Mutex // guards the reads of parent by the concurrent tasks
+	api    frontend.API
+
+	keys   []any // keys of the copied states, in the order of their use
+	states map[any]frontend.MergeableState
+}
+
+func newForkStore(parent kvstore.Store, mu *sync.Mutex) *ForkStore {
+	return &ForkStore{
+		parent: parent,
+		mu:     mu,
+		states: make(map[any]frontend.MergeableState),
+	}
+}
+
+func (s *ForkStore) SetKeyValue(key, value any) {
+	panic(forkStop{key: key})
+}
+
+func (s *ForkStore) GetKeyValue(key any) any {
+	if state, ok := s.states[key]; ok {
+		return state
+	}
+	s.mu.Lock()
+	value := s.parent.GetKeyValue(key)
+	s.mu.Unlock()
+	state, ok := value.(frontend.MergeableState)
+	if !ok {
+		panic(forkStop{key: key, missing: value == nil})
+	}
+	// the copy may use the store to copy the states it depends on
+	fork := state.Fork(s.api)
+	s.keys = append(s.keys, key)
+	s.states[key] = fork
+	return fork
+}
+
+// merge merges the copied states into the states of the builder.
+func (s *ForkStore) merge(remap func(frontend.Variable) frontend.Variable) {
+	for _, key := range s.keys {
+		s.parent.GetKeyValue(key).(frontend.MergeableState).Merge(s.states[key], remap)
+	}
+}
+
+// ForkWires adds to the constraint system dst the wires of src it lacks.
+func ForkWires(dst, src constraint.ConstraintSystem) {
+	for i := dst.GetNbPublicVariables(); i < src.GetNbPublicVariables(); i++ {
+		dst.AddPublicVariable("")
+	}
+	for i := dst.GetNbSecretVariables(); i < src.GetNbSecretVariables(); i++ {
+		dst.AddSecretVariable("")
+	}
+	for i := dst.GetNbInternalVariables(); i < src.GetNbInternalVariables(); i++ {
+		dst.AddInternalVariable()
+	}
+}
+
+// fork is a task defined on a forked builder.
+type fork struct {
+	builder ParallelBuilder
+	system  constraint.ConstraintSystem
+	store   *ForkStore
+	start   constraint.TemplateStart
+	outputs []frontend.Variable
+	pure    bool
+	stop    *forkStop
+	panic   any
+}
+
+// Parallel adds the constraints of the tasks to the constraint system of the
+// builder b, defining them on at most nbWorkers forks of b concurrently, and
+// returns their outputs.
+func Parallel(b ParallelBuilder, system constraint.ConstraintSystem, tasks []func(frontend.API) []frontend.Variable, nbWorkers int) [][]frontend.Variable {
+	outputs := make([][]frontend.Variable, len(tasks))
+	if nbWorkers <= 1 || len(tasks) <= 1 {
+		for i, task := range tasks {
+			outputs[i] = task(b)
+		}
+		return outputs
+	}
+
+	var mu sync.Mutex
+	for start := 0; start < len(tasks); {
+		forks := forkTasks(b, tasks[start:], nbWorkers, &mu)
+
+		// the instructions are added in the order of the tasks
+		i := start
+		for ; i < len(tasks); i++ {
+			f := forks[i-start]
+			forks[i-start] = fork{}
+			if f.panic != nil {
+				panic(f.panic)
+			}
+			if f.stop == nil && f.pure {
+				if remap, err := constraint.AppendSystem(system, f.system, f.start); err == nil {
+					outputs[i] = f.merge(b, remap)
+					continue
+				}
+			}
+			// the task can't be merged, it is defined again on the builder
+			outputs[i] = tasks[i](b)
+			if f.stop != nil && f.stop.missing && refork(b, f.stop.key, forks[i-start+1:]) {
+				i++
+				break
+			}
+		}
+		start = i
+	}
+	return outputs
+}
+
+// forkTasks defines the tasks on at most nbWorkers forks of b concurrently.
+func forkTasks(b ParallelBuilder, tasks []func(frontend.API) []frontend.Variable, nbWorkers int, mu *sync.Mutex) []fork {
+	forks := make([]fork, len(tasks))
+	var wg sync.WaitGroup
+	workers := make(chan struct{}, nbWorkers)
+	for i := range tasks {
+		wg.Add(1)
+		workers <- struct{}{}
+		go func(i int) {
+			defer func() {
+				if r := recover(); r != nil {
+					if stop, ok := r.(forkStop); ok {
+						forks[i].stop = &stop
+					} else {
+						forks[i].panic = r
+					}
+				}
+				<-workers
+				wg.Done()
+			}()
+			f := &forks[i]
+			f.store = newForkStore(b, mu)
+			f.builder, f.system = b.Fork(f.store)
+			f.store.api = f.builder
+			f.start = f.system.StartTemplate()
+			restore := f.builder.IsolateComponent()
+			f.outputs = tasks[i](f.builder)
+			f.pure = restore()
+		}(i)
+	}
+	wg.Wait()
+	return forks
+}
+
+// refork returns true if the tasks following a task defined on the builder
+// should be forked again: the task created the mergeable state at key, which
+// some of the following tasks were stopped at.
+func refork(b ParallelBuilder, key any, next []fork) bool {
+	if _, ok := b.GetKeyValue(key).(frontend.MergeableState); !ok {
+		return false
+	}
+	for _, f := range next {
+		if f.stop != nil && f.stop.missing {
+			return true
+		}
+	}
+	return false
+}
+
+// merge adds the states of the task to the ones of the builder b, given the
+// mapping of the wires of the task, and returns the outputs of the task.
+func (f *fork) merge(b ParallelBuilder, remap func(uint32) uint32) []frontend.Variable {
+	f.store.merge(func(v frontend.Variable) frontend.Variable {
+		return b.RemapWires(v, remap)
+	})
+	outputs := make([]frontend.Variable, len(f.outputs))
+	for j, v := range f.outputs {
+		if v == nil {
+			continue
+		}
+		outputs[j] = b.RemapWires(v, remap)
+		if f.builder.IsBoolean(v) {
+			b.MarkBoolean(outputs[j])
+		}
+	}
+	return outputs
+}
@@ -0,0 +1,330 @@
+package cs_test
+
+import (
+	"fmt"
+	"math/big"
+	"runtime"
+	"testing"
+
+	"github.com/consensys/gnark-crypto/ecc"
+	"github.com/consensys/gnark/constraint"
+	"github.com/consensys/gnark/frontend"
+	"github.com/consensys/gnark/frontend/cs/r1cs"
+	"github.com/consensys/gnark/frontend/cs/scs"
+	"github.com/consensys/gnark/std/hash/poseidon"
+	"github.com/consensys/gnark/std/hash/sha2"
+	"github.com/consensys/gnark/std/math/emulated"
+	"github.com/consensys/gnark/std/math/uints"
+	"github.com/consensys/gnark/std/rangecheck"
+	"github.com/consensys/gnark/test"
+	"github.com/stretchr/testify/require"
+)
+
+type parallelCircuit struct {
+	X        [6]frontend.Variable
+	Y        frontend.Variable `gnark:",public"`
+	nbTraces [6]int
+	nbChecks int // calls of the task using the key-value store past its use
+
+	// the range checker is created before the tasks, which can then use it
+	checkedBefore bool
+}
+
+func (c *parallelCircuit) Define(api frontend.API) error {
+	if c.checkedBefore {
+		rangecheck.New(api).Check(c.X[5], 8)
+	}
+	three := api.Mul(c.X[0], 3)
+	tasks := make([]func(frontend.API) []frontend.Variable, len(c.X))
+	for i := range c.X {
+		i := i
+		tasks[i] = func(api frontend.API) []frontend.Variable {
+			c.nbTraces[i]++
+			if i == 2 {
+				// uses the key-value store of the builder
+				rangecheck.New(api).Check(c.X[i], 8)
+				c.nbChecks++
+				return []frontend.Variable{api.Mul(c.X[i], 2)}
+			}
+			a, b := c.X[i], c.X[(i+1)%len(c.X)]
+			p := api.Mul(a, b, a)
+			bits := api.ToBinary(api.Add(p, b, three), 16)
+			return []frontend.Variable{
+				api.Add(p, 5),
+				api.IsZero(api.Sub(a, b)),
+				api.FromBinary(bits[:8]...),
+				bits[0],
+			}
+		}
+	}
+	acc := frontend.Variable(0)
+	for _, out := range frontend.Parallel(api, tasks...) {
+		acc = api.Add(acc, out[0])
+		if len(out) > 1 {
+			api.AssertIsBoolean(out[3])
+			acc = api.Add(acc, out[1], out[2])
+		}
+	}
+	api.AssertIsEqual(acc, c.Y)
+	return nil
+}
+
+// evaluateParallel returns the value of Y for the given X.
+func evaluateParallel(x [6]int) int {
+	acc := 0
+	for i := range x {
+		if i == 2 {
+			acc += 2 * x[i]
+			continue
+		}
+		a, b := x[i], x[(i+1)%len(x)]
+		p := a * b * a
+		isZero := 0
+		if a == b {
+			isZero = 1
+		}
+		acc += p + 5 + isZero + (p+b+3*x[0])&0xff
+	}
+	return acc
+}
+
+func parallelAssignment(x [6]int, y int) *parallelCircuit {
+	var c parallelCircuit
+	for i := range x {
+		c.X[i] = x[i]
+	}
+	c.Y = y
+	return &c
+}
+
+func TestParallel(t *testing.T) {
+	assert := test.NewAssert(t)
+	x := [6]int{3, 5, 2, 7, 7, 1}
+	assert.CheckCircuit(&parallelCircuit{},
+		test.WithValidAssignment(parallelAssignment(x, evaluateParallel(x))),
+		test.WithInvalidAssignment(parallelAssignment(x, evaluateParallel(x)+1)),
+		test.WithCurves(ecc.BN254),
+		test.WithCompileOpts(frontend.WithParallelism(4)))
+}
+
+func TestParallelCompile(t *testing.T) {
+	x := [6]int{3, 5, 2, 7, 7, 1}
+	field := ecc.BN254.ScalarField()
+	valid, err := frontend.NewWitness(parallelAssignment(x, evaluateParallel(x)), field)
+	require.NoError(t, err)
+	invalid, err := frontend.NewWitness(parallelAssignment(x, evaluateParallel(x)+1), field)
+	require.NoError(t, err)
+
+	for _, newBuilder := range []frontend.NewBuilder{r1cs.NewBuilder, scs.NewBuilder} {
+		for _, checkedBefore := range []bool{false, true} {
+			parallel := parallelCircuit{checkedBefore: checkedBefore}
+			sequential := parallelCircuit{checkedBefore: checkedBefore}
+			ccsParallel, err := frontend.Compile(field, newBuilder, &parallel, frontend.WithParallelism(4))
+			require.NoError(t, err)
+			ccsSequential, err := frontend.Compile(field, newBuilder, &sequential)
+			require.NoError(t, err)
+
+			if checkedBefore {
+				// the task range checks with its copy of the range checker
+				require.Equal(t, [6]int{1, 1, 1, 1, 1, 1}, parallel.nbTraces)
+			} else {
+				// the task creating the range checker is stopped at its use
+				// of the key-value store and defined again sequentially
+				require.Equal(t, [6]int{1, 1, 2, 1, 1, 1}, parallel.nbTraces)
+			}
+			require.Equal(t, 1, parallel.nbChecks)
+			require.Equal(t, [6]int{1, 1, 1, 1, 1, 1}, sequential.nbTraces)
+			require.Equal(t, 1, sequential.nbChecks)
+
+			require.Equal(t, ccsSequential.GetNbConstraints(), ccsParallel.GetNbConstraints())
+			require.Equal(t, ccsSequential.GetNbInternalVariables(), ccsParallel.GetNbInternalVariables())
+			fpSequential, err := constraint.Fingerprint(ccsSequential)
+			require.NoError(t, err)
+			fpParallel, err := constraint.Fingerprint(ccsParallel)
+			require.NoError(t, err)
+			require.Equal(t, fpSequential, fpParallel)
+			_, err = ccsParallel.Solve(valid)
+			require.NoError(t, err)
+			_, err = ccsParallel.Solve(invalid)
+			require.Error(t, err)
+		}
+	}
+}
+
+// batchCircuit hashes the inputs in independent tasks, with Poseidon which only
+// uses the API, or with SHA256 which uses the key-value store of the builder.
+type batchCircuit struct {
+	In     [][64]uints.U8
+	sha256 bool
+
+	nbTraces []int
+}
+
+func (c *batchCircuit) Define(api frontend.API) error {
+	tasks := make([]func(frontend.API) []frontend.Variable, len(c.In))
+	for i := range c.In {
+		i := i
+		tasks[i] = func(api frontend.API) []frontend.Variable {
+			if c.nbTraces != nil {
+				c.nbTraces[i]++
+			}
+			if c.sha256 {
+				h, err := sha2.New(api)
+				if err != nil {
+					panic(err)
+				}
+				h.Write(c.In[i][:])
+				res := h.Sum()
+				return []frontend.Variable{res[0].Val}
+			}
+			inputs := make([]frontend.Variable, 4)
+			for j := range inputs {
+				inputs[j] = c.In[i][j].Val
+			}
+			res, err := poseidon.Hash(api, inputs...)
+			if err != nil {
+				panic(err)
+			}
+			return []frontend.Variable{res}
+		}
+	}
+	for _, out := range frontend.Parallel(api, tasks...) {
+		api.AssertIsDifferent(out[0], -1)
+	}
+	return nil
+}
+
+// emulatedBatchCircuit checks emulated multiplications in independent tasks.
+type emulatedBatchCircuit struct {
+	X, Y [4]emulated.Element[emulated.Secp256k1Fp]
+
+	nbTraces [4]int
+}
+
+func (c *emulatedBatchCircuit) Define(api frontend.API) error {
+	if _, err := emulated.NewField[emulated.Secp256k1Fp](api); err != nil {
+		return err
+	}
+	tasks := make([]func(frontend.API) []frontend.Variable, len(c.X))
+	for i := range c.X {
+		i := i
+		tasks[i] = func(api frontend.API) []frontend.Variable {
+			c.nbTraces[i]++
+			f, err := emulated.NewField[emulated.Secp256k1Fp](api)
+			if err != nil {
+				panic(err)
+			}
+			res := f.Mul(&c.X[i], &c.X[(i+1)%len(c.X)])
+			f.AssertIsEqual(res, &c.Y[i])
+			return nil
+		}
+	}
+	frontend.Parallel(api, tasks...)
+	return nil
+}
+
+func TestParallelMergeableState(t *testing.T) {
+	field := ecc.BN254.ScalarField()
+	for _, newBuilder := range []frontend.NewBuilder{r1cs.NewBuilder, scs.NewBuilder} {
+		// the first task creates the states of the range checker and of the
+		// lookups, and the other ones are forked again with a copy of them
+		parallel := batchCircuit{In: make([][64]uints.U8, 4), sha256: true, nbTraces: make([]int, 4)}
+		sequential := batchCircuit{In: make([][64]uints.U8, 4), sha256: true}
+		ccsParallel, err := frontend.Compile(field, newBuilder, &parallel, frontend.WithParallelism(4))
+		require.NoError(t, err)
+		ccsSequential, err := frontend.Compile(field, newBuilder, &sequential)
+		require.NoError(t, err)
+		require.Equal(t, []int{2, 2, 2, 2}, parallel.nbTraces)
+		fpSequential, err := constraint.Fingerprint(ccsSequential)
+		require.NoError(t, err)
+		fpParallel, err := constraint.Fingerprint(ccsParallel)
+		require.NoError(t, err)
+		require.Equal(t, fpSequential, fpParallel)
+
+		// the emulated field is created before the tasks
+		var emulatedParallel, emulatedSequential emulatedBatchCircuit
+		ccsParallel, err = frontend.Compile(field, newBuilder, &emulatedParallel, frontend.WithParallelism(4))
+		require.NoError(t, err)
+		ccsSequential, err = frontend.Compile(field, newBuilder, &emulatedSequential)
+		require.NoError(t, err)
+		require.Equal(t, [4]int{1, 1, 1, 1}, emulatedParallel.nbTraces)
+		// the width checks of a task are decided at their position when it is
+		// merged
+		require.Equal(t, ccsSequential.GetNbConstraints(), ccsParallel.GetNbConstraints())
+		require.Equal(t, ccsSequential.GetNbInternalVariables(), ccsParallel.GetNbInternalVariables())
+		fpSequential, err = constraint.Fingerprint(ccsSequential)
+		require.NoError(t, err)
+		fpParallel, err = constraint.Fingerprint(ccsParallel)
+		require.NoError(t, err)
+		require.Equal(t, fpSequential, fpParallel)
+	}
+}
+
+// emulatedBatchAssignment returns the assignment of emulatedBatchCircuit for
+// the given X.
+func emulatedBatchAssignment(x [4]int64) emulatedBatchCircuit {
+	var c emulatedBatchCircuit
+	for i := range x {
+		c.X[i] = emulated.ValueOf[emulated.Secp256k1Fp](x[i])
+		c.Y[i] = emulated.ValueOf[emulated.Secp256k1Fp](x[i] * x[(i+1)%len(x)])
+	}
+	return c
+}
+
+func TestParallelEmulated(t *testing.T) {
+	assignment := emulatedBatchAssignment([4]int64{2, 5, 8, 11})
+	invalid := assignment
+	invalid.Y[1] = emulated.ValueOf[emulated.Secp256k1Fp](3)
+	// the limbs of 5*2^64+8 are too wide, which only the width checks merged
+	// from the tasks detect
+	outOfRange := emulatedBatchAssignment([4]int64{2, 0, 8, 11})
+	x := new(big.Int).Lsh(big.NewInt(5), 64)
+	x.Add(x, big.NewInt(8))
+	outOfRange.X[1] = emulated.Element[emulated.Secp256k1Fp]{Limbs: []frontend.Variable{
+		new(big.Int).Add(new(big.Int).Lsh(big.NewInt(1), 64), big.NewInt(8)), 4, 0, 0,
+	}}
+	outOfRange.Y[0] = emulated.ValueOf[emulated.Secp256k1Fp](new(big.Int).Mul(x, big.NewInt(2)))
+	outOfRange.Y[1] = emulated.ValueOf[emulated.Secp256k1Fp](new(big.Int).Mul(x, big.NewInt(8)))
+
+	field := ecc.BN254.ScalarField()
+	for _, newBuilder := range []frontend.NewBuilder{r1cs.NewBuilder, scs.NewBuilder} {
+		ccs, err := frontend.Compile(field, newBuilder, &emulatedBatchCircuit{}, frontend.WithParallelism(4))
+		require.NoError(t, err)
+		w, err := frontend.NewWitness(&assignment, field)
+		require.NoError(t, err)
+		_, err = ccs.Solve(w)
+		require.NoError(t, err)
+		for _, a := range []*emulatedBatchCircuit{&invalid, &outOfRange} {
+			w, err := frontend.NewWitness(a, field)
+			require.NoError(t, err)
+			_, err = ccs.Solve(w)
+			require.Error(t, err)
+		}
+	}
+}
+
+// BenchmarkParallel compiles a batch of hashes sequentially and in parallel.
+// The first SHA256 task creates the range checker and the lookups, the other
+// ones are then forked again with copies of them.
+func BenchmarkParallel(b *testing.B) {
+	field := ecc.BN254.ScalarField()
+	workers := []int{1}
+	if runtime.NumCPU() > 1 {
+		workers = append(workers, runtime.NumCPU())
+	}
+	for _, batch := range []struct {
+		hasher string
+		size   int
+	}{{"poseidon", 64}, {"sha256", 8}} {
+		for _, nbWorkers := range workers {
+			b.Run(fmt.Sprintf("%s/workers=%d", batch.hasher, nbWorkers), func(b *testing.B) {
+				for i := 0; i < b.N; i++ {
+					circuit := batchCircuit{In: make([][64]uints.U8, batch.size), sha256: batch.hasher == "sha256"}
+					if _, err := frontend.Compile(field, scs.NewBuilder, &circuit, frontend.WithParallelism(nbWorkers)); err != nil {
+						b.Fatal(err)
+					}
+				}
+			})
+		}
+	}
+}
@@ -18,6 +18,7 @@ package r1cs
 
 import (
 	"errors"
+	"fmt"
 	"math/big"
 	"reflect"
 	"sort"
@@ -26,6 +27,7 @@ import (
 	"github.com/consensys/gnark/constraint"
 	"github.com/consensys/gnark/debug"
 	"github.com/consensys/gnark/frontend"
+	"github.com/consensys/gnark/frontend/cs"
 	"github.com/consensys/gnark/frontend/internal/expr"
 	"github.com/consensys/gnark/frontend/schema"
 	"github.com/consensys/gnark/internal/circuitdefer"
@@ -57,6 +59,9 @@ type builder struct {
 	config frontend.CompileConfig
 	kvstore.Store
 
+	// memoized components
+	components *cs.Components
+
 	// map for recording boolean constrained variables (to not constrain them twice)
 	mtBooleans map[uint64][]expr.LinearExpression
 
@@ -88,6 +93,7 @@ func newBuilder(field *big.Int, config frontend.CompileConfig) *builder {
 		mbuf1:      make(expr.LinearExpression, 0, macCapacity),
 		mbuf2:      make(expr.LinearExpression, 0, macCapacity),
 		Store:      kvstore.New(),
+		components: cs.NewComponents(config),
 	}
 
 	// by default the circuit is given a public wire equal to 1
@@ -507,3 +513,67 @@ func (builder *builder) ToCanonicalVariable(in frontend.Variable) frontend.Canon
 		return constraint.LinearExpression{term}
 	}
 }
+
+// CallComponent implements the memoization of [frontend.Component].
+func (builder *builder) CallComponent(c *frontend.Component, inputs []frontend.Variable) []frontend.Variable {
+	return builder.components.Call(builder, builder.cs, c, inputs)
+}
+
+// UpdateState implements [frontend.UpdateState].
+func (builder *builder) UpdateState(update frontend.StateUpdate) {
+	builder.components.UpdateState(update)
+}
+
+// WriteComponentKey implements [cs.ComponentBuilder].
+func (builder *builder) WriteComponentKey(v frontend.Variable, key *cs.ComponentKey) {
+	if c, ok := builder.constantValue(v); ok {
+		fmt.Fprintf(key, "c%v", c)
+		return
+	}
+	for _, t := range v.(expr.LinearExpression) {
+		if t.VID == 0 {
+			// the constant wire is the same in all the calls
+			fmt.Fprintf(key, "1*%v,", t.Coeff)
+			continue
+		}
+		fmt.Fprintf(key, "w%d*%v,", key.Wire(uint32(t.VID)), t.Coeff)
+	}
+}
+
+// RemapWires implements [cs.ComponentBuilder].
+func (builder *builder) RemapWires(v frontend.Variable, remap func(uint32) uint32) frontend.Variable {
+	l, ok := v.(expr.LinearExpression)
+	if !ok {
+		return v
+	}
+	res := make(expr.LinearExpression, len(l))
+	for i, t := range l {
+		res[i] = expr.Term{VID: int(remap(uint32(t.VID))), Coeff: t.Coeff}
+	}
+	return res
+}
+
+// IsolateComponent implements [cs.ComponentBuilder].
+func (builder *builder) IsolateComponent() func() bool {
+	store := cs.NewTrackedStore(builder.Store)
+	builder.Store = store
+	return func() bool {
+		builder.Store = store.Store
+		return !store.Used()
+	}
+}
+
+// CallParallel implements the parallel definition of [frontend.Parallel].
+func (builder *builder) CallParallel(tasks []func(frontend.API) []frontend.Variable) [][]frontend.Variable {
+	return cs.Parallel(builder, builder.cs, tasks, builder.config.Parallelism)
+}
+
+// Fork implements [cs.ParallelBuilder].
+func (builder *builder) Fork(store kvstore.Store) (cs.ParallelBuilder, constraint.ConstraintSystem) {
+	config := builder.config
+	config.Capacity = 0
+	fork := newBuilder(builder.Field(), config)
+	fork.Store = store
+	cs.ForkWires(fork.cs, builder.cs)
+	return fork, fork.cs
+}
@@ -17,6 +17,7 @@ limitations under the License.
 package scs
 
 import (
+	"fmt"
 	"math/big"
 	"reflect"
 	"sort"
@@ -25,6 +26,7 @@ import (
 	"github.com/consensys/gnark/constraint"
 	"github.com/consensys/gnark/debug"
 	"github.com/consensys/gnark/frontend"
+	"github.com/consensys/gnark/frontend/cs"
 	"github.com/consensys/gnark/frontend/internal/expr"
 	"github.com/consensys/gnark/frontend/schema"
 	"github.com/consensys/gnark/internal/circuitdefer"
@@ -53,6 +55,9 @@ type builder struct {
 	config frontend.CompileConfig
 	kvstore.Store
 
+	// memoized components
+	components *cs.Components
+
 	// map for recording boolean constrained variables (to not constrain them twice)
 	mtBooleans map[expr.Term]struct{}
 
@@ -84,6 +89,7 @@ func newBuilder(field *big.Int, config frontend.CompileConfig) *builder {
 		mAddInstructions: make(map[uint64]int, config.Capacity/2),
 		config:           config,
 		Store:            kvstore.New(),
+		components:       cs.NewComponents(config),
 		bufL:             make(expr.LinearExpression, 20),
 	}
 	// init hint buffer.
@@ -686,3 +692,62 @@ func (builder *builder) ToCanonicalVariable(v frontend.Variable) frontend.Canoni
 		return term
 	}
 }
+
+// CallComponent implements the memoization of [frontend.Component].
+func (builder *builder) CallComponent(c *frontend.Component, inputs []frontend.Variable) []frontend.Variable {
+	return builder.components.Call(builder, builder.cs, c, inputs)
+}
+
+// UpdateState implements [frontend.UpdateState].
+func (builder *builder) UpdateState(update frontend.StateUpdate) {
+	builder.components.UpdateState(update)
+}
+
+// WriteComponentKey implements [cs.ComponentBuilder].
+func (builder *builder) WriteComponentKey(v frontend.Variable, key *cs.ComponentKey) {
+	if c, ok := builder.constantValue(v); ok {
+		fmt.Fprintf(key, "c%v", c)
+		return
+	}
+	t := v.(expr.Term)
+	fmt.Fprintf(key, "w%d*%v", key.Wire(uint32(t.VID)), t.Coeff)
+}
+
+// RemapWires implements [cs.ComponentBuilder].
+func (builder *builder) RemapWires(v frontend.Variable, remap func(uint32) uint32) frontend.Variable {
+	t, ok := v.(expr.Term)
+	if !ok {
+		return v
+	}
+	return expr.Term{VID: int(remap(uint32(t.VID))), Coeff: t.Coeff}
+}
+
+// IsolateComponent implements [cs.ComponentBuilder]. The component doesn't
+// reuse the constraints added before it, which may not exist at the other
+// calls.
+func (builder *builder) IsolateComponent() func() bool {
+	store := cs.NewTrackedStore(builder.Store)
+	builder.Store = store
+	mul, add := builder.mMulInstructions, builder.mAddInstructions
+	builder.mMulInstructions, builder.mAddInstructions = make(map[uint64]int), make(map[uint64]int)
+	return func() bool {
+		builder.Store = store.Store
+		builder.mMulInstructions, builder.mAddInstructions = mul, add
+		return !store.Used()
+	}
+}
+
+// CallParallel implements the parallel definition of [frontend.Parallel].
+func (builder *builder) CallParallel(tasks []func(frontend.API) []frontend.Variable) [][]frontend.Variable {
+	return cs.Parallel(builder, builder.cs, tasks, builder.config.Parallelism)
+}
+
+// Fork implements [cs.ParallelBuilder].
+func (builder *builder) Fork(store kvstore.Store) (cs.ParallelBuilder, constraint.ConstraintSystem) {
+	config := builder.config
+	config.Capacity = 0
+	fork := newBuilder(builder.Field(), config)
+	fork.Store = store
+	cs.ForkWires(fork.cs, builder.cs)
+	return fork, fork.cs
+}
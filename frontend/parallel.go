@@ -0,0 +1,60 @@
+package frontend
+
+// Parallel adds the constraints of independent tasks and returns their
+// outputs, in the order of the tasks.
+//
+// When the compile option [WithParallelism] is set, the builders define each
+// task on a separate builder, on its own goroutine, and add the resulting
+// instructions to the constraint system with renumbered wires. Otherwise, and
+// in the test engine, the tasks are defined one after the other.
+//
+// The tasks may use the variables defined before the call, but must not use
+// the outputs of the other tasks, the gadgets created outside of the task (as
+// a range checker or a lookup table, which would be updated concurrently) nor
+// modify shared state.
+//
+// The tasks get their own copy of the values of the builder key-value store
+// which are a [MergeableState], as the range checker of
+// [github.com/consensys/gnark/std/rangecheck], the precomputed lookups of the
+// uints package and the emulated fields, created before the call. The copies
+// are merged into the values of the builder with the constraints of the task.
+//
+// The tasks using other values of the store (for example through
+// [API.Compiler].Defer, as the lookup tables do), or creating a value of the
+// store, are stopped at their use of the store and defined sequentially on
+// the builder. The remaining tasks are forked again when the sequential task
+// created the value they were missing, as the range checker at the first range
+// check of the circuit. The tasks using logs or commitments are defined on
+// their own builder and then again on the builder. The function of such a task
+// is thus called twice: it must not have side effects besides the constraints.
+func Parallel(api API, tasks ...func(api API) []Variable) [][]Variable {
+	if b, ok := api.Compiler().(parallelCaller); ok {
+		return b.CallParallel(tasks)
+	}
+	outputs := make([][]Variable, len(tasks))
+	for i, task := range tasks {
+		outputs[i] = task(api)
+	}
+	return outputs
+}
+
+// parallelCaller is implemented by the builders defining tasks in parallel.
+type parallelCaller interface {
+	CallParallel(tasks []func(api API) []Variable) [][]Variable
+}
+
+// MergeableState is a [ReplayableState] of the builder key-value store which
+// the tasks of [Parallel] can update concurrently: each task defined on its own
+// builder updates a copy of the state, which is merged into the state of the
+// builder afterwards.
+type MergeableState interface {
+	ReplayableState
+
+	// Fork returns an empty copy of the state for a task defined on api. It
+	// may read the state, but must not modify it.
+	Fork(api API) MergeableState
+
+	// Merge adds to the state, through [UpdateState], the updates made to
+	// fork, a copy returned by Fork, with their variables mapped by remap.
+	Merge(fork MergeableState, remap func(Variable) Variable)
+}
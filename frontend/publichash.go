@@ -0,0 +1,34 @@
+package frontend
+
+import (
+	"sync"
+)
+
+// PublicInputHasher computes in-circuit the digest of the public inputs of a
+// circuit compiled with [WithPublicInputHashing].
+type PublicInputHasher func(api API, inputs []Variable) (Variable, error)
+
+var (
+	publicInputHashers = make(map[string]PublicInputHasher)
+	publicInputLock    sync.RWMutex
+)
+
+// RegisterPublicInputHasher registers the hasher h under the given name, for
+// use with [WithPublicInputHashing]. The field hashers registered in
+// [github.com/consensys/gnark/std/hash] are registered with the same name, and
+// the packages [github.com/consensys/gnark/std/hash/mimc],
+// [github.com/consensys/gnark/std/hash/sha2] and
+// [github.com/consensys/gnark/std/hash/sha3] register "MIMC", "SHA256",
+// "SHA3_256" and "KECCAK256".
+func RegisterPublicInputHasher(name string, h PublicInputHasher) {
+	publicInputLock.Lock()
+	defer publicInputLock.Unlock()
+	publicInputHashers[name] = h
+}
+
+func getPublicInputHasher(name string) (PublicInputHasher, bool) {
+	publicInputLock.RLock()
+	defer publicInputLock.RUnlock()
+	h, ok := publicInputHashers[name]
+	return h, ok
+}
@@ -105,6 +105,7 @@ func main() {
 				groth16Dir         = strings.Replace(d.RootPath, "{?}", "groth16", 1)
 				groth16MpcSetupDir = filepath.Join(groth16Dir, "mpcsetup")
 				plonkDir           = strings.Replace(d.RootPath, "{?}", "plonk", 1)
+				plonkMpcSetupDir   = filepath.Join(plonkDir, "mpcsetup")
 				plonkFriDir        = strings.Replace(d.RootPath, "{?}", "plonkfri", 1)
 			)
 
@@ -164,6 +165,9 @@ func main() {
 				{File: filepath.Join(groth16Dir, "setup.go"), Templates: []string{"groth16/groth16.setup.go.tmpl", importCurve}},
 				{File: filepath.Join(groth16Dir, "marshal.go"), Templates: []string{"groth16/groth16.marshal.go.tmpl", importCurve}},
 				{File: filepath.Join(groth16Dir, "marshal_test.go"), Templates: []string{"groth16/tests/groth16.marshal.go.tmpl", importCurve}},
+				{File: filepath.Join(groth16Dir, "aggregate.go"), Templates: []string{"groth16/groth16.aggregate.go.tmpl", importCurve}},
+				{File: filepath.Join(groth16Dir, "stream.go"), Templates: []string{"groth16/groth16.stream.go.tmpl", importCurve}},
+				{File: filepath.Join(groth16Dir, "prove_test.go"), Templates: []string{"groth16/tests/groth16.prove.go.tmpl", importCurve}},
 			}
 			if err := bgen.Generate(d, "groth16", "./template/zkpschemes/", entries...); err != nil {
 				panic(err) // TODO handle
@@ -204,6 +208,17 @@ func main() {
 				panic(err)
 			}
 
+			// plonk mpcsetup
+			entries = []bavard.Entry{
+				{File: filepath.Join(plonkMpcSetupDir, "powersoftau.go"), Templates: []string{"plonk/mpcsetup/powersoftau.go.tmpl", importCurve}},
+				{File: filepath.Join(plonkMpcSetupDir, "marshal.go"), Templates: []string{"plonk/mpcsetup/marshal.go.tmpl", importCurve}},
+				{File: filepath.Join(plonkMpcSetupDir, "setup_test.go"), Templates: []string{"plonk/mpcsetup/setup_test.go.tmpl", importCurve}},
+				{File: filepath.Join(plonkMpcSetupDir, "utils.go"), Templates: []string{"plonk/mpcsetup/utils.go.tmpl", importCurve}},
+			}
+			if err := bgen.Generate(d, "mpcsetup", "./template/zkpschemes/", entries...); err != nil {
+				panic(err)
+			}
+
 			// plonkfri
 			entries = []bavard.Entry{
 				{File: filepath.Join(plonkFriDir, "verify.go"), Templates: []string{"plonkfri/plonk.verify.go.tmpl", importCurve}},
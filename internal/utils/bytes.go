@@ -0,0 +1,39 @@
+package utils
+
+import (
+	"encoding/binary"
+	"fmt"
+	"io"
+)
+
+// WriteBytes writes b to w, prefixed with its length as a big-endian uint32.
+func WriteBytes(w io.Writer, b []byte) (int64, error) {
+	var buf [4]byte
+	binary.BigEndian.PutUint32(buf[:], uint32(len(b)))
+	n, err := w.Write(buf[:])
+	if err != nil {
+		return int64(n), err
+	}
+	m, err := w.Write(b)
+	return int64(n + m), err
+}
+
+// ReadBytes reads a byte slice written with WriteBytes, of at most maxLen
+// bytes. It returns nil for an empty slice.
+func ReadBytes(r io.Reader, maxLen int) ([]byte, int64, error) {
+	var buf [4]byte
+	n, err := io.ReadFull(r, buf[:])
+	if err != nil {
+		return nil, int64(n), err
+	}
+	length := binary.BigEndian.Uint32(buf[:])
+	if uint64(length) > uint64(maxLen) {
+		return nil, int64(n), fmt.Errorf("byte slice too long: %d > %d", length, maxLen)
+	}
+	if length == 0 {
+		return nil, int64(n), nil
+	}
+	b := make([]byte, length)
+	m, err := io.ReadFull(r, b)
+	return b, int64(n + m), err
+}
@@ -1,3 +1,22 @@
 package evmprecompiles
 
-// will implement later
+import (
+	"github.com/consensys/gnark/frontend"
+	"github.com/consensys/gnark/std/hash/sha2"
+	"github.com/consensys/gnark/std/math/uints"
+)
+
+// SHA256 implements [SHA256] precompile contract at address 0x02.
+//
+// It returns the digest of the first length bytes of data, length being at
+// most len(data). The number of constraints depends on len(data).
+//
+// [SHA256]: https://ethereum.github.io/execution-specs/autoapi/ethereum/paris/vm/precompiled_contracts/sha256/index.html
+func SHA256(api frontend.API, data []uints.U8, length frontend.Variable) []uints.U8 {
+	h, err := sha2.New(api)
+	if err != nil {
+		panic(err)
+	}
+	h.Write(data)
+	return h.FixedLengthSum(length)
+}
@@ -0,0 +1,53 @@
+package evmprecompiles
+
+import (
+	"crypto/sha256"
+	"testing"
+
+	"github.com/consensys/gnark-crypto/ecc"
+	"github.com/consensys/gnark/frontend"
+	"github.com/consensys/gnark/std/math/uints"
+	"github.com/consensys/gnark/test"
+	"golang.org/x/crypto/ripemd160" //nolint:staticcheck // reference implementation
+)
+
+type hashPrecompileCircuit struct {
+	Data   []uints.U8
+	Length frontend.Variable
+	SHA256 [32]uints.U8
+	RIPEMD [20]uints.U8
+}
+
+func (c *hashPrecompileCircuit) Define(api frontend.API) error {
+	uapi, err := uints.New[uints.U32](api)
+	if err != nil {
+		return err
+	}
+	sha := SHA256(api, c.Data, c.Length)
+	for i := range c.SHA256 {
+		uapi.ByteAssertEq(c.SHA256[i], sha[i])
+	}
+	ripemd := RIPEMD160(api, c.Data, c.Length)
+	for i := range c.RIPEMD {
+		uapi.ByteAssertEq(c.RIPEMD[i], ripemd[i])
+	}
+	return nil
+}
+
+func TestHashPrecompiles(t *testing.T) {
+	assert := test.NewAssert(t)
+	data := []byte("the quick brown fox jumps over the lazy dog, again and again and again")
+	for _, length := range []int{0, 3, len(data)} {
+		witness := hashPrecompileCircuit{
+			Data:   uints.NewU8Array(data),
+			Length: length,
+		}
+		sha := sha256.Sum256(data[:length])
+		copy(witness.SHA256[:], uints.NewU8Array(sha[:]))
+		h := ripemd160.New()
+		h.Write(data[:length])
+		copy(witness.RIPEMD[:], uints.NewU8Array(h.Sum(nil)))
+		err := test.IsSolved(&hashPrecompileCircuit{Data: make([]uints.U8, len(data))}, &witness, ecc.BN254.ScalarField())
+		assert.NoError(err, "length %d", length)
+	}
+}
@@ -0,0 +1,23 @@
+package evmprecompiles
+
+import (
+	"github.com/consensys/gnark/frontend"
+	"github.com/consensys/gnark/std/hash/ripemd160"
+	"github.com/consensys/gnark/std/math/uints"
+)
+
+// RIPEMD160 implements [RIPEMD160] precompile contract at address 0x03.
+//
+// It returns the 20-byte digest of the first length bytes of data, length
+// being at most len(data). The contract returns it left-padded with zeros to
+// 32 bytes. The number of constraints depends on len(data).
+//
+// [RIPEMD160]: https://ethereum.github.io/execution-specs/autoapi/ethereum/paris/vm/precompiled_contracts/ripemd160/index.html
+func RIPEMD160(api frontend.API, data []uints.U8, length frontend.Variable) []uints.U8 {
+	h, err := ripemd160.New(api)
+	if err != nil {
+		panic(err)
+	}
+	h.Write(data)
+	return h.FixedLengthSum(length)
+}
@@ -1 +1,32 @@
 package evmprecompiles
+
+import (
+	"github.com/consensys/gnark/frontend"
+	"github.com/consensys/gnark/std/math/emulated"
+)
+
+// ExpMod implements [MODEXP] precompile contract at address 0x05.
+//
+// The modulus is given at runtime. The type parameter P only bounds the size
+// of the base, the exponent and the modulus, for example emparams.Mod1e4096 for
+// operands of at most 4096 bits, and the
+// number of constraints depends on P rather than on the actual sizes of the
+// operands. As specified by [EIP-198], the result is zero when the modulus is
+// zero, and base^0 is one (reduced modulo the modulus).
+//
+// [MODEXP]: https://ethereum.github.io/execution-specs/autoapi/ethereum/paris/vm/precompiled_contracts/expmod/index.html
+// [EIP-198]: https://eips.ethereum.org/EIPS/eip-198
+func ExpMod[P emulated.FieldParams](api frontend.API, base, exp, modulus *emulated.Element[P]) *emulated.Element[P] {
+	f, err := emulated.NewField[P](api)
+	if err != nil {
+		panic(err)
+	}
+	// we compute with a dummy modulus when it is zero and return zero
+	isZeroMod := api.IsZero(modulus.Limbs[0])
+	for i := 1; i < len(modulus.Limbs); i++ {
+		isZeroMod = api.And(isZeroMod, api.IsZero(modulus.Limbs[i]))
+	}
+	modulus = f.Select(isZeroMod, f.One(), modulus)
+	res := f.ModReduce(f.ModExp(base, exp, modulus), modulus)
+	return f.Select(isZeroMod, f.Zero(), res)
+}
@@ -0,0 +1,72 @@
+package evmprecompiles
+
+import (
+	"fmt"
+	"math/big"
+	"testing"
+
+	"github.com/consensys/gnark-crypto/ecc"
+	"github.com/consensys/gnark/frontend"
+	"github.com/consensys/gnark/std/math/emulated"
+	"github.com/consensys/gnark/std/math/emulated/emparams"
+	"github.com/consensys/gnark/test"
+)
+
+type expmodCircuit struct {
+	Base, Exp, Mod, Result emulated.Element[emparams.Mod1e512]
+}
+
+func (c *expmodCircuit) Define(api frontend.API) error {
+	f, err := emulated.NewField[emparams.Mod1e512](api)
+	if err != nil {
+		return err
+	}
+	res := ExpMod(api, &c.Base, &c.Exp, &c.Mod)
+	f.AssertLimbsEquality(res, &c.Result)
+	return nil
+}
+
+func testExpMod(t *testing.T, base, exp, mod *big.Int) {
+	assert := test.NewAssert(t)
+	expected := new(big.Int)
+	if mod.Sign() != 0 {
+		expected.Exp(base, exp, mod)
+	}
+	wrong := new(big.Int).Add(expected, big.NewInt(1))
+	if mod.Sign() != 0 {
+		// the result plus the modulus is congruent, but not reduced
+		wrong.Add(expected, mod)
+	}
+	assignment := func(result *big.Int) *expmodCircuit {
+		return &expmodCircuit{
+			Base:   emulated.ValueOf[emparams.Mod1e512](base),
+			Exp:    emulated.ValueOf[emparams.Mod1e512](exp),
+			Mod:    emulated.ValueOf[emparams.Mod1e512](mod),
+			Result: emulated.ValueOf[emparams.Mod1e512](result),
+		}
+	}
+	assert.NoError(test.IsSolved(&expmodCircuit{}, assignment(expected), ecc.BN254.ScalarField()))
+	assert.Error(test.IsSolved(&expmodCircuit{}, assignment(wrong), ecc.BN254.ScalarField()))
+}
+
+func TestExpMod(t *testing.T) {
+	p256, _ := new(big.Int).SetString("ffffffff00000001000000000000000000000000ffffffffffffffffffffffff", 16)
+	large := new(big.Int).Lsh(big.NewInt(1), 511)
+	large.Sub(large, big.NewInt(187))
+	for i, v := range []struct{ base, exp, mod *big.Int }{
+		{big.NewInt(3), big.NewInt(5), big.NewInt(7)},
+		{big.NewInt(3), new(big.Int).Sub(p256, big.NewInt(2)), p256},
+		{new(big.Int).Lsh(big.NewInt(1), 300), big.NewInt(65537), large},
+		{big.NewInt(12345), big.NewInt(0), big.NewInt(1000)}, // zero exponent
+		{big.NewInt(0), big.NewInt(0), big.NewInt(1000)},     // 0^0
+		{big.NewInt(12345), big.NewInt(0), big.NewInt(1)},    // modulus one
+		{big.NewInt(12345), big.NewInt(77), big.NewInt(0)},   // zero modulus
+		{big.NewInt(0), big.NewInt(77), big.NewInt(13)},      // zero base
+		{large, large, big.NewInt(2)},                        // base larger than modulus
+	} {
+		v := v
+		t.Run(fmt.Sprintf("case=%d", i), func(t *testing.T) {
+			testExpMod(t, v.base, v.exp, v.mod)
+		})
+	}
+}
@@ -0,0 +1,28 @@
+package evmprecompiles
+
+import (
+	"github.com/consensys/gnark/frontend"
+	"github.com/consensys/gnark/std/math/uints"
+	"github.com/consensys/gnark/std/permutation/blake2"
+)
+
+// BLAKE2F implements [BLAKE2F] precompile contract at address 0x09.
+//
+// It applies rounds rounds of the BLAKE2b compression function to the state h
+// with the message block m and the offset counters t. The final block
+// indicator f must be boolean. The number of rounds is a circuit parameter as
+// the rounds are unrolled.
+//
+// [BLAKE2F]: https://eips.ethereum.org/EIPS/eip-152
+func BLAKE2F(api frontend.API, rounds int, h [8]uints.U64, m [16]uints.U64, t [2]uints.U64, f frontend.Variable) [8]uints.U64 {
+	uapi, err := uints.New[uints.U64](api)
+	if err != nil {
+		panic(err)
+	}
+	api.AssertIsBoolean(f)
+	var mask uints.U64
+	for i := range mask {
+		mask[i] = uints.U8{Val: api.Mul(f, 0xff)}
+	}
+	return blake2.Blake2b(uapi, rounds, h, m, t, mask)
+}
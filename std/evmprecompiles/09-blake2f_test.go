@@ -0,0 +1,83 @@
+package evmprecompiles
+
+import (
+	"encoding/binary"
+	"testing"
+
+	"github.com/consensys/gnark-crypto/ecc"
+	"github.com/consensys/gnark/frontend"
+	"github.com/consensys/gnark/std/math/uints"
+	"github.com/consensys/gnark/std/permutation/blake2"
+	"github.com/consensys/gnark/test"
+	"golang.org/x/crypto/blake2b"
+)
+
+type blake2fCircuit struct {
+	H        [8]uints.U64
+	M        [][16]uints.U64
+	T        [][2]uints.U64
+	F        []frontend.Variable
+	Expected [8]uints.U64
+}
+
+func (c *blake2fCircuit) Define(api frontend.API) error {
+	uapi, err := uints.New[uints.U64](api)
+	if err != nil {
+		return err
+	}
+	h := c.H
+	for i := range c.M {
+		h = BLAKE2F(api, 12, h, c.M[i], c.T[i], c.F[i])
+	}
+	for i := range h {
+		uapi.AssertEq(h[i], c.Expected[i])
+	}
+	return nil
+}
+
+func TestBLAKE2F(t *testing.T) {
+	assert := test.NewAssert(t)
+	data := make([]byte, 256)
+	for i := range data {
+		data[i] = byte(i * 7)
+	}
+	// the BLAKE2b-512 digest of a message of one or two blocks
+	for _, length := range []int{3, 128, 200} {
+		nbBlocks := (length + 127) / 128
+		witness := blake2fCircuit{
+			M: make([][16]uints.U64, nbBlocks),
+			T: make([][2]uints.U64, nbBlocks),
+			F: make([]frontend.Variable, nbBlocks),
+		}
+		seed := blake2.Blake2bIV
+		seed[0] ^= 0x01010040
+		for i := range seed {
+			witness.H[i] = uints.NewU64(seed[i])
+		}
+		for i := range witness.M {
+			offset := 128 * (i + 1)
+			witness.F[i] = 0
+			if offset >= length {
+				offset = length
+				witness.F[i] = 1
+			}
+			block := make([]byte, 128)
+			copy(block, data[128*i:offset])
+			for j := range witness.M[i] {
+				witness.M[i][j] = uints.NewU64(binary.LittleEndian.Uint64(block[8*j:]))
+			}
+			witness.T[i] = [2]uints.U64{uints.NewU64(uint64(offset)), uints.NewU64(0)}
+		}
+		dgst := blake2b.Sum512(data[:length])
+		for i := range witness.Expected {
+			witness.Expected[i] = uints.NewU64(binary.LittleEndian.Uint64(dgst[8*i:]))
+		}
+		circuit := blake2fCircuit{
+			M: make([][16]uints.U64, nbBlocks),
+			T: make([][2]uints.U64, nbBlocks),
+			F: make([]frontend.Variable, nbBlocks),
+		}
+		err := test.IsSolved(&circuit, &witness, ecc.BN254.ScalarField())
+		assert.NoError(err, "length %d", length)
+	}
+}
@@ -0,0 +1,225 @@
+// Package blake2 implements in-circuit BLAKE2b and BLAKE2s hash computation.
+//
+// This package extends the BLAKE2 compression functions [blake2] into the
+// unkeyed BLAKE2 hashes as specified in [RFC 7693] and matches
+// [golang.org/x/crypto/blake2b] and [golang.org/x/crypto/blake2s].
+//
+// [RFC 7693]: https://www.rfc-editor.org/rfc/rfc7693
+package blake2
+
+import (
+	"math/bits"
+
+	"github.com/consensys/gnark/frontend"
+	"github.com/consensys/gnark/std/hash"
+	"github.com/consensys/gnark/std/math/integer"
+	"github.com/consensys/gnark/std/math/uints"
+	"github.com/consensys/gnark/std/permutation/blake2"
+	"github.com/consensys/gnark/std/rangecheck"
+	"github.com/consensys/gnark/std/selector"
+)
+
+func init() {
+	frontend.RegisterPublicInputHasher("BLAKE2B_256", hash.BinaryPublicInputHasher(func(api frontend.API) (hash.BinaryHasher, error) {
+		return NewBlake2b256(api)
+	}))
+	frontend.RegisterPublicInputHasher("BLAKE2S_256", hash.BinaryPublicInputHasher(func(api frontend.API) (hash.BinaryHasher, error) {
+		return NewBlake2s256(api)
+	}))
+}
+
+type digest[T uints.Long] struct {
+	api      frontend.API
+	uapi     *uints.BinaryField[T]
+	compress func(h [8]T, m [16]T, t [2]T, f T) [8]T
+	seed     [8]T
+	size     int
+	in       []uints.U8
+}
+
+// NewBlake2b256 returns a new BLAKE2b-256 hasher.
+func NewBlake2b256(api frontend.API) (hash.BinaryFixedLengthHasher, error) {
+	return newBlake2b(api, 32)
+}
+
+// NewBlake2b512 returns a new BLAKE2b-512 hasher.
+func NewBlake2b512(api frontend.API) (hash.BinaryFixedLengthHasher, error) {
+	return newBlake2b(api, 64)
+}
+
+// NewBlake2s256 returns a new BLAKE2s-256 hasher.
+func NewBlake2s256(api frontend.API) (hash.BinaryFixedLengthHasher, error) {
+	uapi, err := uints.New[uints.U32](api)
+	if err != nil {
+		return nil, err
+	}
+	// the parameter block sets the digest length and the fanout and depth to 1
+	seed := blake2.Blake2sIV
+	seed[0] ^= 0x01010000 ^ 32
+	d := &digest[uints.U32]{api: api, uapi: uapi, size: 32}
+	copy(d.seed[:], uints.NewU32Array(seed[:]))
+	d.compress = func(h [8]uints.U32, m [16]uints.U32, t [2]uints.U32, f uints.U32) [8]uints.U32 {
+		return blake2.Blake2s(uapi, 10, h, m, t, f)
+	}
+	return d, nil
+}
+
+func newBlake2b(api frontend.API, size int) (hash.BinaryFixedLengthHasher, error) {
+	uapi, err := uints.New[uints.U64](api)
+	if err != nil {
+		return nil, err
+	}
+	// the parameter block sets the digest length and the fanout and depth to 1
+	seed := blake2.Blake2bIV
+	seed[0] ^= 0x01010000 ^ uint64(size)
+	d := &digest[uints.U64]{api: api, uapi: uapi, size: size}
+	copy(d.seed[:], uints.NewU64Array(seed[:]))
+	d.compress = func(h [8]uints.U64, m [16]uints.U64, t [2]uints.U64, f uints.U64) [8]uints.U64 {
+		return blake2.Blake2b(uapi, 12, h, m, t, f)
+	}
+	return d, nil
+}
+
+func (d *digest[T]) Write(data []uints.U8) {
+	d.in = append(d.in, data...)
+}
+
+// blockLen returns the number of bytes in a message block of sixteen words.
+func (d *digest[T]) blockLen() int {
+	var w T
+	return 16 * len(w)
+}
+
+// nbBlocks returns the number of blocks of a message of bytesLen bytes. An
+// empty message is compressed as a single zero block.
+func (d *digest[T]) nbBlocks(bytesLen int) int {
+	if bytesLen == 0 {
+		return 1
+	}
+	return (bytesLen + d.blockLen() - 1) / d.blockLen()
+}
+
+// block returns the i-th message block of data, packing the bytes into words
+// in little-endian order.
+func (d *digest[T]) block(data []frontend.Variable, i int) [16]T {
+	var m [16]T
+	for j := range m {
+		var w T
+		for k := 0; k < len(w); k++ {
+			w[k] = uints.U8{Val: data[i*d.blockLen()+j*len(w)+k]}
+		}
+		m[j] = w
+	}
+	return m
+}
+
+func (d *digest[T]) Sum() []uints.U8 {
+	nbBlocks := d.nbBlocks(len(d.in))
+	data := make([]frontend.Variable, nbBlocks*d.blockLen())
+	for i := range data {
+		data[i] = 0
+		if i < len(d.in) {
+			data[i] = d.in[i].Val
+		}
+	}
+	runningDigest := d.seed
+	for i := 0; i < nbBlocks; i++ {
+		var t [2]T
+		var f T
+		offset := (i + 1) * d.blockLen()
+		if i == nbBlocks-1 {
+			offset = len(d.in)
+			f = constant[T](^uint64(0))
+		} else {
+			f = constant[T](0)
+		}
+		t[0], t[1] = constant[T](uint64(offset)), constant[T](0)
+		runningDigest = d.compress(runningDigest, d.block(data, i), t, f)
+	}
+	return d.output(runningDigest)
+}
+
+// FixedLengthSum returns the digest of the first length bytes written, length
+// being a variable at most the number of bytes written. The number of
+// constraints depends on the number of bytes written rather than on length.
+func (d *digest[T]) FixedLengthSum(length frontend.Variable) []uints.U8 {
+	maxLen := len(d.in)
+	if maxLen == 0 {
+		d.api.AssertIsEqual(length, 0)
+	} else {
+		rangecheck.New(d.api).Check(d.api.Sub(maxLen, length), bits.Len(uint(maxLen)))
+	}
+
+	// the input is zero padded to full blocks. The last block is the one
+	// containing the last byte, or the first block for an empty input.
+	maxBlocks := d.nbBlocks(maxLen)
+	data := make([]frontend.Variable, maxBlocks*d.blockLen())
+	for i := range data {
+		data[i] = 0
+		if i < maxLen {
+			data[i] = d.in[i].Val
+		}
+	}
+	data = selector.Partition(d.api, length, false, data)
+	last := d.api.Sub(d.api.Add(length, d.api.IsZero(length)), 1)
+	lastIdx := integer.ShiftRight(d.api, last, uint(bits.TrailingZeros(uint(d.blockLen()))), bits.Len(uint(maxLen+1)))
+	lastBlock := selector.Decoder(d.api, maxBlocks, lastIdx)
+
+	// the byte offset is the length for the last block and the end of the
+	// block for the previous ones.
+	var w T
+	lenBits := d.api.ToBinary(length, 8*len(w))
+	lenBytes := make([]frontend.Variable, len(w))
+	for i := range lenBytes {
+		lenBytes[i] = d.api.FromBinary(lenBits[8*i : 8*i+8]...)
+	}
+
+	runningDigest := d.seed
+	ret := make([]frontend.Variable, d.size)
+	for i := range ret {
+		ret[i] = 0
+	}
+	for i := 0; i < maxBlocks; i++ {
+		offset := constant[T](uint64((i + 1) * d.blockLen()))
+		var t [2]T
+		var f T
+		for j := 0; j < len(w); j++ {
+			t[0][j] = uints.U8{Val: d.api.Add(offset[j].Val, d.api.Mul(lastBlock[i], d.api.Sub(lenBytes[j], offset[j].Val)))}
+			t[1][j] = uints.NewU8(0)
+			f[j] = uints.U8{Val: d.api.Mul(lastBlock[i], 0xff)}
+		}
+		runningDigest = d.compress(runningDigest, d.block(data, i), t, f)
+		for j, b := range d.output(runningDigest) {
+			ret[j] = d.api.MulAcc(ret[j], lastBlock[i], b.Val)
+		}
+	}
+	res := make([]uints.U8, len(ret))
+	for i := range res {
+		res[i] = uints.U8{Val: ret[i]}
+	}
+	return res
+}
+
+// output returns the first bytes of the state in little-endian order.
+func (d *digest[T]) output(state [8]T) []uints.U8 {
+	var ret []uints.U8
+	for i := range state {
+		ret = append(ret, d.uapi.UnpackLSB(state[i])...)
+	}
+	return ret[:d.size]
+}
+
+func (d *digest[T]) Reset() {
+	d.in = nil
+}
+
+func (d *digest[T]) Size() int { return d.size }
+
+// constant returns the word of value v, truncated to the word size.
+func constant[T uints.Long](v uint64) T {
+	var w T
+	for i := 0; i < len(w); i++ {
+		w[i] = uints.NewU8(uint8(v >> (8 * i)))
+	}
+	return w
+}
@@ -0,0 +1,114 @@
+package blake2
+
+import (
+	"fmt"
+	"testing"
+
+	"github.com/consensys/gnark-crypto/ecc"
+	"github.com/consensys/gnark/frontend"
+	"github.com/consensys/gnark/std/hash"
+	"github.com/consensys/gnark/std/math/uints"
+	"github.com/consensys/gnark/test"
+	"golang.org/x/crypto/blake2b"
+	"golang.org/x/crypto/blake2s"
+)
+
+var testCases = []struct {
+	name      string
+	newHasher func(api frontend.API) (hash.BinaryFixedLengthHasher, error)
+	native    func(data []byte) []byte
+}{
+	{"BLAKE2b-256", NewBlake2b256, func(data []byte) []byte { h := blake2b.Sum256(data); return h[:] }},
+	{"BLAKE2b-512", NewBlake2b512, func(data []byte) []byte { h := blake2b.Sum512(data); return h[:] }},
+	{"BLAKE2s-256", NewBlake2s256, func(data []byte) []byte { h := blake2s.Sum256(data); return h[:] }},
+}
+
+type blake2Circuit struct {
+	In       []uints.U8
+	Expected []uints.U8
+	hasher   int
+}
+
+func (c *blake2Circuit) Define(api frontend.API) error {
+	h, err := testCases[c.hasher].newHasher(api)
+	if err != nil {
+		return err
+	}
+	uapi, err := uints.New[uints.U32](api)
+	if err != nil {
+		return err
+	}
+	h.Write(c.In)
+	res := h.Sum()
+	if len(res) != len(c.Expected) {
+		return fmt.Errorf("not %d bytes", len(c.Expected))
+	}
+	for i := range c.Expected {
+		uapi.ByteAssertEq(c.Expected[i], res[i])
+	}
+	return nil
+}
+
+func TestBlake2(t *testing.T) {
+	bts := make([]byte, 200)
+	for i := range bts {
+		bts[i] = byte(i * 7)
+	}
+	for i, tc := range testCases {
+		for _, length := range []int{0, 3, 64, 128, 200} {
+			dgst := tc.native(bts[:length])
+			witness := blake2Circuit{In: uints.NewU8Array(bts[:length]), Expected: uints.NewU8Array(dgst)}
+			circuit := blake2Circuit{In: make([]uints.U8, length), Expected: make([]uints.U8, len(dgst)), hasher: i}
+			err := test.IsSolved(&circuit, &witness, ecc.BN254.ScalarField())
+			if err != nil {
+				t.Fatalf("%s, length %d: %v", tc.name, length, err)
+			}
+		}
+	}
+}
+
+type blake2FixedLengthCircuit struct {
+	In       []uints.U8
+	Length   frontend.Variable
+	Expected []uints.U8
+	hasher   int
+}
+
+func (c *blake2FixedLengthCircuit) Define(api frontend.API) error {
+	h, err := testCases[c.hasher].newHasher(api)
+	if err != nil {
+		return err
+	}
+	uapi, err := uints.New[uints.U32](api)
+	if err != nil {
+		return err
+	}
+	h.Write(c.In)
+	res := h.FixedLengthSum(c.Length)
+	for i := range c.Expected {
+		uapi.ByteAssertEq(c.Expected[i], res[i])
+	}
+	return nil
+}
+
+func TestBlake2FixedLengthSum(t *testing.T) {
+	bts := make([]byte, 130)
+	for i := range bts {
+		bts[i] = byte(i * 7)
+	}
+	for i, tc := range testCases {
+		for _, length := range []int{0, 1, 64, 65, 128, 130} {
+			dgst := tc.native(bts[:length])
+			witness := blake2FixedLengthCircuit{
+				In:       uints.NewU8Array(bts),
+				Length:   length,
+				Expected: uints.NewU8Array(dgst),
+			}
+			circuit := blake2FixedLengthCircuit{In: make([]uints.U8, len(bts)), Expected: make([]uints.U8, len(dgst)), hasher: i}
+			err := test.IsSolved(&circuit, &witness, ecc.BN254.ScalarField())
+			if err != nil {
+				t.Fatalf("%s, length %d: %v", tc.name, length, err)
+			}
+		}
+	}
+}
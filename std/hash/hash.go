@@ -22,6 +22,7 @@ import (
 	"sync"
 
 	"github.com/consensys/gnark/frontend"
+	"github.com/consensys/gnark/std/math/emulated"
 	"github.com/consensys/gnark/std/math/uints"
 )
 
@@ -40,15 +41,40 @@ type FieldHasher interface {
 	Reset()
 }
 
+// EmulatedFieldHasher is like [FieldHasher], but the inputs and the digest are
+// elements of the emulated field T. It allows to hash over fields which are
+// not the native field of the circuit, for example Goldilocks.
+type EmulatedFieldHasher[T emulated.FieldParams] interface {
+	// Sum computes the hash of the internal state of the hash function.
+	Sum() *emulated.Element[T]
+
+	// Write populate the internal state of the hash function with data.
+	Write(data ...*emulated.Element[T])
+
+	// Reset empty the internal state and put the intermediate state to zero.
+	Reset()
+}
+
 var (
 	builderRegistry = make(map[string]func(api frontend.API) (FieldHasher, error))
 	lock            sync.RWMutex
 )
 
+// Register registers the field hasher builder under the given name. The
+// hasher is also registered for the compile option
+// [frontend.WithPublicInputHashing].
 func Register(name string, builder func(api frontend.API) (FieldHasher, error)) {
 	lock.Lock()
 	defer lock.Unlock()
 	builderRegistry[name] = builder
+	frontend.RegisterPublicInputHasher(name, func(api frontend.API, inputs []frontend.Variable) (frontend.Variable, error) {
+		h, err := builder(api)
+		if err != nil {
+			return nil, err
+		}
+		h.Write(inputs...)
+		return h.Sum(), nil
+	})
 }
 
 func GetFieldHasher(name string, api frontend.API) (FieldHasher, error) {
@@ -76,6 +102,42 @@ type BinaryHasher interface {
 	Size() int
 }
 
+// BinaryPublicInputHasher returns the hasher of the public inputs for the
+// compile option [frontend.WithPublicInputHashing] using the binary hasher
+// returned by newHasher. The inputs are hashed as big-endian bytes, and the
+// digest is interpreted as a big-endian integer reduced modulo the field.
+func BinaryPublicInputHasher(newHasher func(api frontend.API) (BinaryHasher, error)) frontend.PublicInputHasher {
+	return func(api frontend.API, inputs []frontend.Variable) (frontend.Variable, error) {
+		h, err := newHasher(api)
+		if err != nil {
+			return nil, err
+		}
+		uapi, err := uints.New[uints.U32](api)
+		if err != nil {
+			return nil, err
+		}
+		nbBits := api.Compiler().FieldBitLen()
+		nbBytes := (nbBits + 7) / 8
+		for _, v := range inputs {
+			bits := api.ToBinary(v, nbBits)
+			for len(bits) < 8*nbBytes {
+				bits = append(bits, 0)
+			}
+			bytes := make([]uints.U8, nbBytes)
+			for i := range bytes {
+				j := 8 * (nbBytes - 1 - i)
+				bytes[i] = uapi.ByteValueOf(api.FromBinary(bits[j : j+8]...))
+			}
+			h.Write(bytes)
+		}
+		var digest frontend.Variable = 0
+		for _, b := range h.Sum() {
+			digest = api.Add(api.Mul(digest, 256), b.Val)
+		}
+		return digest, nil
+	}
+}
+
 // BinaryFixedLengthHasher is like [BinaryHasher], but assumes the length of the
 // input is not full length as defined during compile time. This allows to
 // compute digest of variable-length input, unlike [BinaryHasher] which assumes
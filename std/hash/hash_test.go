@@ -0,0 +1,94 @@
+package hash_test
+
+import (
+	"crypto/sha256"
+	stdhash "hash"
+	"testing"
+
+	"github.com/consensys/gnark-crypto/ecc"
+	cryptohash "github.com/consensys/gnark-crypto/hash"
+	"github.com/consensys/gnark/backend/groth16"
+	"github.com/consensys/gnark/backend/witness"
+	"github.com/consensys/gnark/frontend"
+	"github.com/consensys/gnark/frontend/cs/r1cs"
+	"github.com/consensys/gnark/frontend/cs/scs"
+	_ "github.com/consensys/gnark/std/hash/mimc"
+	_ "github.com/consensys/gnark/std/hash/sha2"
+	_ "github.com/consensys/gnark/std/hash/sha3"
+	"github.com/stretchr/testify/require"
+	"golang.org/x/crypto/sha3"
+)
+
+type publicInputCircuit struct {
+	X, Y frontend.Variable `gnark:",public"`
+	Z    frontend.Variable
+}
+
+func (c *publicInputCircuit) Define(api frontend.API) error {
+	api.AssertIsEqual(api.Mul(c.X, c.Z), c.Y)
+	return nil
+}
+
+func TestPublicInputHashing(t *testing.T) {
+	field := ecc.BN254.ScalarField()
+	valid, err := frontend.NewWitness(&publicInputCircuit{X: 3, Y: 15, Z: 5}, field)
+	require.NoError(t, err)
+	other, err := frontend.NewWitness(&publicInputCircuit{X: 3, Y: 16, Z: 5}, field)
+	require.NoError(t, err)
+
+	for name, newHash := range map[string]func() stdhash.Hash{
+		"MIMC":      cryptohash.MIMC_BN254.New,
+		"SHA256":    sha256.New,
+		"SHA3_256":  sha3.New256,
+		"KECCAK256": sha3.NewLegacyKeccak256,
+	} {
+		hashed, err := witness.HashPublicInputs(valid, newHash())
+		require.NoError(t, err, name)
+		wrong, err := witness.HashPublicInputs(other, newHash())
+		require.NoError(t, err, name)
+		public, err := hashed.Public()
+		require.NoError(t, err, name)
+		require.Len(t, public.Vector(), 1, name)
+
+		for _, newBuilder := range []frontend.NewBuilder{r1cs.NewBuilder, scs.NewBuilder} {
+			ccs, err := frontend.Compile(field, newBuilder, &publicInputCircuit{}, frontend.WithPublicInputHashing(name))
+			require.NoError(t, err, name)
+			require.Equal(t, 3, ccs.GetNbSecretVariables(), name)
+
+			_, err = ccs.Solve(hashed)
+			require.NoError(t, err, name)
+			_, err = ccs.Solve(wrong)
+			require.Error(t, err, name)
+		}
+	}
+}
+
+func TestPublicInputHashingGroth16(t *testing.T) {
+	field := ecc.BN254.ScalarField()
+	ccs, err := frontend.Compile(field, r1cs.NewBuilder, &publicInputCircuit{}, frontend.WithPublicInputHashing("MIMC"))
+	require.NoError(t, err)
+	require.Equal(t, 2, ccs.GetNbPublicVariables()) // the constant wire and the digest
+	pk, vk, err := groth16.Setup(ccs)
+	require.NoError(t, err)
+
+	w, err := frontend.NewWitness(&publicInputCircuit{X: 3, Y: 15, Z: 5}, field)
+	require.NoError(t, err)
+	w, err = witness.HashPublicInputs(w, cryptohash.MIMC_BN254.New())
+	require.NoError(t, err)
+	proof, err := groth16.Prove(ccs, pk, w)
+	require.NoError(t, err)
+
+	// the verifier computes the digest from the public inputs only
+	public, err := frontend.NewWitness(&publicInputCircuit{X: 3, Y: 15}, field, frontend.PublicOnly())
+	require.NoError(t, err)
+	public, err = witness.HashPublicInputs(public, cryptohash.MIMC_BN254.New())
+	require.NoError(t, err)
+	public, err = public.Public()
+	require.NoError(t, err)
+	require.NoError(t, groth16.Verify(proof, vk, public))
+}
+
+func TestPublicInputHashingUnknown(t *testing.T) {
+	_, err := frontend.Compile(ecc.BN254.ScalarField(), r1cs.NewBuilder, &publicInputCircuit{}, frontend.WithPublicInputHashing("UNKNOWN"))
+	require.Error(t, err)
+}
@@ -0,0 +1,80 @@
+package fieldhash
+
+import (
+	"math/big"
+
+	"github.com/consensys/gnark/frontend"
+	"github.com/consensys/gnark/std/math/emulated"
+)
+
+// Arith is the field arithmetic the permutations are defined over. It allows
+// to share the permutations between the native and emulated circuits and the
+// out-of-circuit hashers.
+type Arith[E any] interface {
+	Add(a, b E) E
+	Mul(a, b E) E
+	AddConst(a E, c *big.Int) E
+	MulConst(a E, c *big.Int) E
+}
+
+// Pow returns x^e for a small positive exponent e.
+func Pow[E any](a Arith[E], x E, e int) E {
+	res := x
+	for i := bitLen(e) - 2; i >= 0; i-- {
+		res = a.Mul(res, res)
+		if (e>>i)&1 == 1 {
+			res = a.Mul(res, x)
+		}
+	}
+	return res
+}
+
+func bitLen(e int) int {
+	n := 0
+	for ; e > 0; e >>= 1 {
+		n++
+	}
+	return n
+}
+
+// Circuit is the arithmetic of the native field of the circuit.
+type Circuit struct{ API frontend.API }
+
+func (c Circuit) Add(a, b frontend.Variable) frontend.Variable { return c.API.Add(a, b) }
+func (c Circuit) Mul(a, b frontend.Variable) frontend.Variable { return c.API.Mul(a, b) }
+func (c Circuit) AddConst(a frontend.Variable, k *big.Int) frontend.Variable {
+	return c.API.Add(a, k)
+}
+func (c Circuit) MulConst(a frontend.Variable, k *big.Int) frontend.Variable {
+	return c.API.Mul(a, k)
+}
+
+// Emulated is the arithmetic of an emulated field in the circuit.
+type Emulated[T emulated.FieldParams] struct{ Field *emulated.Field[T] }
+
+func (e Emulated[T]) Add(a, b *emulated.Element[T]) *emulated.Element[T] { return e.Field.Add(a, b) }
+func (e Emulated[T]) Mul(a, b *emulated.Element[T]) *emulated.Element[T] { return e.Field.Mul(a, b) }
+func (e Emulated[T]) AddConst(a *emulated.Element[T], k *big.Int) *emulated.Element[T] {
+	return e.Field.Add(a, e.Field.NewElement(k))
+}
+func (e Emulated[T]) MulConst(a *emulated.Element[T], k *big.Int) *emulated.Element[T] {
+	// multiplying limb-wise is only possible for small constants
+	if k.BitLen() <= 8 {
+		return e.Field.MulConst(a, k)
+	}
+	return e.Field.Mul(a, e.Field.NewElement(k))
+}
+
+// Native is the arithmetic modulo Modulus out of circuit.
+type Native struct{ Modulus *big.Int }
+
+func (n Native) Add(a, b *big.Int) *big.Int { return n.reduce(new(big.Int).Add(a, b)) }
+func (n Native) Mul(a, b *big.Int) *big.Int { return n.reduce(new(big.Int).Mul(a, b)) }
+func (n Native) AddConst(a, k *big.Int) *big.Int {
+	return n.Add(a, k)
+}
+func (n Native) MulConst(a, k *big.Int) *big.Int {
+	return n.Mul(a, k)
+}
+
+func (n Native) reduce(v *big.Int) *big.Int { return v.Mod(v, n.Modulus) }
@@ -0,0 +1,84 @@
+// Package fieldhash implements the parameter generation and the field
+// arithmetic shared by the Poseidon family of hash functions.
+//
+// The round constants are generated with the Grain LFSR and the number of
+// rounds is derived from the security inequalities, both as in the
+// [reference implementation] of Poseidon.
+//
+// [reference implementation]: https://extgit.iaik.tugraz.at/krypto/hadeshash
+package fieldhash
+
+import "math/big"
+
+// Grain is the self-shrinking Grain LFSR used to sample the parameters.
+type Grain struct {
+	state [80]uint8
+	pos   int
+}
+
+// NewGrain returns the Grain LFSR initialised for a permutation of width t
+// over a prime field of n bits with the power S-box, rf full rounds and rp
+// partial rounds.
+func NewGrain(n, t, rf, rp int) *Grain {
+	g := new(Grain)
+	i := 0
+	set := func(v, nbBits int) {
+		for j := nbBits - 1; j >= 0; j-- {
+			g.state[i] = uint8(v>>j) & 1
+			i++
+		}
+	}
+	set(1, 2) // prime field
+	set(0, 4) // x^alpha S-box
+	set(n, 12)
+	set(t, 12)
+	set(rf, 10)
+	set(rp, 10)
+	for ; i < len(g.state); i++ {
+		g.state[i] = 1
+	}
+	for j := 0; j < 160; j++ {
+		g.step()
+	}
+	return g
+}
+
+func (g *Grain) step() uint8 {
+	s := func(j int) uint8 { return g.state[(g.pos+j)%len(g.state)] }
+	b := s(62) ^ s(51) ^ s(38) ^ s(23) ^ s(13) ^ s(0)
+	g.state[g.pos] = b
+	g.pos = (g.pos + 1) % len(g.state)
+	return b
+}
+
+// bit returns the next output bit. The pairs of bits are filtered: the second
+// bit is output only when the first one is set.
+func (g *Grain) bit() uint8 {
+	for {
+		if g.step() == 1 {
+			return g.step()
+		}
+		g.step()
+	}
+}
+
+// Bits returns the next nbBits output bits as a big-endian integer.
+func (g *Grain) Bits(nbBits int) *big.Int {
+	v := new(big.Int)
+	for i := 0; i < nbBits; i++ {
+		v.Lsh(v, 1)
+		if g.bit() == 1 {
+			v.SetBit(v, 0, 1)
+		}
+	}
+	return v
+}
+
+// Element returns the next field element using rejection sampling.
+func (g *Grain) Element(modulus *big.Int) *big.Int {
+	for {
+		if v := g.Bits(modulus.BitLen()); v.Cmp(modulus) < 0 {
+			return v
+		}
+	}
+}
@@ -0,0 +1,105 @@
+package fieldhash
+
+import (
+	"errors"
+	"hash"
+	"math/big"
+)
+
+// MerkleDamgard is the Merkle-Damgård construction over a 2-to-1 compression
+// function: the digest is the running value after compressing every element
+// written into it, starting from zero.
+type MerkleDamgard[E any] struct {
+	compress func(h, x E) E
+	zero     E
+	h        E
+	data     []E
+}
+
+// NewMerkleDamgard returns the Merkle-Damgård hasher with the given
+// compression function and initial value zero.
+func NewMerkleDamgard[E any](zero E, compress func(h, x E) E) *MerkleDamgard[E] {
+	return &MerkleDamgard[E]{compress: compress, zero: zero, h: zero}
+}
+
+// Write adds more data to the running hash.
+func (d *MerkleDamgard[E]) Write(data ...E) {
+	d.data = append(d.data, data...)
+}
+
+// Sum compresses the data written and returns the running hash.
+func (d *MerkleDamgard[E]) Sum() E {
+	for _, x := range d.data {
+		d.h = d.compress(d.h, x)
+	}
+	d.data = nil
+	return d.h
+}
+
+// Reset resets the hash to its initial state.
+func (d *MerkleDamgard[E]) Reset() {
+	d.h = d.zero
+	d.data = nil
+}
+
+// nativeHasher is the out-of-circuit counterpart of [MerkleDamgard]. It
+// implements [hash.Hash] following the byte encoding of the MiMC hasher of
+// gnark-crypto.
+type nativeHasher struct {
+	modulus  *big.Int
+	byteLen  int
+	compress func(h, x *big.Int) *big.Int
+	data     []*big.Int
+}
+
+// NewNativeMerkleDamgard returns the out-of-circuit Merkle-Damgård hasher
+// over the field of the given modulus.
+func NewNativeMerkleDamgard(modulus *big.Int, compress func(h, x *big.Int) *big.Int) hash.Hash {
+	return &nativeHasher{
+		modulus:  modulus,
+		byteLen:  (modulus.BitLen() + 7) / 8,
+		compress: compress,
+	}
+}
+
+// Write adds more data to the running hash. Each block of [nativeHasher.BlockSize]
+// bytes is a big-endian encoded field element, and a shorter input is left
+// padded to a single element.
+func (d *nativeHasher) Write(p []byte) (int, error) {
+	if len(p) > 0 && len(p) < d.byteLen {
+		pp := make([]byte, d.byteLen)
+		copy(pp[len(pp)-len(p):], p)
+		p = pp
+	}
+	if len(p)%d.byteLen != 0 {
+		return 0, errors.New("invalid input length: must represent a list of field elements, expects a []byte of len m*BlockSize")
+	}
+	elems := make([]*big.Int, 0, len(p)/d.byteLen)
+	for start := 0; start < len(p); start += d.byteLen {
+		v := new(big.Int).SetBytes(p[start : start+d.byteLen])
+		if v.Cmp(d.modulus) >= 0 {
+			return 0, errors.New("input is not a canonical field element")
+		}
+		elems = append(elems, v)
+	}
+	d.data = append(d.data, elems...)
+	return len(p), nil
+}
+
+// Sum appends the big-endian encoded digest to b. It does not change the
+// underlying hash state.
+func (d *nativeHasher) Sum(b []byte) []byte {
+	h := new(big.Int)
+	for _, x := range d.data {
+		h = d.compress(h, x)
+	}
+	return append(b, h.FillBytes(make([]byte, d.byteLen))...)
+}
+
+func (d *nativeHasher) Reset() {
+	d.data = nil
+}
+
+func (d *nativeHasher) Size() int { return d.byteLen }
+
+func (d *nativeHasher) BlockSize() int { return d.byteLen }
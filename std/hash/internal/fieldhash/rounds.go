@@ -0,0 +1,60 @@
+package fieldhash
+
+import (
+	"math"
+	"math/big"
+)
+
+// securityLevel is the targeted security level in bits.
+const securityLevel = 128
+
+// Degree returns the smallest degree d >= 3 of the S-box x^d which is a
+// permutation of the field, i.e. such that d is coprime with modulus-1.
+func Degree(modulus *big.Int) int {
+	pm1 := new(big.Int).Sub(modulus, big.NewInt(1))
+	gcd := new(big.Int)
+	for d := int64(3); ; d++ {
+		if gcd.GCD(nil, nil, big.NewInt(d), pm1).Cmp(big.NewInt(1)) == 0 {
+			return int(d)
+		}
+	}
+}
+
+// RoundNumbers returns the number of full and partial rounds of a permutation
+// of width t over the field with the S-box of degree d. They minimize the
+// number of S-boxes among the secure choices, and include the security margin
+// of two full rounds and 7.5% partial rounds.
+func RoundNumbers(modulus *big.Int, t, d int) (rf, rp int) {
+	fp, _ := new(big.Float).SetInt(modulus).Float64()
+	log2p := math.Log2(fp)
+	minCost := math.MaxInt
+	for rpt := 1; rpt < 500; rpt++ {
+		for rft := 4; rft < 100; rft += 2 {
+			if !isSecure(log2p, modulus.BitLen(), t, rft, rpt, d) {
+				continue
+			}
+			rfm, rpm := rft+2, int(math.Ceil(float64(rpt)*1.075))
+			if cost := rfm*t + rpm; cost < minCost || (cost == minCost && rfm < rf) {
+				rf, rp, minCost = rfm, rpm, cost
+			}
+			break
+		}
+	}
+	return rf, rp
+}
+
+// isSecure returns true when the round numbers resist the statistical,
+// interpolation and Gröbner basis attacks.
+func isSecure(log2p float64, n, t, rf, rp, d int) bool {
+	m := float64(securityLevel)
+	logd2 := 1 / math.Log2(float64(d))
+	rf1 := 10.0
+	if m <= math.Floor(log2p-float64(d-1)/2)*float64(t+1) {
+		rf1 = 6
+	}
+	rf2 := 1 + math.Ceil(logd2*math.Min(m, float64(n))) + math.Ceil(math.Log(float64(t))/math.Log(float64(d))) - float64(rp)
+	rf3 := 1 + logd2*math.Min(m/3, log2p/2) - float64(rp)
+	rf4 := float64(t-1) + math.Min(logd2*m/float64(t+1), logd2*log2p/2) - float64(rp)
+	rfMax := math.Max(math.Max(math.Ceil(rf1), math.Ceil(rf2)), math.Max(math.Ceil(rf3), math.Ceil(rf4)))
+	return float64(rf) >= rfMax
+}
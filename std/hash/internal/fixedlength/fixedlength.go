@@ -0,0 +1,102 @@
+// Package fixedlength implements the parts of FixedLengthSum shared by the
+// binary hashers: the masking of the input past the variable length, the
+// selection of the digest after the last block and the Merkle-Damgård
+// padding.
+package fixedlength
+
+import (
+	"math/bits"
+
+	"github.com/consensys/gnark/frontend"
+	"github.com/consensys/gnark/std/math/integer"
+	"github.com/consensys/gnark/std/math/uints"
+	"github.com/consensys/gnark/std/rangecheck"
+	"github.com/consensys/gnark/std/selector"
+)
+
+// Mask checks that length is at most len(in) and returns the bytes of in
+// extended with zeros to size bytes, with the bytes from position length on
+// set to zero, and the decoder of length, whose entry at position length is 1
+// and the others 0. size must be larger than len(in).
+func Mask(api frontend.API, in []uints.U8, length frontend.Variable, size int) (data, end []frontend.Variable) {
+	maxLen := len(in)
+	if maxLen == 0 {
+		api.AssertIsEqual(length, 0)
+	} else {
+		rangecheck.New(api).Check(api.Sub(maxLen, length), bits.Len(uint(maxLen)))
+	}
+	data = make([]frontend.Variable, size)
+	for i := range data {
+		data[i] = 0
+		if i < maxLen {
+			data[i] = in[i].Val
+		}
+	}
+	data = selector.Partition(api, length, false, data)
+	end = selector.Decoder(api, size, length)
+	return data, end
+}
+
+// SelectDigest returns the digest after the block selected by lastBlock, the
+// output of a decoder over the blocks. digest is called for each block in
+// order, and returns the digest after absorbing it.
+func SelectDigest(api frontend.API, lastBlock []frontend.Variable, digest func(block int) []uints.U8) []uints.U8 {
+	var ret []frontend.Variable
+	for i := range lastBlock {
+		d := digest(i)
+		if ret == nil {
+			ret = make([]frontend.Variable, len(d))
+			for j := range ret {
+				ret[j] = 0
+			}
+		}
+		for j := range d {
+			ret[j] = api.MulAcc(ret[j], lastBlock[i], d[j].Val)
+		}
+	}
+	res := make([]uints.U8, len(ret))
+	for i := range res {
+		res[i] = uints.U8{Val: ret[i]}
+	}
+	return res
+}
+
+// MerkleDamgardSum returns the digest of the first length bytes of in, length
+// being a variable at most len(in), for a hash function over 64 bytes blocks
+// with the Merkle-Damgård padding: the byte 0x80, zeros and the bit length of
+// the input as a 64 bits integer at the end of the last block, big-endian or
+// little-endian. compress absorbs a block into the running state and returns
+// the digest after it. The number of constraints depends on len(in) rather
+// than on length.
+func MerkleDamgardSum(api frontend.API, in []uints.U8, length frontend.Variable, bigEndian bool, compress func(block [64]uints.U8) []uints.U8) []uints.U8 {
+	// the padded input has nbBlocks = (length+72)/64 blocks: the input, the
+	// padding byte, zeros and the bit length in the last 8 bytes.
+	maxLen := len(in)
+	maxBlocks := (maxLen + 72) / 64
+	data, padding := Mask(api, in, length, 64*maxBlocks)
+	nbBlocks := integer.ShiftRight(api, api.Add(length, 72), 6, bits.Len(uint(maxLen+72)))
+	lastBlock := selector.Decoder(api, maxBlocks, api.Sub(nbBlocks, 1))
+	lenBits := api.ToBinary(api.Mul(length, 8), 64)
+	var lenBytes [8]frontend.Variable
+	for i := range lenBytes {
+		if bigEndian {
+			lenBytes[i] = api.FromBinary(lenBits[56-8*i : 64-8*i]...)
+		} else {
+			lenBytes[i] = api.FromBinary(lenBits[8*i : 8*i+8]...)
+		}
+	}
+	for i := range data {
+		data[i] = api.MulAcc(data[i], padding[i], 0x80)
+		if j := i % 64; j >= 56 {
+			data[i] = api.MulAcc(data[i], lastBlock[i/64], lenBytes[j-56])
+		}
+	}
+
+	return SelectDigest(api, lastBlock, func(i int) []uints.U8 {
+		var block [64]uints.U8
+		for j := range block {
+			block[j] = uints.U8{Val: data[64*i+j]}
+		}
+		return compress(block)
+	})
+}
@@ -26,6 +26,18 @@ import (
 	"github.com/consensys/gnark/internal/utils"
 )
 
+func init() {
+	// the native counterpart is the MiMC hash of gnark-crypto over the same field
+	frontend.RegisterPublicInputHasher("MIMC", func(api frontend.API, inputs []frontend.Variable) (frontend.Variable, error) {
+		h, err := NewMiMC(api)
+		if err != nil {
+			return nil, err
+		}
+		h.Write(inputs...)
+		return h.Sum(), nil
+	})
+}
+
 // MiMC contains the params of the Mimc hash func and the curves on which it is implemented
 type MiMC struct {
 	params []big.Int           // slice containing constants for the encryption rounds
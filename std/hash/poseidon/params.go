@@ -0,0 +1,123 @@
+package poseidon
+
+import (
+	"errors"
+	"fmt"
+	"math/big"
+	"sync"
+
+	"github.com/consensys/gnark-crypto/field/goldilocks"
+	"github.com/consensys/gnark/std/hash/internal/fieldhash"
+)
+
+// Parameters are the parameters of the Poseidon permutation of a given width
+// over a prime field.
+type Parameters struct {
+	// Width is the number of field elements in the state.
+	Width int
+	// Degree is the degree d of the S-box x^d.
+	Degree int
+	// FullRounds is the number of full rounds, half of them before the
+	// partial rounds.
+	FullRounds int
+	// PartialRounds is the number of partial rounds.
+	PartialRounds int
+	// RoundConstants are the Width constants added to the state in every
+	// round.
+	RoundConstants [][]*big.Int
+	// MDS is the matrix of the linear layer.
+	MDS [][]*big.Int
+}
+
+// ErrGoldilocks is returned over the Goldilocks field. Plonky2 hashes over it
+// with Poseidon of width 12 and its own round constants and MDS matrix, which
+// are not implemented, and parameters generated as over the other fields would
+// match no other implementation.
+var ErrGoldilocks = errors.New("poseidon over the Goldilocks field (Plonky2) is not supported")
+
+var (
+	paramsCache = make(map[string]*Parameters)
+	paramsLock  sync.Mutex
+)
+
+// GetParameters returns the parameters of the Poseidon permutation of the given
+// width over the field of the given modulus. The parameters are generated as
+// in the reference implementation, with the number of partial rounds rounded
+// up to a multiple of the width as in circomlib. Over the BN254 scalar field,
+// they are those of circomlib.
+//
+// It returns [ErrGoldilocks] for the Goldilocks field.
+func GetParameters(modulus *big.Int, width int) (*Parameters, error) {
+	if width < 2 {
+		return nil, fmt.Errorf("width %d too small", width)
+	}
+	if modulus.Cmp(goldilocks.Modulus()) == 0 {
+		return nil, ErrGoldilocks
+	}
+	key := fmt.Sprintf("%s/%d", modulus.String(), width)
+	paramsLock.Lock()
+	defer paramsLock.Unlock()
+	if p, ok := paramsCache[key]; ok {
+		return p, nil
+	}
+	p := newParameters(modulus, width)
+	paramsCache[key] = p
+	return p, nil
+}
+
+func newParameters(modulus *big.Int, width int) *Parameters {
+	d := fieldhash.Degree(modulus)
+	rf, rp := fieldhash.RoundNumbers(modulus, width, d)
+	rp = (rp + width - 1) / width * width
+	p := &Parameters{
+		Width:          width,
+		Degree:         d,
+		FullRounds:     rf,
+		PartialRounds:  rp,
+		RoundConstants: make([][]*big.Int, rf+rp),
+	}
+
+	n := modulus.BitLen()
+	g := fieldhash.NewGrain(n, width, rf, rp)
+	for i := range p.RoundConstants {
+		p.RoundConstants[i] = make([]*big.Int, width)
+		for j := range p.RoundConstants[i] {
+			p.RoundConstants[i][j] = g.Element(modulus)
+		}
+	}
+
+	// the MDS matrix is the Cauchy matrix 1/(x_i+y_j) for distinct sampled
+	// x_i, y_j.
+	for p.MDS == nil {
+		xs := make([]*big.Int, 2*width)
+		for distinct := false; !distinct; {
+			distinct = true
+			seen := make(map[string]bool)
+			for i := range xs {
+				xs[i] = g.Bits(n)
+				xs[i].Mod(xs[i], modulus)
+				if seen[xs[i].String()] {
+					distinct = false
+				}
+				seen[xs[i].String()] = true
+			}
+		}
+		mds := make([][]*big.Int, width)
+		for i := range mds {
+			mds[i] = make([]*big.Int, width)
+			for j := range mds[i] {
+				s := new(big.Int).Add(xs[i], xs[width+j])
+				if s.Mod(s, modulus).Sign() == 0 {
+					mds = nil
+					break
+				}
+				mds[i][j] = s.ModInverse(s, modulus)
+			}
+			if mds == nil {
+				break
+			}
+		}
+		p.MDS = mds
+	}
+	return p
+}
@@ -0,0 +1,152 @@
+// Package poseidon implements the Poseidon hash function.
+//
+// The permutation follows the [Poseidon] paper with the parameters generated as
+// in the reference implementation, see [GetParameters]. Over the BN254 scalar
+// field, [Hash] matches the Poseidon hash of circomlib.
+//
+// The package provides the in-circuit hashers over the native field and over
+// emulated fields, and the matching out-of-circuit hashers. They are
+// registered in [hash] under the names POSEIDON_BN254, POSEIDON_BLS12_381 etc.
+//
+// Over other fields, the parameters are generated in the same way from the
+// width len(inputs)+1.
+//
+// Compatibility with Plonky2 is out of scope: the Goldilocks field is rejected
+// with [ErrGoldilocks]. The Poseidon2 hashers of
+// [github.com/consensys/gnark/std/hash/poseidon2] accept Goldilocks, but they
+// don't match Plonky2 either.
+//
+// [Poseidon]: https://eprint.iacr.org/2019/458
+package poseidon
+
+import (
+	"errors"
+	"fmt"
+	stdhash "hash"
+	"math/big"
+	"strings"
+
+	"github.com/consensys/gnark"
+	"github.com/consensys/gnark/frontend"
+	"github.com/consensys/gnark/std/hash"
+	"github.com/consensys/gnark/std/hash/internal/fieldhash"
+	"github.com/consensys/gnark/std/math/emulated"
+)
+
+func init() {
+	for _, curve := range gnark.Curves() {
+		field := curve.ScalarField()
+		hash.Register("POSEIDON_"+strings.ToUpper(curve.String()), func(api frontend.API) (hash.FieldHasher, error) {
+			if api.Compiler().Field().Cmp(field) != 0 {
+				return nil, errors.New("circuit field does not match the hasher field")
+			}
+			return NewMerkleDamgardHasher(api)
+		})
+	}
+}
+
+// permute applies the Poseidon permutation to the state in place.
+func permute[E any](a fieldhash.Arith[E], params *Parameters, state []E) {
+	half := params.FullRounds / 2
+	for r := 0; r < params.FullRounds+params.PartialRounds; r++ {
+		for i := range state {
+			state[i] = a.AddConst(state[i], params.RoundConstants[r][i])
+		}
+		if r < half || r >= half+params.PartialRounds {
+			for i := range state {
+				state[i] = fieldhash.Pow(a, state[i], params.Degree)
+			}
+		} else {
+			state[0] = fieldhash.Pow(a, state[0], params.Degree)
+		}
+		res := make([]E, len(state))
+		for i := range res {
+			res[i] = a.MulConst(state[0], params.MDS[i][0])
+			for j := 1; j < len(state); j++ {
+				res[i] = a.Add(res[i], a.MulConst(state[j], params.MDS[i][j]))
+			}
+		}
+		copy(state, res)
+	}
+}
+
+// hashElements returns the first element of the permutation of the state
+// consisting of zero followed by the inputs.
+func hashElements[E any](a fieldhash.Arith[E], modulus *big.Int, zero E, inputs ...E) (E, error) {
+	params, err := GetParameters(modulus, len(inputs)+1)
+	if err != nil {
+		return zero, fmt.Errorf("get parameters: %w", err)
+	}
+	state := append([]E{zero}, inputs...)
+	permute(a, params, state)
+	return state[0], nil
+}
+
+// Hash returns the Poseidon hash of the inputs as defined in circomlib: the
+// first element of the permutation of width len(inputs)+1 applied to zero
+// followed by the inputs.
+func Hash(api frontend.API, inputs ...frontend.Variable) (frontend.Variable, error) {
+	if len(inputs) == 0 {
+		return nil, errors.New("no inputs")
+	}
+	return hashElements[frontend.Variable](fieldhash.Circuit{API: api}, api.Compiler().Field(), 0, inputs...)
+}
+
+// NewMerkleDamgardHasher returns the Merkle-Damgård hasher over the native
+// field, with the 2-to-1 compression function (h, x) -> Hash(h, x).
+func NewMerkleDamgardHasher(api frontend.API) (hash.FieldHasher, error) {
+	a := fieldhash.Circuit{API: api}
+	field := api.Compiler().Field()
+	if _, err := GetParameters(field, 3); err != nil {
+		return nil, err
+	}
+	return fieldhash.NewMerkleDamgard[frontend.Variable](0, func(h, x frontend.Variable) frontend.Variable {
+		res, err := hashElements[frontend.Variable](a, field, 0, h, x)
+		if err != nil {
+			panic(err)
+		}
+		return res
+	}), nil
+}
+
+// NewEmulatedMerkleDamgardHasher returns the Merkle-Damgård hasher over the
+// emulated field T, for example [emulated.BN254Fr]. It is the same
+// construction as [NewMerkleDamgardHasher].
+func NewEmulatedMerkleDamgardHasher[T emulated.FieldParams](api frontend.API) (hash.EmulatedFieldHasher[T], error) {
+	f, err := emulated.NewField[T](api)
+	if err != nil {
+		return nil, fmt.Errorf("new field: %w", err)
+	}
+	var fp T
+	field := fp.Modulus()
+	if _, err := GetParameters(field, 3); err != nil {
+		return nil, err
+	}
+	a := fieldhash.Emulated[T]{Field: f}
+	return fieldhash.NewMerkleDamgard(f.Zero(), func(h, x *emulated.Element[T]) *emulated.Element[T] {
+		res, err := hashElements[*emulated.Element[T]](a, field, f.Zero(), h, x)
+		if err != nil {
+			panic(err)
+		}
+		return res
+	}), nil
+}
+
+// NewNativeMerkleDamgardHasher returns the out-of-circuit counterpart of
+// [NewMerkleDamgardHasher] over the field of the given modulus. Like the MiMC
+// hasher of gnark-crypto, it hashes big-endian encoded field elements and can
+// be used in Fiat-Shamir transcripts or with
+// [github.com/consensys/gnark/backend.WithProverHashToFieldFunction].
+func NewNativeMerkleDamgardHasher(modulus *big.Int) (stdhash.Hash, error) {
+	if _, err := GetParameters(modulus, 3); err != nil {
+		return nil, err
+	}
+	a := fieldhash.Native{Modulus: modulus}
+	return fieldhash.NewNativeMerkleDamgard(modulus, func(h, x *big.Int) *big.Int {
+		res, err := hashElements[*big.Int](a, modulus, new(big.Int), h, x)
+		if err != nil {
+			panic(err)
+		}
+		return res
+	}), nil
+}
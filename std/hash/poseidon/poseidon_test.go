@@ -0,0 +1,138 @@
+package poseidon
+
+import (
+	"math/big"
+	"strings"
+	"testing"
+
+	"github.com/consensys/gnark"
+	"github.com/consensys/gnark-crypto/ecc"
+	"github.com/consensys/gnark/frontend"
+	"github.com/consensys/gnark/frontend/cs/r1cs"
+	"github.com/consensys/gnark/std/hash"
+	"github.com/consensys/gnark/std/math/emulated"
+	"github.com/consensys/gnark/test"
+)
+
+func TestParametersCircomlib(t *testing.T) {
+	assert := test.NewAssert(t)
+	// the number of partial rounds of circomlib for the widths 2 to 17
+	partialRounds := []int{56, 57, 56, 60, 60, 63, 64, 63, 60, 66, 60, 65, 70, 60, 64, 68}
+	for i, rp := range partialRounds {
+		params, err := GetParameters(ecc.BN254.ScalarField(), i+2)
+		assert.NoError(err)
+		assert.Equal(5, params.Degree)
+		assert.Equal(8, params.FullRounds)
+		assert.Equal(rp, params.PartialRounds, "width %d", i+2)
+	}
+	params, err := GetParameters(ecc.BN254.ScalarField(), 3)
+	assert.NoError(err)
+	rc, _ := new(big.Int).SetString("0ee9a592ba9a9518d05986d656f40c2114c4993c11bb29938d21d47304cd8e6e", 16)
+	assert.Equal(0, params.RoundConstants[0][0].Cmp(rc))
+}
+
+type hashCircuit struct {
+	In       [2]frontend.Variable
+	Expected frontend.Variable
+}
+
+func (c *hashCircuit) Define(api frontend.API) error {
+	res, err := Hash(api, c.In[:]...)
+	if err != nil {
+		return err
+	}
+	api.AssertIsEqual(res, c.Expected)
+	return nil
+}
+
+func TestHashCircomlib(t *testing.T) {
+	assert := test.NewAssert(t)
+	// poseidon([1, 2]) in circomlibjs
+	expected, _ := new(big.Int).SetString("115cc0f5e7d690413df64c6b9662e9cf2a3617f2743245519e19607a4417189a", 16)
+	err := test.IsSolved(&hashCircuit{}, &hashCircuit{In: [2]frontend.Variable{1, 2}, Expected: expected}, ecc.BN254.ScalarField())
+	assert.NoError(err)
+}
+
+type merkleDamgardCircuit struct {
+	In       [3]frontend.Variable
+	Expected frontend.Variable
+	name     string
+}
+
+func (c *merkleDamgardCircuit) Define(api frontend.API) error {
+	h, err := hash.GetFieldHasher(c.name, api)
+	if err != nil {
+		return err
+	}
+	h.Write(c.In[:]...)
+	api.AssertIsEqual(h.Sum(), c.Expected)
+	return nil
+}
+
+func TestMerkleDamgardHasher(t *testing.T) {
+	assert := test.NewAssert(t)
+	for _, curve := range gnark.Curves() {
+		h, err := NewNativeMerkleDamgardHasher(curve.ScalarField())
+		assert.NoError(err)
+		witness := merkleDamgardCircuit{}
+		buf := make([]byte, h.BlockSize())
+		for i := range witness.In {
+			v := big.NewInt(int64(i + 42))
+			witness.In[i] = v
+			_, err = h.Write(v.FillBytes(buf))
+			assert.NoError(err)
+		}
+		witness.Expected = new(big.Int).SetBytes(h.Sum(nil))
+		name := "POSEIDON_" + strings.ToUpper(curve.String())
+		err = test.IsSolved(&merkleDamgardCircuit{name: name}, &witness, curve.ScalarField())
+		assert.NoError(err, name)
+	}
+}
+
+type emulatedMerkleDamgardCircuit[T emulated.FieldParams] struct {
+	In       [3]emulated.Element[T]
+	Expected emulated.Element[T]
+}
+
+func (c *emulatedMerkleDamgardCircuit[T]) Define(api frontend.API) error {
+	h, err := NewEmulatedMerkleDamgardHasher[T](api)
+	if err != nil {
+		return err
+	}
+	f, err := emulated.NewField[T](api)
+	if err != nil {
+		return err
+	}
+	for i := range c.In {
+		h.Write(&c.In[i])
+	}
+	f.AssertIsEqual(h.Sum(), &c.Expected)
+	return nil
+}
+
+func TestEmulatedMerkleDamgardHasher(t *testing.T) {
+	assert := test.NewAssert(t)
+	h, err := NewNativeMerkleDamgardHasher(ecc.BN254.ScalarField())
+	assert.NoError(err)
+	var witness emulatedMerkleDamgardCircuit[emulated.BN254Fr]
+	buf := make([]byte, h.BlockSize())
+	for i := range witness.In {
+		v := big.NewInt(int64(i + 42))
+		witness.In[i] = emulated.ValueOf[emulated.BN254Fr](v)
+		_, err = h.Write(v.FillBytes(buf))
+		assert.NoError(err)
+	}
+	witness.Expected = emulated.ValueOf[emulated.BN254Fr](new(big.Int).SetBytes(h.Sum(nil)))
+	err = test.IsSolved(&emulatedMerkleDamgardCircuit[emulated.BN254Fr]{}, &witness, ecc.BLS12_381.ScalarField())
+	assert.NoError(err)
+}
+
+func TestGoldilocksNotSupported(t *testing.T) {
+	assert := test.NewAssert(t)
+	_, err := GetParameters(emulated.Goldilocks{}.Modulus(), 3)
+	assert.ErrorIs(err, ErrGoldilocks)
+	_, err = NewNativeMerkleDamgardHasher(emulated.Goldilocks{}.Modulus())
+	assert.ErrorIs(err, ErrGoldilocks)
+	_, err = frontend.Compile(ecc.BN254.ScalarField(), r1cs.NewBuilder, &emulatedMerkleDamgardCircuit[emulated.Goldilocks]{})
+	assert.ErrorIs(err, ErrGoldilocks)
+}
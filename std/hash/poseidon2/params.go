@@ -0,0 +1,77 @@
+package poseidon2
+
+import (
+	"fmt"
+	"math/big"
+	"sync"
+
+	"github.com/consensys/gnark/std/hash/internal/fieldhash"
+)
+
+// Parameters are the parameters of the Poseidon2 permutation of a given width
+// over a prime field.
+type Parameters struct {
+	// Width is the number of field elements in the state, either 2 or 3.
+	Width int
+	// Degree is the degree d of the S-box x^d.
+	Degree int
+	// FullRounds is the number of external rounds, half of them before the
+	// internal rounds.
+	FullRounds int
+	// PartialRounds is the number of internal rounds.
+	PartialRounds int
+	// RoundConstants are the constants added to the state in every round:
+	// Width constants for the external rounds and a single constant for the
+	// internal rounds.
+	RoundConstants [][]*big.Int
+}
+
+var (
+	paramsCache = make(map[string]*Parameters)
+	paramsLock  sync.Mutex
+)
+
+// GetParameters returns the parameters of the Poseidon2 permutation of the
+// given width over the field of the given modulus. They are generated as in
+// the HorizenLabs reference implementation: the round numbers are those of
+// Poseidon, and the round constants are sampled with the Grain LFSR in the
+// order of the rounds, Width of them for each external round and a single one
+// for each internal round.
+func GetParameters(modulus *big.Int, width int) (*Parameters, error) {
+	if width != 2 && width != 3 {
+		return nil, fmt.Errorf("unsupported width %d", width)
+	}
+	key := fmt.Sprintf("%s/%d", modulus.String(), width)
+	paramsLock.Lock()
+	defer paramsLock.Unlock()
+	if p, ok := paramsCache[key]; ok {
+		return p, nil
+	}
+	p := newParameters(modulus, width)
+	paramsCache[key] = p
+	return p, nil
+}
+
+func newParameters(modulus *big.Int, width int) *Parameters {
+	d := fieldhash.Degree(modulus)
+	rf, rp := fieldhash.RoundNumbers(modulus, width, d)
+	p := &Parameters{
+		Width:          width,
+		Degree:         d,
+		FullRounds:     rf,
+		PartialRounds:  rp,
+		RoundConstants: make([][]*big.Int, rf+rp),
+	}
+	g := fieldhash.NewGrain(modulus.BitLen(), width, rf, rp)
+	for i := range p.RoundConstants {
+		rc := make([]*big.Int, width)
+		if i >= rf/2 && i < rf/2+rp {
+			rc = rc[:1]
+		}
+		for j := range rc {
+			rc[j] = g.Element(modulus)
+		}
+		p.RoundConstants[i] = rc
+	}
+	return p
+}
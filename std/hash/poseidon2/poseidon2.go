@@ -0,0 +1,143 @@
+// Package poseidon2 implements the Poseidon2 hash function.
+//
+// The permutation follows the [Poseidon2] paper for the widths 2 and 3, with
+// the parameters generated as described in [GetParameters]. The hashers use
+// the Merkle-Damgård construction over the 2-to-1 compression function
+// (h, x) -> P(h, x)[1] + x, where P is the permutation of width 2.
+//
+// The package provides the in-circuit hashers over the native field and over
+// emulated fields, and the matching out-of-circuit hashers. They are
+// registered in [hash] under the names POSEIDON2_BN254, POSEIDON2_BLS12_381
+// etc.
+//
+// Over Goldilocks, the hashers are not those of Plonky2, which hashes with
+// Poseidon: see [github.com/consensys/gnark/std/hash/poseidon].
+//
+// [Poseidon2]: https://eprint.iacr.org/2023/323
+package poseidon2
+
+import (
+	"errors"
+	"fmt"
+	stdhash "hash"
+	"math/big"
+	"strings"
+
+	"github.com/consensys/gnark"
+	"github.com/consensys/gnark/frontend"
+	"github.com/consensys/gnark/std/hash"
+	"github.com/consensys/gnark/std/hash/internal/fieldhash"
+	"github.com/consensys/gnark/std/math/emulated"
+)
+
+func init() {
+	for _, curve := range gnark.Curves() {
+		field := curve.ScalarField()
+		hash.Register("POSEIDON2_"+strings.ToUpper(curve.String()), func(api frontend.API) (hash.FieldHasher, error) {
+			if api.Compiler().Field().Cmp(field) != 0 {
+				return nil, errors.New("circuit field does not match the hasher field")
+			}
+			return NewMerkleDamgardHasher(api)
+		})
+	}
+}
+
+// permute applies the Poseidon2 permutation to the state in place.
+func permute[E any](a fieldhash.Arith[E], params *Parameters, state []E) {
+	// the external matrix is circ(2, 1, ..., 1) and the internal matrix adds
+	// one more to the last diagonal entry.
+	linear := func(internal bool) {
+		sum := state[0]
+		for i := 1; i < len(state); i++ {
+			sum = a.Add(sum, state[i])
+		}
+		for i := range state {
+			if internal && i == len(state)-1 {
+				state[i] = a.Add(sum, a.MulConst(state[i], big.NewInt(2)))
+			} else {
+				state[i] = a.Add(sum, state[i])
+			}
+		}
+	}
+
+	half := params.FullRounds / 2
+	linear(false)
+	for r := 0; r < params.FullRounds+params.PartialRounds; r++ {
+		if r < half || r >= half+params.PartialRounds {
+			for i := range state {
+				state[i] = a.AddConst(state[i], params.RoundConstants[r][i])
+				state[i] = fieldhash.Pow(a, state[i], params.Degree)
+			}
+			linear(false)
+		} else {
+			state[0] = a.AddConst(state[0], params.RoundConstants[r][0])
+			state[0] = fieldhash.Pow(a, state[0], params.Degree)
+			linear(true)
+		}
+	}
+}
+
+// compress returns P(h, x)[1] + x.
+func compress[E any](a fieldhash.Arith[E], params *Parameters, h, x E) E {
+	state := []E{h, x}
+	permute(a, params, state)
+	return a.Add(state[1], x)
+}
+
+// Permute applies the Poseidon2 permutation of width len(state) over the
+// native field to the state in place.
+func Permute(api frontend.API, state []frontend.Variable) error {
+	params, err := GetParameters(api.Compiler().Field(), len(state))
+	if err != nil {
+		return fmt.Errorf("get parameters: %w", err)
+	}
+	permute[frontend.Variable](fieldhash.Circuit{API: api}, params, state)
+	return nil
+}
+
+// NewMerkleDamgardHasher returns the Merkle-Damgård hasher over the native
+// field.
+func NewMerkleDamgardHasher(api frontend.API) (hash.FieldHasher, error) {
+	params, err := GetParameters(api.Compiler().Field(), 2)
+	if err != nil {
+		return nil, fmt.Errorf("get parameters: %w", err)
+	}
+	a := fieldhash.Circuit{API: api}
+	return fieldhash.NewMerkleDamgard[frontend.Variable](0, func(h, x frontend.Variable) frontend.Variable {
+		return compress[frontend.Variable](a, params, h, x)
+	}), nil
+}
+
+// NewEmulatedMerkleDamgardHasher returns the Merkle-Damgård hasher over the
+// emulated field T, for example [emulated.Goldilocks].
+func NewEmulatedMerkleDamgardHasher[T emulated.FieldParams](api frontend.API) (hash.EmulatedFieldHasher[T], error) {
+	f, err := emulated.NewField[T](api)
+	if err != nil {
+		return nil, fmt.Errorf("new field: %w", err)
+	}
+	var fp T
+	params, err := GetParameters(fp.Modulus(), 2)
+	if err != nil {
+		return nil, fmt.Errorf("get parameters: %w", err)
+	}
+	a := fieldhash.Emulated[T]{Field: f}
+	return fieldhash.NewMerkleDamgard(f.Zero(), func(h, x *emulated.Element[T]) *emulated.Element[T] {
+		return compress[*emulated.Element[T]](a, params, h, x)
+	}), nil
+}
+
+// NewNativeMerkleDamgardHasher returns the out-of-circuit counterpart of
+// [NewMerkleDamgardHasher] over the field of the given modulus. Like the MiMC
+// hasher of gnark-crypto, it hashes big-endian encoded field elements and can
+// be used in Fiat-Shamir transcripts or with
+// [github.com/consensys/gnark/backend.WithProverHashToFieldFunction].
+func NewNativeMerkleDamgardHasher(modulus *big.Int) (stdhash.Hash, error) {
+	params, err := GetParameters(modulus, 2)
+	if err != nil {
+		return nil, fmt.Errorf("get parameters: %w", err)
+	}
+	a := fieldhash.Native{Modulus: modulus}
+	return fieldhash.NewNativeMerkleDamgard(modulus, func(h, x *big.Int) *big.Int {
+		return compress[*big.Int](a, params, h, x)
+	}), nil
+}
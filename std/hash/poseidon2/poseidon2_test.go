@@ -0,0 +1,234 @@
+package poseidon2
+
+import (
+	"fmt"
+	"math/big"
+	"strings"
+	"testing"
+
+	"github.com/consensys/gnark"
+	"github.com/consensys/gnark-crypto/ecc"
+	fiatshamir "github.com/consensys/gnark-crypto/fiat-shamir"
+	"github.com/consensys/gnark-crypto/field/goldilocks"
+	"github.com/consensys/gnark/backend"
+	"github.com/consensys/gnark/backend/groth16"
+	"github.com/consensys/gnark/frontend"
+	"github.com/consensys/gnark/frontend/cs/r1cs"
+	fiatshamirgadget "github.com/consensys/gnark/std/fiat-shamir"
+	"github.com/consensys/gnark/std/hash"
+	"github.com/consensys/gnark/std/hash/internal/fieldhash"
+	"github.com/consensys/gnark/std/math/emulated"
+	"github.com/consensys/gnark/test"
+)
+
+type permutationCircuit struct {
+	In       [3]frontend.Variable
+	Expected [3]frontend.Variable
+}
+
+func (c *permutationCircuit) Define(api frontend.API) error {
+	state := c.In
+	if err := Permute(api, state[:]); err != nil {
+		return err
+	}
+	for i := range state {
+		api.AssertIsEqual(state[i], c.Expected[i])
+	}
+	return nil
+}
+
+func TestPermutation(t *testing.T) {
+	assert := test.NewAssert(t)
+	for _, curve := range gnark.Curves() {
+		params, err := GetParameters(curve.ScalarField(), 3)
+		assert.NoError(err)
+		state := []*big.Int{big.NewInt(0), big.NewInt(1), big.NewInt(2)}
+		var witness permutationCircuit
+		for i := range state {
+			witness.In[i] = state[i]
+		}
+		permute[*big.Int](fieldhash.Native{Modulus: curve.ScalarField()}, params, state)
+		for i := range state {
+			witness.Expected[i] = state[i]
+		}
+		err = test.IsSolved(&permutationCircuit{}, &witness, curve.ScalarField())
+		assert.NoError(err, curve.String())
+	}
+}
+
+func TestPermutationKnownAnswer(t *testing.T) {
+	// test vector of the HorizenLabs reference implementation, BN254 with t=3
+	assert := test.NewAssert(t)
+	params, err := GetParameters(ecc.BN254.ScalarField(), 3)
+	assert.NoError(err)
+	state := []*big.Int{big.NewInt(0), big.NewInt(1), big.NewInt(2)}
+	permute[*big.Int](fieldhash.Native{Modulus: ecc.BN254.ScalarField()}, params, state)
+	expected := []string{
+		"0x0bb61d24daca55eebcb1929a82650f328134334da98ea4f847f760054f4a3033",
+		"0x303b6f7c86d043bfcbcc80214f26a30277a15d3f74ca654992defe7ff8d03570",
+		"0x1ed25194542b12eef8617361c3ba7c52e660b145994427cc86296242cf766ec8",
+	}
+	for i := range state {
+		e, _ := new(big.Int).SetString(expected[i], 0)
+		assert.Equal(e, state[i], "state[%d]", i)
+	}
+	var witness permutationCircuit
+	for i := range witness.In {
+		witness.In[i] = i
+		witness.Expected[i] = state[i]
+	}
+	err = test.IsSolved(&permutationCircuit{}, &witness, ecc.BN254.ScalarField())
+	assert.NoError(err)
+}
+
+type merkleDamgardCircuit struct {
+	In       [3]frontend.Variable
+	Expected frontend.Variable
+	name     string
+}
+
+func (c *merkleDamgardCircuit) Define(api frontend.API) error {
+	h, err := hash.GetFieldHasher(c.name, api)
+	if err != nil {
+		return err
+	}
+	h.Write(c.In[:]...)
+	api.AssertIsEqual(h.Sum(), c.Expected)
+	return nil
+}
+
+func TestMerkleDamgardHasher(t *testing.T) {
+	assert := test.NewAssert(t)
+	for _, curve := range gnark.Curves() {
+		h, err := NewNativeMerkleDamgardHasher(curve.ScalarField())
+		assert.NoError(err)
+		witness := merkleDamgardCircuit{}
+		buf := make([]byte, h.BlockSize())
+		for i := range witness.In {
+			v := big.NewInt(int64(i + 42))
+			witness.In[i] = v
+			_, err = h.Write(v.FillBytes(buf))
+			assert.NoError(err)
+		}
+		witness.Expected = new(big.Int).SetBytes(h.Sum(nil))
+		name := "POSEIDON2_" + strings.ToUpper(curve.String())
+		err = test.IsSolved(&merkleDamgardCircuit{name: name}, &witness, curve.ScalarField())
+		assert.NoError(err, name)
+	}
+	// the hasher is specific to the field
+	witness := merkleDamgardCircuit{In: [3]frontend.Variable{0, 0, 0}, Expected: 0}
+	err := test.IsSolved(&merkleDamgardCircuit{name: "POSEIDON2_BLS12_381"}, &witness, ecc.BN254.ScalarField())
+	assert.Error(err)
+}
+
+type emulatedMerkleDamgardCircuit[T emulated.FieldParams] struct {
+	In       [3]emulated.Element[T]
+	Expected emulated.Element[T]
+}
+
+func (c *emulatedMerkleDamgardCircuit[T]) Define(api frontend.API) error {
+	h, err := NewEmulatedMerkleDamgardHasher[T](api)
+	if err != nil {
+		return err
+	}
+	f, err := emulated.NewField[T](api)
+	if err != nil {
+		return err
+	}
+	for i := range c.In {
+		h.Write(&c.In[i])
+	}
+	f.AssertIsEqual(h.Sum(), &c.Expected)
+	return nil
+}
+
+func TestEmulatedMerkleDamgardHasher(t *testing.T) {
+	assert := test.NewAssert(t)
+	h, err := NewNativeMerkleDamgardHasher(goldilocks.Modulus())
+	assert.NoError(err)
+	var witness emulatedMerkleDamgardCircuit[emulated.Goldilocks]
+	buf := make([]byte, h.BlockSize())
+	for i := range witness.In {
+		v := big.NewInt(int64(i + 42))
+		witness.In[i] = emulated.ValueOf[emulated.Goldilocks](v)
+		_, err = h.Write(v.FillBytes(buf))
+		assert.NoError(err)
+	}
+	witness.Expected = emulated.ValueOf[emulated.Goldilocks](new(big.Int).SetBytes(h.Sum(nil)))
+	err = test.IsSolved(&emulatedMerkleDamgardCircuit[emulated.Goldilocks]{}, &witness, ecc.BN254.ScalarField())
+	assert.NoError(err)
+}
+
+type transcriptCircuit struct {
+	Bindings  [2]frontend.Variable
+	Challenge frontend.Variable
+}
+
+func (c *transcriptCircuit) Define(api frontend.API) error {
+	h, err := NewMerkleDamgardHasher(api)
+	if err != nil {
+		return err
+	}
+	ts := fiatshamirgadget.NewTranscript(api, h, []string{"alpha"})
+	if err := ts.Bind("alpha", c.Bindings[:]); err != nil {
+		return err
+	}
+	challenge, err := ts.ComputeChallenge("alpha")
+	if err != nil {
+		return err
+	}
+	api.AssertIsEqual(challenge, c.Challenge)
+	return nil
+}
+
+func TestTranscript(t *testing.T) {
+	assert := test.NewAssert(t)
+	h, err := NewNativeMerkleDamgardHasher(ecc.BN254.ScalarField())
+	assert.NoError(err)
+	ts := fiatshamir.NewTranscript(h, "alpha")
+	var witness transcriptCircuit
+	buf := make([]byte, h.BlockSize())
+	for i := range witness.Bindings {
+		v := big.NewInt(int64(i + 42))
+		witness.Bindings[i] = v
+		assert.NoError(ts.Bind("alpha", v.FillBytes(buf)))
+	}
+	challenge, err := ts.ComputeChallenge("alpha")
+	assert.NoError(err)
+	witness.Challenge = challenge
+	err = test.IsSolved(&transcriptCircuit{}, &witness, ecc.BN254.ScalarField())
+	assert.NoError(err)
+}
+
+type commitmentCircuit struct {
+	X frontend.Variable
+}
+
+func (c *commitmentCircuit) Define(api frontend.API) error {
+	cmt, err := api.(frontend.Committer).Commit(c.X)
+	if err != nil {
+		return fmt.Errorf("commit: %w", err)
+	}
+	api.AssertIsDifferent(cmt, 0)
+	return nil
+}
+
+func TestHashToField(t *testing.T) {
+	assert := test.NewAssert(t)
+	ccs, err := frontend.Compile(ecc.BN254.ScalarField(), r1cs.NewBuilder, &commitmentCircuit{})
+	assert.NoError(err)
+	pk, vk, err := groth16.Setup(ccs)
+	assert.NoError(err)
+	witness, err := frontend.NewWitness(&commitmentCircuit{X: 1}, ecc.BN254.ScalarField())
+	assert.NoError(err)
+	pubWitness, err := witness.Public()
+	assert.NoError(err)
+	proverHasher, err := NewNativeMerkleDamgardHasher(ecc.BN254.ScalarField())
+	assert.NoError(err)
+	verifierHasher, err := NewNativeMerkleDamgardHasher(ecc.BN254.ScalarField())
+	assert.NoError(err)
+	proof, err := groth16.Prove(ccs, pk, witness, backend.WithProverHashToFieldFunction(proverHasher))
+	assert.NoError(err)
+	assert.NoError(groth16.Verify(proof, vk, pubWitness, backend.WithVerifierHashToFieldFunction(verifierHasher)))
+	assert.Error(groth16.Verify(proof, vk, pubWitness))
+}
@@ -0,0 +1,93 @@
+package ripemd160
+
+import (
+	"github.com/consensys/gnark/std/math/uints"
+)
+
+// message word selection and left rotation amounts of the left line
+var (
+	_n = [80]int{
+		0, 1, 2, 3, 4, 5, 6, 7, 8, 9, 10, 11, 12, 13, 14, 15,
+		7, 4, 13, 1, 10, 6, 15, 3, 12, 0, 9, 5, 2, 14, 11, 8,
+		3, 10, 14, 4, 9, 15, 8, 1, 2, 7, 0, 6, 13, 11, 5, 12,
+		1, 9, 11, 10, 0, 8, 12, 4, 13, 3, 7, 15, 14, 5, 6, 2,
+		4, 0, 5, 9, 7, 12, 2, 10, 14, 1, 3, 8, 11, 6, 15, 13,
+	}
+	_r = [80]int{
+		11, 14, 15, 12, 5, 8, 7, 9, 11, 13, 14, 15, 6, 7, 9, 8,
+		7, 6, 8, 13, 11, 9, 7, 15, 7, 12, 15, 9, 11, 7, 13, 12,
+		11, 13, 6, 7, 14, 9, 13, 15, 14, 8, 13, 6, 5, 12, 7, 5,
+		11, 12, 14, 15, 14, 15, 9, 8, 9, 14, 5, 6, 8, 6, 5, 12,
+		9, 15, 5, 11, 6, 8, 13, 12, 5, 12, 13, 14, 11, 8, 5, 6,
+	}
+	_k = [5]uint32{0x00000000, 0x5a827999, 0x6ed9eba1, 0x8f1bbcdc, 0xa953fd4e}
+)
+
+// same for the parallel right line
+var (
+	n_ = [80]int{
+		5, 14, 7, 0, 9, 2, 11, 4, 13, 6, 15, 8, 1, 10, 3, 12,
+		6, 11, 3, 7, 0, 13, 5, 10, 14, 15, 8, 12, 4, 9, 1, 2,
+		15, 5, 1, 3, 7, 14, 6, 9, 11, 8, 12, 2, 10, 0, 4, 13,
+		8, 6, 4, 1, 3, 11, 15, 0, 5, 12, 2, 13, 9, 7, 10, 14,
+		12, 15, 10, 4, 1, 5, 8, 7, 6, 2, 13, 14, 0, 3, 9, 11,
+	}
+	r_ = [80]int{
+		8, 9, 9, 11, 13, 15, 15, 5, 7, 7, 8, 11, 14, 14, 12, 6,
+		9, 13, 15, 7, 12, 8, 9, 11, 7, 7, 12, 7, 6, 15, 13, 11,
+		9, 7, 15, 11, 8, 6, 6, 14, 12, 13, 5, 14, 13, 13, 7, 5,
+		15, 5, 8, 11, 14, 14, 6, 14, 6, 9, 12, 9, 12, 5, 15, 8,
+		8, 5, 12, 9, 12, 5, 14, 6, 8, 13, 6, 5, 15, 13, 11, 11,
+	}
+	k_ = [5]uint32{0x50a28be6, 0x5c4dd124, 0x6d703ef3, 0x7a6d76e9, 0x00000000}
+)
+
+// compress returns the state after processing the 64-byte block p.
+func compress(uapi *uints.BinaryField[uints.U32], state [5]uints.U32, p [64]uints.U8) [5]uints.U32 {
+	var x [16]uints.U32
+	for i := range x {
+		x[i] = uapi.PackLSB(p[4*i], p[4*i+1], p[4*i+2], p[4*i+3])
+	}
+	a, b, c, d, e := state[0], state[1], state[2], state[3], state[4]
+	aa, bb, cc, dd, ee := a, b, c, d, e
+	for i := 0; i < 80; i++ {
+		round := i / 16
+		alpha := uapi.Add(a, f(uapi, round, b, c, d), x[_n[i]], uints.NewU32(_k[round]))
+		alpha = uapi.Add(uapi.Lrot(alpha, _r[i]), e)
+		a, b, c, d, e = e, alpha, b, uapi.Lrot(c, 10), d
+
+		// the right line uses the boolean functions in reverse order
+		alpha = uapi.Add(aa, f(uapi, 4-round, bb, cc, dd), x[n_[i]], uints.NewU32(k_[round]))
+		alpha = uapi.Add(uapi.Lrot(alpha, r_[i]), ee)
+		aa, bb, cc, dd, ee = ee, alpha, bb, uapi.Lrot(cc, 10), dd
+	}
+	return [5]uints.U32{
+		uapi.Add(state[1], c, dd),
+		uapi.Add(state[2], d, ee),
+		uapi.Add(state[3], e, aa),
+		uapi.Add(state[4], a, bb),
+		uapi.Add(state[0], b, cc),
+	}
+}
+
+// f returns the boolean function of the given round. The disjunctions are
+// computed as exclusive or of disjoint terms, or using De Morgan's law.
+func f(uapi *uints.BinaryField[uints.U32], round int, x, y, z uints.U32) uints.U32 {
+	switch round {
+	case 0:
+		// x ^ y ^ z
+		return uapi.Xor(x, y, z)
+	case 1:
+		// (x & y) | (^x & z)
+		return uapi.Xor(uapi.And(x, y), uapi.And(uapi.Not(x), z))
+	case 2:
+		// (x | ^y) ^ z
+		return uapi.Xor(uapi.Not(uapi.And(uapi.Not(x), y)), z)
+	case 3:
+		// (x & z) | (y & ^z)
+		return uapi.Xor(uapi.And(x, z), uapi.And(y, uapi.Not(z)))
+	default:
+		// x ^ (y | ^z)
+		return uapi.Xor(x, uapi.Not(uapi.And(uapi.Not(y), z)))
+	}
+}
@@ -0,0 +1,91 @@
+// Package ripemd160 implements in-circuit RIPEMD-160 hash computation.
+//
+// The implementation follows the specification of [RIPEMD-160] and matches
+// [golang.org/x/crypto/ripemd160].
+//
+// [RIPEMD-160]: https://homes.esat.kuleuven.be/~bosselae/ripemd160.html
+package ripemd160
+
+import (
+	"encoding/binary"
+
+	"github.com/consensys/gnark/frontend"
+	"github.com/consensys/gnark/std/hash"
+	"github.com/consensys/gnark/std/hash/internal/fixedlength"
+	"github.com/consensys/gnark/std/math/uints"
+)
+
+var _seed = uints.NewU32Array([]uint32{
+	0x67452301, 0xefcdab89, 0x98badcfe, 0x10325476, 0xc3d2e1f0,
+})
+
+type digest struct {
+	api  frontend.API
+	uapi *uints.BinaryField[uints.U32]
+	in   []uints.U8
+}
+
+// New returns a new RIPEMD-160 hasher.
+func New(api frontend.API) (hash.BinaryFixedLengthHasher, error) {
+	uapi, err := uints.New[uints.U32](api)
+	if err != nil {
+		return nil, err
+	}
+	return &digest{api: api, uapi: uapi}, nil
+}
+
+func (d *digest) Write(data []uints.U8) {
+	d.in = append(d.in, data...)
+}
+
+func (d *digest) padded(bytesLen int) []uints.U8 {
+	zeroPadLen := 55 - bytesLen%64
+	if zeroPadLen < 0 {
+		zeroPadLen += 64
+	}
+	buf := make([]uints.U8, 0, bytesLen+9+zeroPadLen)
+	buf = append(buf, d.in...)
+	buf = append(buf, uints.NewU8(0x80))
+	buf = append(buf, uints.NewU8Array(make([]uint8, zeroPadLen))...)
+	lenbuf := make([]uint8, 8)
+	binary.LittleEndian.PutUint64(lenbuf, uint64(8*bytesLen))
+	buf = append(buf, uints.NewU8Array(lenbuf)...)
+	return buf
+}
+
+func (d *digest) Sum() []uints.U8 {
+	var runningDigest [5]uints.U32
+	var buf [64]uints.U8
+	copy(runningDigest[:], _seed)
+	padded := d.padded(len(d.in))
+	for i := 0; i < len(padded)/64; i++ {
+		copy(buf[:], padded[i*64:(i+1)*64])
+		runningDigest = compress(d.uapi, runningDigest, buf)
+	}
+	var ret []uints.U8
+	for i := range runningDigest {
+		ret = append(ret, d.uapi.UnpackLSB(runningDigest[i])...)
+	}
+	return ret
+}
+
+// FixedLengthSum implements [hash.BinaryFixedLengthHasher], the padding
+// being that of RIPEMD-160 with the little-endian bit length.
+func (d *digest) FixedLengthSum(length frontend.Variable) []uints.U8 {
+	var runningDigest [5]uints.U32
+	copy(runningDigest[:], _seed)
+	return fixedlength.MerkleDamgardSum(d.api, d.in, length, false, func(block [64]uints.U8) []uints.U8 {
+		runningDigest = compress(d.uapi, runningDigest, block)
+		var ret []uints.U8
+		for i := range runningDigest {
+			ret = append(ret, d.uapi.UnpackLSB(runningDigest[i])...)
+		}
+		return ret
+	})
+}
+
+func (d *digest) Reset() {
+	d.in = nil
+}
+
+func (d *digest) Size() int { return 20 }
@@ -0,0 +1,61 @@
+package ripemd160
+
+import (
+	"testing"
+
+	"github.com/consensys/gnark-crypto/ecc"
+	"github.com/consensys/gnark/frontend"
+	"github.com/consensys/gnark/std/math/uints"
+	"github.com/consensys/gnark/test"
+	"golang.org/x/crypto/ripemd160" //nolint:staticcheck // reference implementation
+)
+
+type ripemd160Circuit struct {
+	In       []uints.U8
+	Length   frontend.Variable
+	Expected [20]uints.U8
+	Full     [20]uints.U8
+}
+
+func (c *ripemd160Circuit) Define(api frontend.API) error {
+	h, err := New(api)
+	if err != nil {
+		return err
+	}
+	uapi, err := uints.New[uints.U32](api)
+	if err != nil {
+		return err
+	}
+	h.Write(c.In)
+	res := h.FixedLengthSum(c.Length)
+	full := h.Sum()
+	for i := range c.Expected {
+		uapi.ByteAssertEq(c.Expected[i], res[i])
+		uapi.ByteAssertEq(c.Full[i], full[i])
+	}
+	return nil
+}
+
+func TestRIPEMD160(t *testing.T) {
+	bts := make([]byte, 130)
+	for i := range bts {
+		bts[i] = byte(i*13 + 1)
+	}
+	native := func(in []byte) []uints.U8 {
+		h := ripemd160.New()
+		h.Write(in)
+		return uints.NewU8Array(h.Sum(nil))
+	}
+	for _, length := range []int{0, 1, 55, 56, 64, 120, 130} {
+		witness := ripemd160Circuit{
+			In:     uints.NewU8Array(bts),
+			Length: length,
+		}
+		copy(witness.Expected[:], native(bts[:length]))
+		copy(witness.Full[:], native(bts))
+		err := test.IsSolved(&ripemd160Circuit{In: make([]uints.U8, len(bts))}, &witness, ecc.BN254.ScalarField())
+		if err != nil {
+			t.Fatalf("length %d: %v", length, err)
+		}
+	}
+}
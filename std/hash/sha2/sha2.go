@@ -9,6 +9,7 @@ import (
 
 	"github.com/consensys/gnark/frontend"
 	"github.com/consensys/gnark/std/hash"
+	"github.com/consensys/gnark/std/hash/internal/fixedlength"
 	"github.com/consensys/gnark/std/math/uints"
 	"github.com/consensys/gnark/std/permutation/sha2"
 )
@@ -17,17 +18,25 @@ var _seed = uints.NewU32Array([]uint32{
 	0x6A09E667, 0xBB67AE85, 0x3C6EF372, 0xA54FF53A, 0x510E527F, 0x9B05688C, 0x1F83D9AB, 0x5BE0CD19,
 })
 
+func init() {
+	frontend.RegisterPublicInputHasher("SHA256", hash.BinaryPublicInputHasher(func(api frontend.API) (hash.BinaryHasher, error) {
+		return New(api)
+	}))
+}
+
 type digest struct {
+	api  frontend.API
 	uapi *uints.BinaryField[uints.U32]
 	in   []uints.U8
 }
 
-func New(api frontend.API) (hash.BinaryHasher, error) {
+// New returns a new SHA2-256 hasher.
+func New(api frontend.API) (hash.BinaryFixedLengthHasher, error) {
 	uapi, err := uints.New[uints.U32](api)
 	if err != nil {
 		return nil, err
 	}
-	return &digest{uapi: uapi}, nil
+	return &digest{api: api, uapi: uapi}, nil
 }
 
 func (d *digest) Write(data []uints.U8) {
@@ -70,16 +79,19 @@ func (d *digest) Sum() []uints.U8 {
 	return ret
 }
 
+// FixedLengthSum implements [hash.BinaryFixedLengthHasher], the padding
+// being that of SHA-256 with the big-endian bit length.
 func (d *digest) FixedLengthSum(length frontend.Variable) []uints.U8 {
-	panic("TODO")
-	// we need to do two things here -- first the padding has to be put to the
-	// right place. For that we need to know how many blocks we have used. We
-	// need to fit at least 9 more bytes (padding byte and 8 bytes for input
-	// length). Knowing the block, we have to keep running track if the current
-	// block is the expected one.
-	//
-	// idea - have a mask for blocks where 1 is only for the block we want to
-	// use.
+	var runningDigest [8]uints.U32
+	copy(runningDigest[:], _seed)
+	return fixedlength.MerkleDamgardSum(d.api, d.in, length, true, func(block [64]uints.U8) []uints.U8 {
+		runningDigest = sha2.Permute(d.uapi, runningDigest, block)
+		var ret []uints.U8
+		for i := range runningDigest {
+			ret = append(ret, d.uapi.UnpackMSB(runningDigest[i])...)
+		}
+		return ret
+	})
 }
 
 func (d *digest) Reset() {
@@ -48,3 +48,51 @@ func TestSHA2(t *testing.T) {
 		t.Fatal(err)
 	}
 }
+
+type sha2FixedLengthCircuit struct {
+	In       []uints.U8
+	Length   frontend.Variable
+	Expected [32]uints.U8
+}
+
+func (c *sha2FixedLengthCircuit) Define(api frontend.API) error {
+	h, err := New(api)
+	if err != nil {
+		return err
+	}
+	uapi, err := uints.New[uints.U32](api)
+	if err != nil {
+		return err
+	}
+	h.Write(c.In)
+	res := h.FixedLengthSum(c.Length)
+	for i := range c.Expected {
+		uapi.ByteAssertEq(c.Expected[i], res[i])
+	}
+	return nil
+}
+
+func TestSHA2FixedLengthSum(t *testing.T) {
+	bts := make([]byte, 130)
+	for i := range bts {
+		bts[i] = byte(i * 7)
+	}
+	for _, length := range []int{0, 1, 55, 56, 63, 64, 119, 120, 130} {
+		dgst := sha256.Sum256(bts[:length])
+		witness := sha2FixedLengthCircuit{
+			In:     uints.NewU8Array(bts),
+			Length: length,
+		}
+		copy(witness.Expected[:], uints.NewU8Array(dgst[:]))
+		err := test.IsSolved(&sha2FixedLengthCircuit{In: make([]uints.U8, len(bts))}, &witness, ecc.BN254.ScalarField())
+		if err != nil {
+			t.Fatalf("length %d: %v", length, err)
+		}
+	}
+	// the length can't exceed the number of bytes written
+	witness := sha2FixedLengthCircuit{In: uints.NewU8Array(bts), Length: len(bts) + 1}
+	copy(witness.Expected[:], uints.NewU8Array(make([]byte, 32)))
+	if err := test.IsSolved(&sha2FixedLengthCircuit{In: make([]uints.U8, len(bts))}, &witness, ecc.BN254.ScalarField()); err == nil {
+		t.Fatal("expected an error for a length larger than the input")
+	}
+}
@@ -2,4 +2,10 @@
 // Keccak f-[1600] permutation function.
 //
 // Instances correspond golang.org/x/crypto/sha3, except SHA224, which is not x64 compatible.
+//
+// The hashers implement
+// [github.com/consensys/gnark/std/hash.BinaryFixedLengthHasher], so that a
+// circuit can hash a prefix of variable length of the written bytes, for
+// example Ethereum RLP encoded data or calldata of unknown length up to a
+// maximum.
 package sha3
@@ -1,11 +1,18 @@
 package sha3
 
 import (
+	"math/bits"
+
+	"github.com/consensys/gnark/frontend"
+	"github.com/consensys/gnark/std/hash/internal/fixedlength"
+	"github.com/consensys/gnark/std/math/integer"
 	"github.com/consensys/gnark/std/math/uints"
 	"github.com/consensys/gnark/std/permutation/keccakf"
+	"github.com/consensys/gnark/std/selector"
 )
 
 type digest struct {
+	api       frontend.API
 	uapi      *uints.BinaryField[uints.U64]
 	state     [25]uints.U64 // 1600 bits state: 25 x 64
 	in        []uints.U8    // input to be digested
@@ -32,6 +39,45 @@ func (d *digest) Sum() []uints.U8 {
 	return d.squeezeBlocks()
 }
 
+// FixedLengthSum implements [hash.BinaryFixedLengthHasher]. The padded input
+// has length/rate+1 blocks: the input, the domain separation byte, zeros and
+// the final bit of the padding at the end of the last block.
+func (d *digest) FixedLengthSum(length frontend.Variable) []uints.U8 {
+	maxLen := len(d.in)
+	maxBlocks := maxLen/d.rate + 1
+	data, padding := fixedlength.Mask(d.api, d.in, length, maxBlocks*d.rate)
+	bound := bits.Len(uint(maxLen))
+	if b := bits.Len(uint(d.rate)); b > bound {
+		bound = b
+	}
+	lastIdx, _ := integer.DivMod(d.api, length, d.rate, bound)
+	lastBlock := selector.Decoder(d.api, maxBlocks, lastIdx)
+	for i := range data {
+		data[i] = d.api.MulAcc(data[i], padding[i], d.dsbyte)
+		if i%d.rate == d.rate-1 {
+			data[i] = d.api.MulAcc(data[i], lastBlock[i/d.rate], 0x80)
+		}
+	}
+
+	// the digest is squeezed from the state after the last block
+	state := d.state
+	return fixedlength.SelectDigest(d.api, lastBlock, func(i int) []uints.U8 {
+		for j := 0; j < d.rate/8; j++ {
+			var word uints.U64
+			for k := range word {
+				word[k] = uints.U8{Val: data[i*d.rate+8*j+k]}
+			}
+			state[j] = d.uapi.Xor(state[j], word)
+		}
+		state = keccakf.Permute(d.uapi, state)
+		var ret []uints.U8
+		for j := 0; j < d.outputLen/8; j++ {
+			ret = append(ret, d.uapi.UnpackLSB(state[j])...)
+		}
+		return ret
+	})
+}
+
 func (d *digest) padding() []uints.U8 {
 	padded := make([]uints.U8, len(d.in))
 	copy(padded[:], d.in[:])
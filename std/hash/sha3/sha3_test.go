@@ -7,7 +7,10 @@ import (
 	"testing"
 
 	"github.com/consensys/gnark-crypto/ecc"
+	"github.com/consensys/gnark/backend/witness"
+	"github.com/consensys/gnark/constraint"
 	"github.com/consensys/gnark/frontend"
+	"github.com/consensys/gnark/frontend/cs/scs"
 	zkhash "github.com/consensys/gnark/std/hash"
 	"github.com/consensys/gnark/std/math/uints"
 	"github.com/consensys/gnark/test"
@@ -15,7 +18,7 @@ import (
 )
 
 type testCase struct {
-	zk     func(api frontend.API) (zkhash.BinaryHasher, error)
+	zk     func(api frontend.API) (zkhash.BinaryFixedLengthHasher, error)
 	native func() hash.Hash
 }
 
@@ -88,3 +91,97 @@ func TestSHA3(t *testing.T) {
 		}, name)
 	}
 }
+
+type sha3FixedLengthCircuit struct {
+	In       []uints.U8
+	Length   frontend.Variable
+	Expected []uints.U8
+
+	hasher string
+}
+
+func (c *sha3FixedLengthCircuit) Define(api frontend.API) error {
+	newHasher, ok := testCases[c.hasher]
+	if !ok {
+		return fmt.Errorf("hash function unknown: %s", c.hasher)
+	}
+	h, err := newHasher.zk(api)
+	if err != nil {
+		return err
+	}
+	uapi, err := uints.New[uints.U64](api)
+	if err != nil {
+		return err
+	}
+
+	h.Write(c.In)
+	res := h.FixedLengthSum(c.Length)
+
+	for i := range c.Expected {
+		uapi.ByteAssertEq(c.Expected[i], res[i])
+	}
+	return nil
+}
+
+func TestSHA3FixedLengthSum(t *testing.T) {
+	assert := test.NewAssert(t)
+	in := make([]byte, 310)
+	_, err := rand.Reader.Read(in)
+	assert.NoError(err)
+
+	for name := range testCases {
+		assert.Run(func(assert *test.Assert) {
+			name := name
+			strategy := testCases[name]
+			rate := strategy.native().BlockSize()
+			// all the lengths of an input spanning three padded blocks for
+			// SHA3-512, which has the smallest rate, solved with the compiled
+			// circuit to bound the test time. Only the lengths around the block
+			// boundaries and in the middle of the blocks for the other hashers,
+			// and only around the block boundaries in short mode.
+			maxLen := 2*rate + 1
+			sweep := !testing.Short() && name == "SHA3-512"
+			var lengths []int
+			for i := 0; i <= maxLen; i++ {
+				j := i % rate
+				if !sweep && j > 1 && j < rate-1 && (testing.Short() || j != rate/2) {
+					continue
+				}
+				lengths = append(lengths, i)
+			}
+			circuit := &sha3FixedLengthCircuit{
+				In:       make([]uints.U8, maxLen),
+				Expected: make([]uints.U8, strategy.native().Size()),
+				hasher:   name,
+			}
+			var ccs constraint.ConstraintSystem
+			if sweep {
+				ccs, err = frontend.Compile(ecc.BN254.ScalarField(), scs.NewBuilder, circuit)
+				assert.NoError(err)
+			}
+			for _, length := range lengths {
+				h := strategy.native()
+				h.Write(in[:length])
+				expected := h.Sum(nil)
+
+				assignment := &sha3FixedLengthCircuit{
+					In:       uints.NewU8Array(in[:maxLen]),
+					Length:   length,
+					Expected: uints.NewU8Array(expected),
+				}
+
+				if ccs == nil {
+					err = test.IsSolved(circuit, assignment, ecc.BN254.ScalarField())
+				} else {
+					var w witness.Witness
+					if w, err = frontend.NewWitness(assignment, ecc.BN254.ScalarField()); err == nil {
+						_, err = ccs.Solve(w)
+					}
+				}
+				if err != nil {
+					t.Fatalf("%s, length %d: %s", name, length, err)
+				}
+			}
+		}, name)
+	}
+}
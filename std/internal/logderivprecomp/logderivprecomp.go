@@ -66,6 +66,25 @@ func New(api frontend.API, fn solver.Hint, rets []uint) (*Precomputed, error) {
 	return t, nil
 }
 
+// ReplayableState implements [frontend.ReplayableState], the queries are
+// recorded through [frontend.UpdateState].
+func (t *Precomputed) ReplayableState() {}
+
+// Fork implements [frontend.MergeableState].
+func (t *Precomputed) Fork(api frontend.API) frontend.MergeableState {
+	return &Precomputed{api: api, compute: t.compute, rets: t.rets}
+}
+
+// Merge implements [frontend.MergeableState].
+func (t *Precomputed) Merge(fork frontend.MergeableState, remap func(frontend.Variable) frontend.Variable) {
+	queries := fork.(*Precomputed).queries
+	frontend.UpdateState(t.api, func(outer func(frontend.Variable) frontend.Variable) {
+		for _, q := range queries {
+			t.queries = append(t.queries, outer(remap(q)))
+		}
+	})
+}
+
 func (t *Precomputed) pack(x, y frontend.Variable, rets []frontend.Variable) frontend.Variable {
 	shift := big.NewInt(1 << 8)
 	packed := t.api.Add(x, t.api.Mul(y, shift))
@@ -85,7 +104,9 @@ func (t *Precomputed) Query(x, y frontend.Variable) []frontend.Variable {
 		panic(err)
 	}
 	packed := t.pack(x, y, rets)
-	t.queries = append(t.queries, packed)
+	frontend.UpdateState(t.api, func(remap func(frontend.Variable) frontend.Variable) {
+		t.queries = append(t.queries, remap(packed))
+	})
 	return rets
 }
 
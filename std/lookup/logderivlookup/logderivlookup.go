@@ -61,13 +61,17 @@ func (t *Table) Insert(val frontend.Variable) (index int) {
 	if t.immutable {
 		panic("inserting into committed lookup table")
 	}
-	t.entries = append(t.entries, val)
+	index = len(t.entries)
+	frontend.UpdateState(t.api, func(remap func(frontend.Variable) frontend.Variable) {
+		val := remap(val)
+		t.entries = append(t.entries, val)
 
-	// each time we insert a new entry, we update the blueprint
-	v := t.api.Compiler().ToCanonicalVariable(val)
-	v.Compress(&t.blueprint.EntriesCalldata)
+		// each time we insert a new entry, we update the blueprint
+		v := t.api.Compiler().ToCanonicalVariable(val)
+		v.Compress(&t.blueprint.EntriesCalldata)
+	})
 
-	return len(t.entries) - 1
+	return index
 }
 
 // Lookup lookups up values from the lookup tables given by the indices inds. It
@@ -127,7 +131,11 @@ func (t *Table) performLookup(inds []frontend.Variable) []frontend.Variable {
 		internalVariables[i] = compiler.InternalVariable(outputs[i])
 		lookupResult[i] = result{ind: inds[i], val: internalVariables[i]}
 	}
-	t.results = append(t.results, lookupResult...)
+	frontend.UpdateState(t.api, func(remap func(frontend.Variable) frontend.Variable) {
+		for _, r := range lookupResult {
+			t.results = append(t.results, result{ind: remap(r.ind), val: remap(r.val)})
+		}
+	})
 	return internalVariables
 }
 
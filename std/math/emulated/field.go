@@ -44,7 +44,58 @@ type Field[T FieldParams] struct {
 	constrainedLimbs map[uint64]struct{}
 	checker          frontend.Rangechecker
 
+	// parent is set when the field is used by a parallel task. The width
+	// checks of the task are then made by parent when the range checks of the
+	// task are merged, see [rangecheck.Defer].
+	parent *Field[T]
+
 	mulChecks []mulCheck[T]
+
+	// copies of the elements of the multiplication checks replayed by the
+	// components
+	replayed map[replayedKey[T]]*Element[T]
+}
+
+// ReplayableState implements [frontend.ReplayableState], the multiplication
+// checks and the constrained limbs are updated through [frontend.UpdateState].
+func (f *Field[T]) ReplayableState() {}
+
+// Fork implements [frontend.MergeableState].
+func (f *Field[T]) Fork(api frontend.API) frontend.MergeableState {
+	return &Field[T]{
+		api:     api,
+		log:     f.log,
+		checker: rangecheck.New(api),
+		parent:  f,
+	}
+}
+
+// Merge implements [frontend.MergeableState]. The width checks of the task
+// are made when the range checks of the task are merged.
+func (f *Field[T]) Merge(fork frontend.MergeableState, remap func(frontend.Variable) frontend.Variable) {
+	ff := fork.(*Field[T])
+	frontend.UpdateState(f.api, func(outer func(frontend.Variable) frontend.Variable) {
+		mapped := func(v frontend.Variable) frontend.Variable { return outer(remap(v)) }
+		// the elements shared by the checks of the task stay shared, and the
+		// elements of the parent are kept as is, as if the task was defined
+		// sequentially
+		copies := make(map[*Element[T]]*Element[T])
+		copyOf := func(e *Element[T]) *Element[T] {
+			if c, ok := copies[e]; ok {
+				return c
+			}
+			c := f.remapElement(e, mapped)
+			if f.limbsKey(c.Limbs) == f.limbsKey(e.Limbs) {
+				c = e
+			}
+			copies[e] = c
+			return c
+		}
+		for _, mc := range ff.mulChecks {
+			mc.f = f
+			f.mulChecks = append(f.mulChecks, mc.remap(copyOf))
+		}
+	})
 }
 
 type ctxKey[T FieldParams] struct{}
@@ -193,6 +244,26 @@ func (f *Field[T]) enforceWidthConditional(a *Element[T]) (didConstrain bool) {
 		// constant values are constant
 		return false
 	}
+	// the limbs already constrained depend on the previous operations, so the
+	// components using the field decide again when they are replayed
+	frontend.UpdateState(f.api, func(remap func(frontend.Variable) frontend.Variable) {
+		a := f.remapElement(a, remap)
+		if p := f.parent; p != nil {
+			// the limbs constrained by the tasks defined before are decided
+			// when the task is merged, as if the tasks were sequential
+			rangecheck.Defer(f.checker, func(remap func(frontend.Variable) frontend.Variable) {
+				p.enforceWidthOnce(p.remapElement(a, remap))
+			})
+			return
+		}
+		didConstrain = f.enforceWidthOnce(a)
+	})
+	return
+}
+
+// enforceWidthOnce enforces the width of the limbs of a if some of them are
+// not yet constrained, and marks them as constrained.
+func (f *Field[T]) enforceWidthOnce(a *Element[T]) (didConstrain bool) {
 	for i := range a.Limbs {
 		if !frontend.IsCanonical(a.Limbs[i]) {
 			// this is not a canonical variable, nor a constant. This may happen
@@ -229,6 +300,19 @@ func (f *Field[T]) enforceWidthConditional(a *Element[T]) (didConstrain bool) {
 	return
 }
 
+// remapElement returns a copy of e with its limbs mapped by remap, when
+// replaying a state update.
+func (f *Field[T]) remapElement(e *Element[T], remap func(frontend.Variable) frontend.Variable) *Element[T] {
+	if e == nil {
+		return nil
+	}
+	limbs := make([]frontend.Variable, len(e.Limbs))
+	for i := range e.Limbs {
+		limbs[i] = remap(e.Limbs[i])
+	}
+	return &Element[T]{Limbs: limbs, overflow: e.overflow, internal: e.internal}
+}
+
 func (f *Field[T]) constantValue(v *Element[T]) (*big.Int, bool) {
 	var ok bool
 
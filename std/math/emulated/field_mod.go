@@ -0,0 +1,88 @@
+package emulated
+
+import (
+	"fmt"
+	"math/big"
+
+	"github.com/consensys/gnark/frontend"
+)
+
+// ModMul computes a*b modulo the given modulus. Unlike [Field.Mul], the
+// modulus is not the one of the parameters T but an element given at runtime,
+// which must be non-zero: the solver returns an error otherwise. The type parameter T only defines the width of the
+// elements and should be large enough to contain the inputs and the modulus,
+// for example [emparams.Mod1e512] or [emparams.Mod1e4096].
+//
+// The result is congruent to a*b and has the width of T, but may not be less
+// than the modulus, see [Field.ModReduce]. The number of constraints depends
+// on T rather than on the actual size of the modulus.
+func (f *Field[T]) ModMul(a, b, modulus *Element[T]) *Element[T] {
+	f.checkModulus(modulus)
+	if _, err := f.mulPreCond(a, b); err != nil {
+		panic(err)
+	}
+	return f.modMul(a, b, modulus)
+}
+
+// ModExp computes base^exp modulo the given modulus, see [Field.ModMul]. The
+// exponent is decomposed on the full width of T.
+func (f *Field[T]) ModExp(base, exp, modulus *Element[T]) *Element[T] {
+	f.checkModulus(modulus)
+	expBits := f.ToBits(exp)
+	n := len(expBits)
+	res := f.Select(expBits[n-1], base, f.One())
+	for i := n - 2; i >= 0; i-- {
+		res = f.modMul(res, res, modulus)
+		res = f.Select(expBits[i], f.modMul(res, base, modulus), res)
+	}
+	return res
+}
+
+// ModReduce returns the element congruent to a modulo the given non-zero
+// modulus and less than it, see [Field.ModMul].
+func (f *Field[T]) ModReduce(a, modulus *Element[T]) *Element[T] {
+	f.checkModulus(modulus)
+	r := f.modMul(a, f.One(), modulus)
+
+	// r < modulus as modulus = r + d + 1 with d >= 0
+	hintInputs := []frontend.Variable{f.fParams.BitsPerLimb(), f.fParams.NbLimbs()}
+	hintInputs = append(hintInputs, modulus.Limbs...)
+	hintInputs = append(hintInputs, r.Limbs...)
+	d, err := f.api.NewHint(modDiffHint, int(f.fParams.NbLimbs()), hintInputs...)
+	if err != nil {
+		panic(fmt.Sprintf("hint error: %v", err))
+	}
+	rd := f.add(r, f.packLimbs(d, true), 1)
+	f.AssertLimbsEquality(f.add(rd, f.One(), 2), modulus)
+	return r
+}
+
+// checkModulus ensures that the runtime modulus has the width of T.
+func (f *Field[T]) checkModulus(modulus *Element[T]) {
+	if len(modulus.Limbs) != int(f.fParams.NbLimbs()) || modulus.overflow != 0 {
+		panic("modulus must have the width of the parameters")
+	}
+	f.enforceWidthConditional(modulus)
+}
+
+// modDiffHint computes p-1-r for the limbs of p and r.
+func modDiffHint(_ *big.Int, inputs, outputs []*big.Int) error {
+	nbBits := uint(inputs[0].Int64())
+	nbLimbs := int(inputs[1].Int64())
+	if len(inputs) != 2+2*nbLimbs || len(outputs) != nbLimbs {
+		return fmt.Errorf("expected %d inputs and %d outputs", 2+2*nbLimbs, nbLimbs)
+	}
+	p, r := new(big.Int), new(big.Int)
+	if err := recompose(inputs[2:2+nbLimbs], nbBits, p); err != nil {
+		return fmt.Errorf("recompose modulus: %w", err)
+	}
+	if err := recompose(inputs[2+nbLimbs:], nbBits, r); err != nil {
+		return fmt.Errorf("recompose remainder: %w", err)
+	}
+	d := new(big.Int).Sub(p, r)
+	d.Sub(d, big.NewInt(1))
+	if d.Sign() < 0 {
+		return fmt.Errorf("remainder not less than modulus")
+	}
+	return decompose(d, nbBits, outputs)
+}
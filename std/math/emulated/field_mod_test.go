@@ -0,0 +1,100 @@
+package emulated
+
+import (
+	"crypto/rand"
+	"math/big"
+	"testing"
+
+	"github.com/consensys/gnark/frontend"
+	"github.com/consensys/gnark/frontend/cs/r1cs"
+	"github.com/consensys/gnark/std/math/emulated/emparams"
+	"github.com/consensys/gnark/test"
+)
+
+type modCircuit[T FieldParams] struct {
+	A, B, Modulus  Element[T]
+	Product, Power Element[T]
+}
+
+func (c *modCircuit[T]) Define(api frontend.API) error {
+	f, err := NewField[T](api)
+	if err != nil {
+		return err
+	}
+	f.AssertLimbsEquality(f.ModReduce(f.ModMul(&c.A, &c.B, &c.Modulus), &c.Modulus), &c.Product)
+	f.AssertLimbsEquality(f.ModReduce(f.ModExp(&c.A, &c.B, &c.Modulus), &c.Modulus), &c.Power)
+	return nil
+}
+
+func TestModOperations(t *testing.T) {
+	assert := test.NewAssert(t)
+	type T = emparams.Mod1e512
+	bound := new(big.Int).Lsh(big.NewInt(1), 512)
+	for _, nbBits := range []int{1, 64, 300, 511} {
+		modulus, _ := rand.Int(rand.Reader, new(big.Int).Lsh(big.NewInt(1), uint(nbBits)))
+		modulus.Add(modulus, big.NewInt(1))
+		a, _ := rand.Int(rand.Reader, bound)
+		b, _ := rand.Int(rand.Reader, big.NewInt(1<<16))
+		product := new(big.Int).Mul(a, b)
+		product.Mod(product, modulus)
+		power := new(big.Int).Exp(a, b, modulus)
+		witness := modCircuit[T]{
+			A:       ValueOf[T](a),
+			B:       ValueOf[T](b),
+			Modulus: ValueOf[T](modulus),
+			Product: ValueOf[T](product),
+			Power:   ValueOf[T](power),
+		}
+		err := test.IsSolved(&modCircuit[T]{}, &witness, testCurve.ScalarField())
+		assert.NoError(err, "modulus of %d bits", nbBits)
+	}
+}
+
+type modMulCircuit[T FieldParams] struct {
+	A, B, Modulus, Product Element[T]
+}
+
+func (c *modMulCircuit[T]) Define(api frontend.API) error {
+	f, err := NewField[T](api)
+	if err != nil {
+		return err
+	}
+	f.AssertLimbsEquality(f.ModMul(&c.A, &c.B, &c.Modulus), &c.Product)
+	return nil
+}
+
+type modReduceCircuit[T FieldParams] struct {
+	A, Modulus, Reduced Element[T]
+}
+
+func (c *modReduceCircuit[T]) Define(api frontend.API) error {
+	f, err := NewField[T](api)
+	if err != nil {
+		return err
+	}
+	f.AssertLimbsEquality(f.ModReduce(&c.A, &c.Modulus), &c.Reduced)
+	return nil
+}
+
+func TestModZeroModulus(t *testing.T) {
+	assert := test.NewAssert(t)
+	type T = emparams.Mod1e512
+	for _, c := range []struct {
+		name             string
+		circuit, witness frontend.Circuit
+	}{
+		{"ModMul", &modMulCircuit[T]{}, &modMulCircuit[T]{A: ValueOf[T](3), B: ValueOf[T](5), Modulus: ValueOf[T](0), Product: ValueOf[T](15)}},
+		{"ModReduce", &modReduceCircuit[T]{}, &modReduceCircuit[T]{A: ValueOf[T](3), Modulus: ValueOf[T](0), Reduced: ValueOf[T](3)}},
+	} {
+		assert.Run(func(assert *test.Assert) {
+			err := test.IsSolved(c.circuit, c.witness, testCurve.ScalarField())
+			assert.Error(err)
+
+			ccs, err := frontend.Compile(testCurve.ScalarField(), r1cs.NewBuilder, c.circuit)
+			assert.NoError(err)
+			w, err := frontend.NewWitness(c.witness, testCurve.ScalarField())
+			assert.NoError(err)
+			assert.Error(ccs.IsSolved(w))
+		}, c.name)
+	}
+}
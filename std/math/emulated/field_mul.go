@@ -1,6 +1,7 @@
 package emulated
 
 import (
+	"encoding/binary"
 	"fmt"
 	"math/big"
 	"math/bits"
@@ -58,6 +59,7 @@ type mulCheck[T FieldParams] struct {
 	r    *Element[T] // reduced value
 	k    *Element[T] // coefficient
 	c    *Element[T] // carry
+	p    *Element[T] // modulus, nil for the modulus of the emulated field
 }
 
 // evalRound1 evaluates first c(X), r(X) and k(X) at a given random point at[0].
@@ -67,6 +69,9 @@ func (mc *mulCheck[T]) evalRound1(api frontend.API, at []frontend.Variable) {
 	mc.c = mc.f.evalWithChallenge(mc.c, at)
 	mc.r = mc.f.evalWithChallenge(mc.r, at)
 	mc.k = mc.f.evalWithChallenge(mc.k, at)
+	if mc.p != nil {
+		mc.p = mc.f.evalWithChallenge(mc.p, at)
+	}
 }
 
 // evalRound2 now evaluates a and b at a given random point at[0]. However, it
@@ -79,8 +84,12 @@ func (mc *mulCheck[T]) evalRound2(api frontend.API, at []frontend.Variable) {
 
 // check checks a(ch) * b(ch) = r(ch) + k(ch) * p(ch) + (2^t - ch) c(ch). As the
 // computation of p(ch) and (2^t-ch) can be shared over all mulCheck instances,
-// then we get them already evaluated as peval and coef.
+// then we get them already evaluated as peval and coef. The evaluation of a
+// runtime modulus replaces peval.
 func (mc *mulCheck[T]) check(api frontend.API, peval, coef frontend.Variable) {
+	if mc.p != nil {
+		peval = mc.p.evaluation
+	}
 	ls := api.Mul(mc.a.evaluation, mc.b.evaluation)
 	rs := api.Add(mc.r.evaluation, api.Mul(peval, mc.k.evaluation), api.Mul(mc.c.evaluation, coef))
 	api.AssertIsEqual(ls, rs)
@@ -99,14 +108,23 @@ func (mc *mulCheck[T]) cleanEvaluations() {
 	mc.k.isEvaluated = false
 	mc.c.evaluation = 0
 	mc.c.isEvaluated = false
+	if mc.p != nil {
+		mc.p.evaluation = 0
+		mc.p.isEvaluated = false
+	}
 }
 
 // mulMod returns a*b mod r. In practice it computes the result using a hint and
 // defers the actual multiplication check.
 func (f *Field[T]) mulMod(a, b *Element[T], _ uint) *Element[T] {
+	return f.modMul(a, b, nil)
+}
+
+// modMul returns a*b mod p, or modulo the emulated modulus if p is nil.
+func (f *Field[T]) modMul(a, b, p *Element[T]) *Element[T] {
 	f.enforceWidthConditional(a)
 	f.enforceWidthConditional(b)
-	k, r, c, err := f.callMulHint(a, b)
+	k, r, c, err := f.callMulHint(a, b, p)
 	if err != nil {
 		panic(err)
 	}
@@ -117,11 +135,64 @@ func (f *Field[T]) mulMod(a, b *Element[T], _ uint) *Element[T] {
 		c: c,
 		k: k,
 		r: r,
+		p: p,
 	}
-	f.mulChecks = append(f.mulChecks, mc)
+	// the update is applied first to this call: the check shares the elements
+	// to evaluate them once. When it is replayed by a component, the check gets
+	// copies of the elements, shared in the same way.
+	recorded := false
+	frontend.UpdateState(f.api, func(remap func(frontend.Variable) frontend.Variable) {
+		if !recorded {
+			recorded = true
+			f.mulChecks = append(f.mulChecks, mc)
+			return
+		}
+		f.mulChecks = append(f.mulChecks, mc.remap(func(e *Element[T]) *Element[T] {
+			return f.replayedElement(e, remap)
+		}))
+	})
 	return r
 }
 
+// remap returns the check with its elements mapped by m.
+func (mc *mulCheck[T]) remap(m func(*Element[T]) *Element[T]) mulCheck[T] {
+	res := mulCheck[T]{
+		f: mc.f,
+		a: m(mc.a),
+		b: m(mc.b),
+		c: m(mc.c),
+		k: m(mc.k),
+		r: m(mc.r),
+	}
+	if mc.p != nil {
+		res.p = m(mc.p)
+	}
+	return res
+}
+
+// replayedKey identifies the copy of an element in the checks replayed by the
+// components: the recorded element and its limbs in the replaying call.
+type replayedKey[T FieldParams] struct {
+	e     *Element[T]
+	limbs string
+}
+
+// replayedElement returns the copy of e with its limbs mapped by remap. The
+// copy is shared by the replayed checks using e with the same limbs, as e is by
+// the recorded checks, so that it is evaluated once at the challenge.
+func (f *Field[T]) replayedElement(e *Element[T], remap func(frontend.Variable) frontend.Variable) *Element[T] {
+	c := f.remapElement(e, remap)
+	key := replayedKey[T]{e: e, limbs: f.limbsKey(c.Limbs)}
+	if shared, ok := f.replayed[key]; ok {
+		return shared
+	}
+	if f.replayed == nil {
+		f.replayed = make(map[replayedKey[T]]*Element[T])
+	}
+	f.replayed[key] = c
+	return c
+}
+
 // evalWithChallenge represents element a as a polynomial a(X) and evaluates at
 // at[0]. For efficiency, we use already evaluated powers of at[0] given by at.
 // It stores the evaluation result inside the Element and marks it as evaluated.
@@ -142,6 +213,19 @@ func (f *Field[T]) evalWithChallenge(a *Element[T], at []frontend.Variable) *Ele
 	return a
 }
 
+// limbsKey returns a key identifying the limbs of an element.
+func (f *Field[T]) limbsKey(limbs []frontend.Variable) string {
+	calldata := make([]uint32, 0, 4*len(limbs))
+	for _, l := range limbs {
+		f.api.Compiler().ToCanonicalVariable(l).Compress(&calldata)
+	}
+	key := make([]byte, 4*len(calldata))
+	for i := range calldata {
+		binary.LittleEndian.PutUint32(key[4*i:], calldata[i])
+	}
+	return string(key)
+}
+
 // performMulChecks should be deferred to actually perform all the
 // multiplication checks.
 func (f *Field[T]) performMulChecks(api frontend.API) error {
@@ -167,6 +251,9 @@ func (f *Field[T]) performMulChecks(api frontend.API) error {
 		toCommit = append(toCommit, f.mulChecks[i].r.Limbs...)
 		toCommit = append(toCommit, f.mulChecks[i].k.Limbs...)
 		toCommit = append(toCommit, f.mulChecks[i].c.Limbs...)
+		if f.mulChecks[i].p != nil {
+			toCommit = append(toCommit, f.mulChecks[i].p.Limbs...)
+		}
 	}
 	// we give all the inputs as inputs to obtain random verifier challenge.
 	multicommit.WithCommitment(api, func(api frontend.API, commitment frontend.Variable) error {
@@ -209,8 +296,9 @@ func (f *Field[T]) performMulChecks(api frontend.API) error {
 	return nil
 }
 
-// callMulHint uses hint to compute r, k and c.
-func (f *Field[T]) callMulHint(a, b *Element[T]) (quo, rem, carries *Element[T], err error) {
+// callMulHint uses hint to compute r, k and c, modulo p or the emulated modulus
+// if p is nil.
+func (f *Field[T]) callMulHint(a, b, p *Element[T]) (quo, rem, carries *Element[T], err error) {
 	// inputs is always nblimbs
 	// quotient may be larger if inputs have overflow
 	// remainder is always nblimbs
@@ -219,8 +307,15 @@ func (f *Field[T]) callMulHint(a, b *Element[T]) (quo, rem, carries *Element[T],
 	// skip error handle - it happens when we are supposed to reduce. But we
 	// already check it as a precondition. We only need the overflow here.
 	nbLimbs, nbBits := f.fParams.NbLimbs(), f.fParams.BitsPerLimb()
+	modBits := uint(f.fParams.Modulus().BitLen())
+	if p == nil {
+		p = f.Modulus()
+	} else {
+		// the runtime modulus may be as small as 1
+		modBits = 1
+	}
 	nbQuoLimbs := ((2*nbLimbs-1)*nbBits + nextOverflow + 1 - //
-		uint(f.fParams.Modulus().BitLen()) + //
+		modBits + //
 		nbBits - 1) /
 		nbBits
 	nbRemLimbs := nbLimbs
@@ -229,7 +324,7 @@ func (f *Field[T]) callMulHint(a, b *Element[T]) (quo, rem, carries *Element[T],
 		nbBits,
 		nbLimbs,
 	}
-	hintInputs = append(hintInputs, f.Modulus().Limbs...)
+	hintInputs = append(hintInputs, p.Limbs...)
 	hintInputs = append(hintInputs, a.Limbs...)
 	hintInputs = append(hintInputs, b.Limbs...)
 	ret, err := f.api.NewHint(mulHint, int(nbQuoLimbs)+int(nbRemLimbs)+int(nbCarryLimbs), hintInputs...)
@@ -274,6 +369,10 @@ func mulHint(field *big.Int, inputs, outputs []*big.Int) error {
 	if err := recompose(blimbs, uint(nbBits), b); err != nil {
 		return fmt.Errorf("recompose b: %w", err)
 	}
+	if p.Sign() == 0 {
+		// the modulus of [Field.ModMul] is given at runtime
+		return fmt.Errorf("modulus is zero")
+	}
 	quo := new(big.Int)
 	rem := new(big.Int)
 	ab := new(big.Int).Mul(a, b)
@@ -25,6 +25,7 @@ func GetHints() []solver.Hint {
 		RightShift,
 		SqrtHint,
 		mulHint,
+		modDiffHint,
 	}
 }
 
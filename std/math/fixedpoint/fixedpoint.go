@@ -0,0 +1,257 @@
+// Package fixedpoint implements signed fixed-point arithmetic in circuits.
+//
+// A [Fixed] value x of a [Format] with IntBits integer bits (including the
+// sign bit) and FracBits fraction bits is represented by the integer
+// V = x * 2^FracBits, embedded in the native field (negative values are
+// represented by their opposite modulo the field order). V must be in the
+// range [-2^(n-1), 2^(n-1)) where n = IntBits + FracBits.
+//
+// The results of the operations are range checked using [rangecheck.New]:
+// by default an overflow makes the circuit unsatisfiable, and with
+// [WithSaturation] the results are clamped to the range of the format. The
+// values given as witness are not checked, [API.AssertIsInRange] should be
+// called on them before using them.
+package fixedpoint
+
+import (
+	"fmt"
+	"math"
+	"math/big"
+
+	"github.com/consensys/gnark/frontend"
+	"github.com/consensys/gnark/std/math/integer"
+	"github.com/consensys/gnark/std/rangecheck"
+)
+
+// Format is a fixed-point format.
+type Format struct {
+	IntBits  int // number of integer bits, including the sign bit
+	FracBits int // number of fraction bits
+}
+
+// Fixed is a fixed-point value, see the package documentation for its
+// representation. It can be used in circuit definitions and assignments.
+type Fixed struct {
+	V frontend.Variable
+}
+
+// ValueOf returns the fixed-point value closest to x in the format, rounding
+// half away from zero, represented by a signed *big.Int. It is meant for
+// assignments; x must be in the range of the format and its precision is
+// limited to the 53 bits of a float64.
+func (f Format) ValueOf(x float64) Fixed {
+	scaled := math.Round(math.Ldexp(x, f.FracBits))
+	v, _ := new(big.Float).SetFloat64(scaled).Int(nil)
+	return Fixed{V: v}
+}
+
+func (f Format) nbBits() int {
+	return f.IntBits + f.FracBits
+}
+
+// Option configures the fixed-point arithmetic.
+type Option func(*API) error
+
+// WithSaturation clamps the results of the operations to the range of the
+// format instead of making the circuit unsatisfiable on overflow.
+func WithSaturation() Option {
+	return func(a *API) error {
+		a.saturate = true
+		return nil
+	}
+}
+
+// API implements the fixed-point operations for a format.
+type API struct {
+	api      frontend.API
+	format   Format
+	rc       frontend.Rangechecker
+	saturate bool
+
+	min, max *big.Int // bounds of V
+}
+
+// New returns the fixed-point arithmetic for the given format. It returns an
+// error if the format is too large for the field of the circuit: the quotient
+// computed by Div must fit in half of the field.
+func New(api frontend.API, format Format, opts ...Option) (*API, error) {
+	if format.IntBits < 1 || format.FracBits < 0 {
+		return nil, fmt.Errorf("invalid format %d.%d", format.IntBits, format.FracBits)
+	}
+	n := format.nbBits()
+	if 2*(n+format.FracBits+1)+3 > api.Compiler().FieldBitLen() {
+		return nil, fmt.Errorf("format %d.%d too large for the field", format.IntBits, format.FracBits)
+	}
+	a := &API{
+		api:    api,
+		format: format,
+		rc:     rangecheck.New(api),
+		min:    new(big.Int).Neg(new(big.Int).Lsh(big.NewInt(1), uint(n-1))),
+		max:    new(big.Int).Sub(new(big.Int).Lsh(big.NewInt(1), uint(n-1)), big.NewInt(1)),
+	}
+	for _, opt := range opts {
+		if err := opt(a); err != nil {
+			return nil, err
+		}
+	}
+	return a, nil
+}
+
+// Constant returns the constant fixed-point value closest to x, see
+// [Format.ValueOf].
+func (a *API) Constant(x float64) Fixed {
+	v := a.format.ValueOf(x).V.(*big.Int)
+	return Fixed{V: v.Mod(v, a.api.Compiler().Field())}
+}
+
+// FromInt returns the fixed-point value of the integer v. It doesn't check
+// that v is in the range of the format.
+func (a *API) FromInt(v frontend.Variable) Fixed {
+	return Fixed{V: a.api.Mul(v, new(big.Int).Lsh(big.NewInt(1), uint(a.format.FracBits)))}
+}
+
+// AssertIsInRange checks that the representation of x is in the range of the
+// format.
+func (a *API) AssertIsInRange(x Fixed) {
+	a.rangeCheck(a.api.Add(x.V, new(big.Int).Neg(a.min)), a.format.nbBits())
+}
+
+// Add returns x + y.
+func (a *API) Add(x, y Fixed) Fixed {
+	return a.fit(a.api.Add(x.V, y.V), a.format.nbBits()+1)
+}
+
+// Sub returns x - y.
+func (a *API) Sub(x, y Fixed) Fixed {
+	return a.fit(a.api.Sub(x.V, y.V), a.format.nbBits()+1)
+}
+
+// Neg returns -x. The opposite of the smallest value overflows.
+func (a *API) Neg(x Fixed) Fixed {
+	return a.fit(a.api.Neg(x.V), a.format.nbBits()+1)
+}
+
+// Mul returns x * y, rounded to the nearest value of the format, half away
+// from zero.
+func (a *API) Mul(x, y Fixed) Fixed {
+	n, f := a.format.nbBits(), a.format.FracBits
+	p := a.api.Mul(x.V, y.V)
+	if f == 0 {
+		return a.fit(p, 2*n)
+	}
+
+	// |p| <= 2^(2n-2)
+	s, abs := a.signAbs(p, 2*n)
+	half := new(big.Int).Lsh(big.NewInt(1), uint(f-1))
+	q := integer.ShiftRight(a.api, a.api.Add(abs, half), uint(f), 2*n-1)
+	return a.fit(a.api.Select(s, a.api.Neg(q), q), 2*n-f)
+}
+
+// Div returns x / y, rounded to the nearest value of the format, half away
+// from zero. Dividing by zero makes the circuit unsatisfiable.
+func (a *API) Div(x, y Fixed) Fixed {
+	n, f := a.format.nbBits(), a.format.FracBits
+
+	// q = (2 * |x| * 2^f + |y|) / (2 * |y|)
+	sx, absX := a.signAbs(x.V, n)
+	sy, absY := a.signAbs(y.V, n)
+	num := a.api.Add(a.api.Mul(absX, new(big.Int).Lsh(big.NewInt(1), uint(f+1))), absY)
+	q, _ := integer.DivMod(a.api, num, a.api.Mul(absY, 2), n+f+1)
+	return a.fit(a.api.Select(a.api.Xor(sx, sy), a.api.Neg(q), q), n+f+1)
+}
+
+// IsNegative returns 1 if x < 0 and 0 otherwise.
+func (a *API) IsNegative(x Fixed) frontend.Variable {
+	return a.isNegative(x.V, a.format.nbBits())
+}
+
+// IsLess returns 1 if x < y and 0 otherwise.
+func (a *API) IsLess(x, y Fixed) frontend.Variable {
+	return a.isNegative(a.api.Sub(x.V, y.V), a.format.nbBits()+1)
+}
+
+// IsLessOrEqual returns 1 if x <= y and 0 otherwise.
+func (a *API) IsLessOrEqual(x, y Fixed) frontend.Variable {
+	return a.api.Sub(1, a.IsLess(y, x))
+}
+
+// AssertIsLessOrEqual asserts that x <= y.
+func (a *API) AssertIsLessOrEqual(x, y Fixed) {
+	a.rangeCheck(a.api.Sub(y.V, x.V), a.format.nbBits())
+}
+
+// IsEqual returns 1 if x == y and 0 otherwise.
+func (a *API) IsEqual(x, y Fixed) frontend.Variable {
+	return a.api.IsZero(a.api.Sub(x.V, y.V))
+}
+
+// AssertIsEqual asserts that x == y.
+func (a *API) AssertIsEqual(x, y Fixed) {
+	a.api.AssertIsEqual(x.V, y.V)
+}
+
+// Select returns x if sel is 1 and y otherwise.
+func (a *API) Select(sel frontend.Variable, x, y Fixed) Fixed {
+	return Fixed{V: a.api.Select(sel, x.V, y.V)}
+}
+
+// Min returns the minimum of x and y.
+func (a *API) Min(x, y Fixed) Fixed {
+	return a.Select(a.IsLess(x, y), x, y)
+}
+
+// Max returns the maximum of x and y.
+func (a *API) Max(x, y Fixed) Fixed {
+	return a.Select(a.IsLess(x, y), y, x)
+}
+
+// Abs returns |x|. The absolute value of the smallest value overflows.
+func (a *API) Abs(x Fixed) Fixed {
+	_, abs := a.signAbs(x.V, a.format.nbBits())
+	return a.fit(abs, a.format.nbBits()+1)
+}
+
+// ReLU returns max(x, 0).
+func (a *API) ReLU(x Fixed) Fixed {
+	return Fixed{V: a.api.Select(a.IsNegative(x), 0, x.V)}
+}
+
+// fit returns the representation v, with -2^(nbBits-1) <= v < 2^(nbBits-1),
+// as a value of the format. It checks that v is in the range of the format or
+// clamps it with saturation.
+func (a *API) fit(v frontend.Variable, nbBits int) Fixed {
+	if !a.saturate {
+		a.rangeCheck(a.api.Add(v, new(big.Int).Neg(a.min)), a.format.nbBits())
+		return Fixed{V: v}
+	}
+	over := a.api.Sub(1, a.isNegative(a.api.Add(v, a.min), nbBits+1))
+	under := a.isNegative(a.api.Sub(v, a.min), nbBits+1)
+	min := new(big.Int).Mod(a.min, a.api.Compiler().Field())
+	return Fixed{V: a.api.Select(over, a.max, a.api.Select(under, min, v))}
+}
+
+// isNegative returns 1 if v < 0 and 0 otherwise, for
+// -2^(nbBits-1) <= v < 2^(nbBits-1).
+func (a *API) isNegative(v frontend.Variable, nbBits int) frontend.Variable {
+	offset := new(big.Int).Lsh(big.NewInt(1), uint(nbBits-1))
+	nonNegative := integer.ShiftRight(a.api, a.api.Add(v, offset), uint(nbBits-1), nbBits)
+	return a.api.Sub(1, nonNegative)
+}
+
+// signAbs returns the sign bit and the absolute value of v, for
+// -2^(nbBits-1) <= v < 2^(nbBits-1).
+func (a *API) signAbs(v frontend.Variable, nbBits int) (s, abs frontend.Variable) {
+	s = a.isNegative(v, nbBits)
+	return s, a.api.Select(s, a.api.Neg(v), v)
+}
+
+// rangeCheck checks that 0 <= v < 2^nbBits.
+func (a *API) rangeCheck(v frontend.Variable, nbBits int) {
+	if c, ok := a.api.Compiler().ConstantValue(v); ok {
+		if c.Sign() < 0 || c.BitLen() > nbBits {
+			panic("constant out of range")
+		}
+		return
+	}
+	a.rc.Check(v, nbBits)
+}
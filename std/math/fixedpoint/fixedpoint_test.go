@@ -0,0 +1,156 @@
+package fixedpoint
+
+import (
+	"fmt"
+	"math/big"
+	"testing"
+
+	"github.com/consensys/gnark-crypto/ecc"
+	"github.com/consensys/gnark/frontend"
+	"github.com/consensys/gnark/test"
+)
+
+var testFormat = Format{IntBits: 8, FracBits: 8}
+
+type fixedCircuit struct {
+	Saturate bool
+
+	X, Y                                      Fixed
+	Sum, Diff, Prod, Quo, Min, Max, Abs, ReLU Fixed
+	Less                                      frontend.Variable
+}
+
+func (c *fixedCircuit) Define(api frontend.API) error {
+	var opts []Option
+	if c.Saturate {
+		opts = append(opts, WithSaturation())
+	}
+	f, err := New(api, testFormat, opts...)
+	if err != nil {
+		return err
+	}
+	f.AssertIsInRange(c.X)
+	f.AssertIsInRange(c.Y)
+	f.AssertIsEqual(f.Add(c.X, c.Y), c.Sum)
+	f.AssertIsEqual(f.Sub(c.X, c.Y), c.Diff)
+	f.AssertIsEqual(f.Mul(c.X, c.Y), c.Prod)
+	f.AssertIsEqual(f.Div(c.X, c.Y), c.Quo)
+	f.AssertIsEqual(f.Min(c.X, c.Y), c.Min)
+	f.AssertIsEqual(f.Max(c.X, c.Y), c.Max)
+	f.AssertIsEqual(f.Abs(c.X), c.Abs)
+	f.AssertIsEqual(f.ReLU(c.X), c.ReLU)
+	api.AssertIsEqual(f.IsLess(c.X, c.Y), c.Less)
+	return nil
+}
+
+// reference implements the fixed-point operations on the integer
+// representations, reporting overflows or saturating.
+type reference struct {
+	format   Format
+	saturate bool
+	overflow bool
+}
+
+func (r *reference) fit(v *big.Int) *big.Int {
+	n := uint(r.format.nbBits())
+	min := new(big.Int).Neg(new(big.Int).Lsh(big.NewInt(1), n-1))
+	max := new(big.Int).Sub(new(big.Int).Lsh(big.NewInt(1), n-1), big.NewInt(1))
+	switch {
+	case v.Cmp(max) > 0:
+		r.overflow = true
+		if r.saturate {
+			return max
+		}
+	case v.Cmp(min) < 0:
+		r.overflow = true
+		if r.saturate {
+			return min
+		}
+	}
+	return v
+}
+
+// roundedQuo returns a / b rounded to the nearest integer, half away from zero.
+func roundedQuo(a, b *big.Int) *big.Int {
+	num := new(big.Int).Mul(new(big.Int).Abs(a), big.NewInt(2))
+	num.Add(num, new(big.Int).Abs(b))
+	q := num.Quo(num, new(big.Int).Mul(new(big.Int).Abs(b), big.NewInt(2)))
+	if a.Sign()*b.Sign() < 0 {
+		q.Neg(q)
+	}
+	return q
+}
+
+func (r *reference) assignment(x, y float64) *fixedCircuit {
+	vx, vy := r.format.ValueOf(x).V.(*big.Int), r.format.ValueOf(y).V.(*big.Int)
+	one := new(big.Int).Lsh(big.NewInt(1), uint(r.format.FracBits))
+	// the test engine expects the assignments reduced modulo the field
+	fixed := func(v *big.Int) Fixed { return Fixed{V: new(big.Int).Mod(v, ecc.BN254.ScalarField())} }
+
+	c := &fixedCircuit{
+		X:    fixed(vx),
+		Y:    fixed(vy),
+		Sum:  fixed(r.fit(new(big.Int).Add(vx, vy))),
+		Diff: fixed(r.fit(new(big.Int).Sub(vx, vy))),
+		Prod: fixed(r.fit(roundedQuo(new(big.Int).Mul(vx, vy), one))),
+		Quo:  fixed(r.fit(roundedQuo(new(big.Int).Mul(vx, one), vy))),
+		Min:  fixed(vx),
+		Max:  fixed(vy),
+		Abs:  fixed(r.fit(new(big.Int).Abs(vx))),
+		ReLU: fixed(vx),
+		Less: 1,
+	}
+	if vx.Cmp(vy) >= 0 {
+		c.Min, c.Max, c.Less = fixed(vy), fixed(vx), 0
+	}
+	if vx.Sign() < 0 {
+		c.ReLU = fixed(new(big.Int))
+	}
+	return c
+}
+
+func TestFixed(t *testing.T) {
+	assert := test.NewAssert(t)
+	for _, saturate := range []bool{false, true} {
+		for _, xy := range [][2]float64{
+			{1.5, 2.25}, {-3.75, 0.5}, {0.00390625, 0.5}, {-1.5, -0.00390625},
+			{3, -7}, {-0.5, 0.75}, {100, 100}, {-128, -1}, {127.99609375, -128},
+		} {
+			r := &reference{format: testFormat, saturate: saturate}
+			valid := r.assignment(xy[0], xy[1])
+			if r.overflow && !saturate {
+				continue
+			}
+			invalid := r.assignment(xy[0], xy[1])
+			invalid.Prod.V = new(big.Int).Add(invalid.Prod.V.(*big.Int), big.NewInt(1))
+			invalid.Prod.V.(*big.Int).Mod(invalid.Prod.V.(*big.Int), ecc.BN254.ScalarField())
+			assert.Run(func(assert *test.Assert) {
+				assert.CheckCircuit(&fixedCircuit{Saturate: saturate},
+					test.WithValidAssignment(valid), test.WithInvalidAssignment(invalid), test.WithCurves(ecc.BN254))
+			}, fmt.Sprintf("saturate=%t/x=%g/y=%g", saturate, xy[0], xy[1]))
+		}
+	}
+}
+
+type overflowCircuit struct {
+	X, Y Fixed
+}
+
+func (c *overflowCircuit) Define(api frontend.API) error {
+	f, err := New(api, testFormat)
+	if err != nil {
+		return err
+	}
+	f.Mul(c.X, c.Y)
+	f.Div(c.X, c.Y)
+	return nil
+}
+
+func TestOverflow(t *testing.T) {
+	assert := test.NewAssert(t)
+	assert.CheckCircuit(&overflowCircuit{},
+		test.WithValidAssignment(&overflowCircuit{X: testFormat.ValueOf(11), Y: testFormat.ValueOf(-11)}),
+		test.WithInvalidAssignment(&overflowCircuit{X: testFormat.ValueOf(12), Y: testFormat.ValueOf(-12)}),
+		test.WithInvalidAssignment(&overflowCircuit{X: testFormat.ValueOf(1), Y: testFormat.ValueOf(0)}),
+		test.WithCurves(ecc.BN254))
+}
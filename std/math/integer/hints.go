@@ -0,0 +1,53 @@
+package integer
+
+import (
+	"errors"
+	"fmt"
+	"math/big"
+
+	"github.com/consensys/gnark/constraint/solver"
+)
+
+func init() {
+	solver.RegisterHint(GetHints()...)
+}
+
+// GetHints returns all hint functions used in this package. This method is
+// useful for registering all hints in the solver.
+func GetHints() []solver.Hint {
+	return []solver.Hint{divModHint, partitionHint}
+}
+
+// divModHint computes the quotient and the remainder of the euclidean division
+// of inputs[0] by inputs[1].
+func divModHint(_ *big.Int, inputs []*big.Int, outputs []*big.Int) error {
+	if len(inputs) != 2 {
+		return fmt.Errorf("expecting two inputs")
+	}
+	if len(outputs) != 2 {
+		return fmt.Errorf("expecting two outputs")
+	}
+	if inputs[1].Sign() == 0 {
+		return errors.New("division by zero")
+	}
+	outputs[0].QuoRem(inputs[0], inputs[1], outputs[1])
+	return nil
+}
+
+// partitionHint splits inputs[1] at the bit inputs[0] into its upper and
+// lower parts.
+func partitionHint(_ *big.Int, inputs []*big.Int, outputs []*big.Int) error {
+	if len(inputs) != 2 {
+		return fmt.Errorf("expecting two inputs")
+	}
+	if len(outputs) != 2 {
+		return fmt.Errorf("expecting two outputs")
+	}
+	if !inputs[0].IsUint64() {
+		return fmt.Errorf("split location must be int")
+	}
+	split := uint(inputs[0].Uint64())
+	outputs[0].Rsh(inputs[1], split)
+	outputs[1].Sub(inputs[1], new(big.Int).Lsh(outputs[0], split))
+	return nil
+}
@@ -0,0 +1,229 @@
+// Package integer implements the division, modulo, bit shifts and truncation
+// of bounded integers.
+//
+// Every operation is given the bound n of its operands: they are either
+// unsigned integers less than 2^n or, with the [WithSigned] option, n-bit
+// two's complement signed integers, where a value v in [2^(n-1), 2^n)
+// represents v - 2^n. The results are computed by hints and constrained using
+// range checks from [rangecheck.New].
+//
+// The linear relations between the operands and the results are free in
+// R1CS, but not in PLONK. When the builder implements [frontend.PlonkAPI],
+// they are enforced with a single PLONK constraint each.
+package integer
+
+import (
+	"fmt"
+	"math/big"
+	"math/bits"
+
+	"github.com/consensys/gnark/frontend"
+	"github.com/consensys/gnark/std/rangecheck"
+)
+
+// DivMod returns the quotient q and the remainder r of the division of a by b.
+//
+// For unsigned operands, it is the euclidean division: a = q*b + r with
+// 0 <= r < b. For signed operands, the quotient is truncated towards zero and
+// the remainder has the sign of a, as the Go operators / and %; the quotient
+// of the smallest value by -1 overflows to the smallest value.
+//
+// a and b must be less than 2^bound, and 2*bound+3 must not exceed the bit
+// length of the field. Dividing by zero makes the circuit unsatisfiable.
+func DivMod(api frontend.API, a, b frontend.Variable, bound int, opts ...Option) (q, r frontend.Variable) {
+	cfg, err := parseOpts(opts...)
+	if err != nil {
+		panic(err)
+	}
+	if 2*bound+3 > api.Compiler().FieldBitLen() {
+		panic(fmt.Sprintf("bound %d too large for the field", bound))
+	}
+	if !cfg.signed {
+		return divMod(api, a, b, bound)
+	}
+
+	// we divide the absolute values and restore the signs
+	sa, absA := signAbs(api, a, bound)
+	sb, absB := signAbs(api, b, bound)
+	q, r = divMod(api, absA, absB, bound)
+	q = api.Select(api.Xor(sa, sb), neg(api, q, bound), q)
+	r = api.Select(sa, neg(api, r, bound), r)
+	return q, r
+}
+
+// Mod returns the remainder of the division of a by b, see [DivMod].
+func Mod(api frontend.API, a, b frontend.Variable, bound int, opts ...Option) frontend.Variable {
+	_, r := DivMod(api, a, b, bound, opts...)
+	return r
+}
+
+// ShiftRight returns a shifted right by shift bits. The shift is logical for
+// unsigned operands and arithmetic for signed operands, i.e. the quotient of
+// a by 2^shift rounded towards negative infinity.
+//
+// a must be less than 2^bound, which is enforced, and bound must be less than
+// the bit length of the field.
+func ShiftRight(api frontend.API, a frontend.Variable, shift uint, bound int, opts ...Option) frontend.Variable {
+	cfg, err := parseOpts(opts...)
+	if err != nil {
+		panic(err)
+	}
+	checkShiftBound(api, bound)
+	if !cfg.signed {
+		if shift >= uint(bound) {
+			// a still has to be in range
+			partition(api, a, uint(bound), bound)
+			return 0
+		}
+		_, upper := partition(api, a, shift, bound)
+		return upper
+	}
+
+	// a = s * 2^(bound-1) + upper * 2^shift + lower, and the sign s is
+	// replicated in the upper bits of the result.
+	if shift > uint(bound-1) {
+		shift = uint(bound - 1)
+	}
+	_, upper := partition(api, a, shift, bound)
+	upper, s := partition(api, upper, uint(bound-1)-shift, bound-int(shift))
+	ext := new(big.Int).Lsh(big.NewInt(1), uint(bound))
+	ext.Sub(ext, new(big.Int).Lsh(big.NewInt(1), uint(bound-1)-shift))
+	return api.Add(upper, api.Mul(s, ext))
+}
+
+// ShiftLeft returns a shifted left by shift bits, modulo 2^bound. It is the
+// same operation for unsigned and two's complement signed operands.
+//
+// a must be less than 2^bound, which is enforced, and bound must be less than
+// the bit length of the field.
+func ShiftLeft(api frontend.API, a frontend.Variable, shift uint, bound int) frontend.Variable {
+	checkShiftBound(api, bound)
+	if shift >= uint(bound) {
+		partition(api, a, uint(bound), bound)
+		return 0
+	}
+	lower, _ := partition(api, a, uint(bound)-shift, bound)
+	return api.Mul(lower, new(big.Int).Lsh(big.NewInt(1), shift))
+}
+
+// Truncate returns the nbBits least significant bits of a, i.e. a modulo
+// 2^nbBits. For two's complement signed operands, it converts a to a signed
+// integer of nbBits bits, wrapping around on overflow.
+//
+// a must be less than 2^bound, which is enforced, and bound must be less than
+// the bit length of the field.
+func Truncate(api frontend.API, a frontend.Variable, nbBits uint, bound int) frontend.Variable {
+	checkShiftBound(api, bound)
+	if nbBits >= uint(bound) {
+		partition(api, a, uint(bound), bound)
+		return a
+	}
+	lower, _ := partition(api, a, nbBits, bound)
+	return lower
+}
+
+func checkShiftBound(api frontend.API, bound int) {
+	if bound < 1 || bound >= api.Compiler().FieldBitLen() {
+		panic(fmt.Sprintf("bound %d out of range", bound))
+	}
+}
+
+// divMod returns the quotient and remainder of the euclidean division of a by b.
+func divMod(api frontend.API, a, b frontend.Variable, bound int) (q, r frontend.Variable) {
+	ac, aConstant := api.Compiler().ConstantValue(a)
+	bc, bConstant := api.Compiler().ConstantValue(b)
+	if bConstant && bc.Sign() == 0 {
+		panic("division by zero")
+	}
+	if aConstant && bConstant {
+		qc, rc := new(big.Int), new(big.Int)
+		qc.QuoRem(ac, bc, rc)
+		return qc, rc
+	}
+
+	res, err := api.Compiler().NewHint(divModHint, 2, a, b)
+	if err != nil {
+		panic(err)
+	}
+	q, r = res[0], res[1]
+
+	// q < 2^bound and 0 <= r < b
+	rBound := bound
+	if bConstant {
+		rBound = new(big.Int).Sub(bc, big.NewInt(1)).BitLen()
+	}
+	rc := rangecheck.New(api)
+	rangeCheck(api, rc, q, bound)
+	rangeCheck(api, rc, r, rBound)
+	rangeCheck(api, rc, api.Sub(b, r, 1), rBound)
+
+	// a = q * b + r
+	if papi, ok := api.(frontend.PlonkAPI); ok && !bConstant {
+		qb := papi.EvaluatePlonkExpression(q, b, 0, 0, 1, 0)
+		papi.AddPlonkConstraint(qb, r, a, 1, 1, -1, 0, 0)
+	} else {
+		api.AssertIsEqual(api.Add(api.Mul(q, b), r), a)
+	}
+	return q, r
+}
+
+// partition returns lower and upper such that a = upper * 2^split + lower,
+// with lower < 2^split and upper < 2^(bound-split).
+func partition(api frontend.API, a frontend.Variable, split uint, bound int) (lower, upper frontend.Variable) {
+	if ac, ok := api.Compiler().ConstantValue(a); ok {
+		if ac.BitLen() > bound {
+			panic("constant larger than bound")
+		}
+		lc, uc := new(big.Int), new(big.Int)
+		uc.Rsh(ac, split)
+		lc.Sub(ac, new(big.Int).Lsh(uc, split))
+		return lc, uc
+	}
+
+	rc := rangecheck.New(api)
+	if split >= uint(bound) {
+		rangeCheck(api, rc, a, bound)
+		return a, 0
+	}
+	if split == 0 {
+		rangeCheck(api, rc, a, bound)
+		return 0, a
+	}
+
+	res, err := api.Compiler().NewHint(partitionHint, 2, split, a)
+	if err != nil {
+		panic(err)
+	}
+	upper, lower = res[0], res[1]
+	rangeCheck(api, rc, lower, int(split))
+	rangeCheck(api, rc, upper, bound-int(split))
+
+	// a = upper * 2^split + lower
+	if papi, ok := api.(frontend.PlonkAPI); ok && split < bits.UintSize-1 {
+		papi.AddPlonkConstraint(upper, lower, a, 1<<split, 1, -1, 0, 0)
+	} else {
+		api.AssertIsEqual(api.Add(api.Mul(upper, new(big.Int).Lsh(big.NewInt(1), split)), lower), a)
+	}
+	return lower, upper
+}
+
+// signAbs returns the sign bit and the absolute value of the signed integer a.
+func signAbs(api frontend.API, a frontend.Variable, bound int) (s, abs frontend.Variable) {
+	_, s = partition(api, a, uint(bound-1), bound)
+	return s, api.Select(s, api.Sub(new(big.Int).Lsh(big.NewInt(1), uint(bound)), a), a)
+}
+
+// neg returns -a modulo 2^bound, for 0 <= a <= 2^bound.
+func neg(api frontend.API, a frontend.Variable, bound int) frontend.Variable {
+	m := new(big.Int).Lsh(big.NewInt(1), uint(bound))
+	return api.Select(api.IsZero(a), 0, api.Sub(m, a))
+}
+
+// rangeCheck checks that v < 2^nbBits.
+func rangeCheck(api frontend.API, rc frontend.Rangechecker, v frontend.Variable, nbBits int) {
+	if nbBits == 0 {
+		api.AssertIsEqual(v, 0)
+		return
+	}
+	rc.Check(v, nbBits)
+}
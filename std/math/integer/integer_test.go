@@ -0,0 +1,111 @@
+package integer
+
+import (
+	"fmt"
+	"math/big"
+	"testing"
+
+	"github.com/consensys/gnark-crypto/ecc"
+	"github.com/consensys/gnark/frontend"
+	"github.com/consensys/gnark/test"
+)
+
+const testBound = 16
+
+type integerCircuit struct {
+	Signed bool
+	Shift  uint
+
+	A, B                                 frontend.Variable
+	Q, R, ShiftedRight, ShiftedLeft, Low frontend.Variable
+}
+
+func (c *integerCircuit) Define(api frontend.API) error {
+	var opts []Option
+	if c.Signed {
+		opts = append(opts, WithSigned())
+	}
+	q, r := DivMod(api, c.A, c.B, testBound, opts...)
+	api.AssertIsEqual(q, c.Q)
+	api.AssertIsEqual(r, c.R)
+	api.AssertIsEqual(Mod(api, c.A, c.B, testBound, opts...), c.R)
+	api.AssertIsEqual(ShiftRight(api, c.A, c.Shift, testBound, opts...), c.ShiftedRight)
+	api.AssertIsEqual(ShiftLeft(api, c.A, c.Shift, testBound), c.ShiftedLeft)
+	api.AssertIsEqual(Truncate(api, c.A, c.Shift, testBound), c.Low)
+	return nil
+}
+
+// assignment returns the assignment for a and b, computed with the Go
+// operators on int16 for signed values and uint16 for unsigned ones.
+func assignment(signed bool, shift uint, a, b int) *integerCircuit {
+	if signed {
+		sa, sb := int16(a), int16(b)
+		return &integerCircuit{
+			A: uint16(sa), B: uint16(sb),
+			Q: uint16(sa / sb), R: uint16(sa % sb),
+			ShiftedRight: uint16(sa >> shift), ShiftedLeft: uint16(sa << shift),
+			Low: uint16(sa) & (1<<shift - 1),
+		}
+	}
+	ua, ub := uint16(a), uint16(b)
+	return &integerCircuit{
+		A: ua, B: ub,
+		Q: ua / ub, R: ua % ub,
+		ShiftedRight: ua >> shift, ShiftedLeft: ua << shift,
+		Low: ua & (1<<shift - 1),
+	}
+}
+
+func TestInteger(t *testing.T) {
+	assert := test.NewAssert(t)
+	for _, signed := range []bool{false, true} {
+		for _, shift := range []uint{0, 3, 15} {
+			for _, ab := range [][2]int{{1000, 7}, {65535, 1}, {7, 1000}, {-1000, 7}, {1000, -7}, {-1000, -7}, {-32768, -1}, {0, 3}} {
+				valid := assignment(signed, shift, ab[0], ab[1])
+				invalid := assignment(signed, shift, ab[0], ab[1])
+				invalid.R = 1 + invalid.R.(uint16)
+				assert.Run(func(assert *test.Assert) {
+					assert.CheckCircuit(&integerCircuit{Signed: signed, Shift: shift},
+						test.WithValidAssignment(valid), test.WithInvalidAssignment(invalid), test.WithCurves(ecc.BN254))
+				}, fmt.Sprintf("signed=%t/shift=%d/a=%d/b=%d", signed, shift, ab[0], ab[1]))
+			}
+		}
+	}
+}
+
+func TestDivModZero(t *testing.T) {
+	assert := test.NewAssert(t)
+	assert.CheckCircuit(&integerCircuit{Shift: 1},
+		test.WithInvalidAssignment(&integerCircuit{A: 5, B: 0, Q: 0, R: 0, ShiftedRight: 2, ShiftedLeft: 10, Low: 1}),
+		test.WithCurves(ecc.BN254))
+}
+
+type outOfRangeCircuit struct {
+	A frontend.Variable
+}
+
+func (c *outOfRangeCircuit) Define(api frontend.API) error {
+	ShiftRight(api, c.A, 4, testBound)
+	return nil
+}
+
+func TestShiftOutOfRange(t *testing.T) {
+	assert := test.NewAssert(t)
+	assert.CheckCircuit(&outOfRangeCircuit{},
+		test.WithValidAssignment(&outOfRangeCircuit{A: 1<<testBound - 1}),
+		test.WithInvalidAssignment(&outOfRangeCircuit{A: 1 << testBound}),
+		test.WithCurves(ecc.BN254))
+}
+
+func TestHints(t *testing.T) {
+	q, r := new(big.Int), new(big.Int)
+	if err := divModHint(nil, []*big.Int{big.NewInt(17), big.NewInt(5)}, []*big.Int{q, r}); err != nil {
+		t.Fatal(err)
+	}
+	if q.Int64() != 3 || r.Int64() != 2 {
+		t.Fatalf("unexpected division result %s %s", q, r)
+	}
+	if err := divModHint(nil, []*big.Int{big.NewInt(17), big.NewInt(0)}, []*big.Int{q, r}); err == nil {
+		t.Fatal("expected division by zero error")
+	}
+}
@@ -0,0 +1,27 @@
+package integer
+
+type config struct {
+	signed bool
+}
+
+func parseOpts(opts ...Option) (*config, error) {
+	cfg := new(config)
+	for _, apply := range opts {
+		if err := apply(cfg); err != nil {
+			return nil, err
+		}
+	}
+	return cfg, nil
+}
+
+// Option configures the operations of the package.
+type Option func(*config) error
+
+// WithSigned interprets the values as two's complement signed integers: a value
+// v with bound n represents v if v < 2^(n-1) and v - 2^n otherwise.
+func WithSigned() Option {
+	return func(c *config) error {
+		c.signed = true
+		return nil
+	}
+}
@@ -24,6 +24,8 @@ package uints
 
 import (
 	"fmt"
+	"math/big"
+	"math/bits"
 
 	"github.com/consensys/gnark/frontend"
 	"github.com/consensys/gnark/std/internal/logderivprecomp"
@@ -260,8 +262,19 @@ func (bf *BinaryField[T]) Add(a ...T) T {
 		va[i] = bf.ToValue(a[i])
 	}
 	vres := bf.api.Add(va[0], va[1], va[2:]...)
-	res := bf.ValueOf(vres)
-	// TODO: should also check the that carry we omitted is correct.
+	var res T
+	// the sum is decomposed into the result bytes and the carry, which is less
+	// than the number of operands.
+	bts, err := bf.api.Compiler().NewHint(toBytes, len(res)+1, len(res)+1, vres)
+	if err != nil {
+		panic(err)
+	}
+	for i := 0; i < len(res); i++ {
+		res[i] = bf.ByteValueOf(bts[i])
+	}
+	carry := bts[len(res)]
+	bf.rchecker.Check(carry, bits.Len(uint(len(a)-1)))
+	bf.api.AssertIsEqual(bf.api.Add(bf.ToValue(res), bf.api.Mul(carry, new(big.Int).Lsh(big.NewInt(1), uint(8*len(res))))), vres)
 	return res
 }
 
@@ -1,11 +1,14 @@
 package uints
 
 import (
+	"math/big"
 	"math/bits"
 	"testing"
 
 	"github.com/consensys/gnark-crypto/ecc"
+	"github.com/consensys/gnark/constraint/solver"
 	"github.com/consensys/gnark/frontend"
+	"github.com/consensys/gnark/frontend/cs/scs"
 	"github.com/consensys/gnark/test"
 )
 
@@ -79,3 +82,52 @@ func TestRshift(t *testing.T) {
 	err = test.IsSolved(&rshiftCircuit{Shift: 11}, &rshiftCircuit{Shift: 11, In: NewU32(0x12345678), Expected: NewU32(0x12345678 >> 11)}, ecc.BN254.ScalarField())
 	assert.NoError(err)
 }
+
+type addCircuit struct {
+	In       [4]U32
+	Expected U32
+}
+
+func (c *addCircuit) Define(api frontend.API) error {
+	uapi, err := New[U32](api)
+	if err != nil {
+		return err
+	}
+	res := uapi.Add(c.In[:]...)
+	uapi.AssertEq(c.Expected, res)
+	return nil
+}
+
+func TestAdd(t *testing.T) {
+	assert := test.NewAssert(t)
+	in := [4]uint32{0xffffffff, 0xfffffffe, 0x80000000, 0x12345678}
+	var witness addCircuit
+	var sum uint32
+	for i := range in {
+		witness.In[i] = NewU32(in[i])
+		sum += in[i]
+	}
+	witness.Expected = NewU32(sum)
+	err := test.IsSolved(&addCircuit{}, &witness, ecc.BN254.ScalarField())
+	assert.NoError(err)
+
+	// the carry of the sum, which overflows, is constrained
+	ccs, err := frontend.Compile(ecc.BN254.ScalarField(), scs.NewBuilder, &addCircuit{})
+	assert.NoError(err)
+	w, err := frontend.NewWitness(&witness, ecc.BN254.ScalarField())
+	assert.NoError(err)
+	_, err = ccs.Solve(w)
+	assert.NoError(err)
+	for _, delta := range []int64{1, -1} {
+		wrongCarry := func(m *big.Int, inputs, outputs []*big.Int) error {
+			if err := toBytes(m, inputs, outputs); err != nil {
+				return err
+			}
+			carry := outputs[len(outputs)-1]
+			carry.Add(carry, big.NewInt(delta)).Mod(carry, m)
+			return nil
+		}
+		_, err = ccs.Solve(w, solver.OverrideHint(solver.GetHintID(toBytes), wrongCarry))
+		assert.Error(err)
+	}
+}
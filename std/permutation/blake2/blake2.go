@@ -0,0 +1,91 @@
+// Package blake2 implements the BLAKE2b and BLAKE2s compression functions.
+//
+// This package exposes only the compression function F as defined in [RFC
+// 7693], with a configurable number of rounds as required by the BLAKE2F
+// precompile of [EIP-152]. For the BLAKE2 hash functions see
+// [github.com/consensys/gnark/std/hash/blake2].
+//
+// [RFC 7693]: https://www.rfc-editor.org/rfc/rfc7693
+// [EIP-152]: https://eips.ethereum.org/EIPS/eip-152
+package blake2
+
+import (
+	"github.com/consensys/gnark/std/math/uints"
+)
+
+// Blake2bIV is the initialisation vector of BLAKE2b.
+var Blake2bIV = [8]uint64{
+	0x6a09e667f3bcc908, 0xbb67ae8584caa73b, 0x3c6ef372fe94f82b, 0xa54ff53a5f1d36f1,
+	0x510e527fade682d1, 0x9b05688c2b3e6c1f, 0x1f83d9abfb41bd6b, 0x5be0cd19137e2179,
+}
+
+// Blake2sIV is the initialisation vector of BLAKE2s.
+var Blake2sIV = [8]uint32{
+	0x6a09e667, 0xbb67ae85, 0x3c6ef372, 0xa54ff53a, 0x510e527f, 0x9b05688c, 0x1f83d9ab, 0x5be0cd19,
+}
+
+var sigma = [10][16]int{
+	{0, 1, 2, 3, 4, 5, 6, 7, 8, 9, 10, 11, 12, 13, 14, 15},
+	{14, 10, 4, 8, 9, 15, 13, 6, 1, 12, 0, 2, 11, 7, 5, 3},
+	{11, 8, 12, 0, 5, 2, 15, 13, 10, 14, 3, 6, 7, 1, 9, 4},
+	{7, 9, 3, 1, 13, 12, 11, 14, 2, 6, 5, 10, 4, 0, 15, 8},
+	{9, 0, 5, 7, 2, 4, 10, 15, 14, 1, 11, 12, 6, 8, 3, 13},
+	{2, 12, 6, 10, 0, 11, 8, 3, 4, 13, 7, 5, 15, 14, 1, 9},
+	{12, 5, 1, 15, 14, 13, 4, 10, 0, 7, 6, 3, 9, 2, 8, 11},
+	{13, 11, 7, 14, 12, 1, 3, 9, 5, 0, 15, 4, 8, 6, 2, 10},
+	{6, 15, 14, 9, 11, 3, 0, 8, 12, 2, 13, 7, 1, 4, 10, 5},
+	{10, 2, 8, 4, 7, 6, 1, 5, 15, 11, 9, 14, 3, 12, 13, 0},
+}
+
+// Blake2b applies rounds rounds of the BLAKE2b compression function to the
+// state h with the message block m and the byte offset t. The final block
+// flag f is xored into the working vector, and is all ones for the last block
+// and zero otherwise. BLAKE2b uses 12 rounds.
+func Blake2b(uapi *uints.BinaryField[uints.U64], rounds int, h [8]uints.U64, m [16]uints.U64, t [2]uints.U64, f uints.U64) [8]uints.U64 {
+	return compress(uapi, rounds, [4]int{32, 24, 16, 63}, uints.NewU64Array(Blake2bIV[:]), h, m, t, f)
+}
+
+// Blake2s applies rounds rounds of the BLAKE2s compression function to the
+// state h with the message block m and the byte offset t. The final block
+// flag f is xored into the working vector, and is all ones for the last block
+// and zero otherwise. BLAKE2s uses 10 rounds.
+func Blake2s(uapi *uints.BinaryField[uints.U32], rounds int, h [8]uints.U32, m [16]uints.U32, t [2]uints.U32, f uints.U32) [8]uints.U32 {
+	return compress(uapi, rounds, [4]int{16, 12, 8, 7}, uints.NewU32Array(Blake2sIV[:]), h, m, t, f)
+}
+
+func compress[T uints.Long](uapi *uints.BinaryField[T], rounds int, rot [4]int, iv []T, h [8]T, m [16]T, t [2]T, f T) [8]T {
+	var v [16]T
+	copy(v[:8], h[:])
+	copy(v[8:], iv)
+	v[12] = uapi.Xor(v[12], t[0])
+	v[13] = uapi.Xor(v[13], t[1])
+	v[14] = uapi.Xor(v[14], f)
+
+	g := func(a, b, c, d int, x, y T) {
+		v[a] = uapi.Add(v[a], v[b], x)
+		v[d] = uapi.Lrot(uapi.Xor(v[d], v[a]), -rot[0])
+		v[c] = uapi.Add(v[c], v[d])
+		v[b] = uapi.Lrot(uapi.Xor(v[b], v[c]), -rot[1])
+		v[a] = uapi.Add(v[a], v[b], y)
+		v[d] = uapi.Lrot(uapi.Xor(v[d], v[a]), -rot[2])
+		v[c] = uapi.Add(v[c], v[d])
+		v[b] = uapi.Lrot(uapi.Xor(v[b], v[c]), -rot[3])
+	}
+	for i := 0; i < rounds; i++ {
+		s := &sigma[i%10]
+		g(0, 4, 8, 12, m[s[0]], m[s[1]])
+		g(1, 5, 9, 13, m[s[2]], m[s[3]])
+		g(2, 6, 10, 14, m[s[4]], m[s[5]])
+		g(3, 7, 11, 15, m[s[6]], m[s[7]])
+		g(0, 5, 10, 15, m[s[8]], m[s[9]])
+		g(1, 6, 11, 12, m[s[10]], m[s[11]])
+		g(2, 7, 8, 13, m[s[12]], m[s[13]])
+		g(3, 4, 9, 14, m[s[14]], m[s[15]])
+	}
+
+	var ret [8]T
+	for i := range ret {
+		ret[i] = uapi.Xor(h[i], v[i], v[i+8])
+	}
+	return ret
+}
@@ -0,0 +1,113 @@
+package blake2_test
+
+import (
+	"fmt"
+	"math/bits"
+	"math/rand"
+	"testing"
+	"time"
+
+	"github.com/consensys/gnark-crypto/ecc"
+	"github.com/consensys/gnark/frontend"
+	"github.com/consensys/gnark/std/math/uints"
+	"github.com/consensys/gnark/std/permutation/blake2"
+	"github.com/consensys/gnark/test"
+)
+
+var sigma = [10][16]int{
+	{0, 1, 2, 3, 4, 5, 6, 7, 8, 9, 10, 11, 12, 13, 14, 15},
+	{14, 10, 4, 8, 9, 15, 13, 6, 1, 12, 0, 2, 11, 7, 5, 3},
+	{11, 8, 12, 0, 5, 2, 15, 13, 10, 14, 3, 6, 7, 1, 9, 4},
+	{7, 9, 3, 1, 13, 12, 11, 14, 2, 6, 5, 10, 4, 0, 15, 8},
+	{9, 0, 5, 7, 2, 4, 10, 15, 14, 1, 11, 12, 6, 8, 3, 13},
+	{2, 12, 6, 10, 0, 11, 8, 3, 4, 13, 7, 5, 15, 14, 1, 9},
+	{12, 5, 1, 15, 14, 13, 4, 10, 0, 7, 6, 3, 9, 2, 8, 11},
+	{13, 11, 7, 14, 12, 1, 3, 9, 5, 0, 15, 4, 8, 6, 2, 10},
+	{6, 15, 14, 9, 11, 3, 0, 8, 12, 2, 13, 7, 1, 4, 10, 5},
+	{10, 2, 8, 4, 7, 6, 1, 5, 15, 11, 9, 14, 3, 12, 13, 0},
+}
+
+func compressGeneric(rounds int, h *[8]uint64, m [16]uint64, t [2]uint64, f uint64) {
+	var v [16]uint64
+	copy(v[:8], h[:])
+	copy(v[8:], blake2.Blake2bIV[:])
+	v[12] ^= t[0]
+	v[13] ^= t[1]
+	v[14] ^= f
+	g := func(a, b, c, d int, x, y uint64) {
+		v[a] += v[b] + x
+		v[d] = bits.RotateLeft64(v[d]^v[a], -32)
+		v[c] += v[d]
+		v[b] = bits.RotateLeft64(v[b]^v[c], -24)
+		v[a] += v[b] + y
+		v[d] = bits.RotateLeft64(v[d]^v[a], -16)
+		v[c] += v[d]
+		v[b] = bits.RotateLeft64(v[b]^v[c], -63)
+	}
+	for i := 0; i < rounds; i++ {
+		s := &sigma[i%10]
+		g(0, 4, 8, 12, m[s[0]], m[s[1]])
+		g(1, 5, 9, 13, m[s[2]], m[s[3]])
+		g(2, 6, 10, 14, m[s[4]], m[s[5]])
+		g(3, 7, 11, 15, m[s[6]], m[s[7]])
+		g(0, 5, 10, 15, m[s[8]], m[s[9]])
+		g(1, 6, 11, 12, m[s[10]], m[s[11]])
+		g(2, 7, 8, 13, m[s[12]], m[s[13]])
+		g(3, 4, 9, 14, m[s[14]], m[s[15]])
+	}
+	for i := range h {
+		h[i] ^= v[i] ^ v[i+8]
+	}
+}
+
+type blake2bCircuit struct {
+	rounds   int
+	H        [8]uints.U64
+	M        [16]uints.U64
+	T        [2]uints.U64
+	F        uints.U64
+	Expected [8]uints.U64
+}
+
+func (c *blake2bCircuit) Define(api frontend.API) error {
+	uapi, err := uints.New[uints.U64](api)
+	if err != nil {
+		return err
+	}
+	res := blake2.Blake2b(uapi, c.rounds, c.H, c.M, c.T, c.F)
+	for i := range res {
+		uapi.AssertEq(res[i], c.Expected[i])
+	}
+	return nil
+}
+
+func TestBlake2b(t *testing.T) {
+	assert := test.NewAssert(t)
+	rng := rand.New(rand.NewSource(time.Now().Unix())) //nolint G404, test code
+	for _, rounds := range []int{0, 1, 12} {
+		for _, f := range []uint64{0, ^uint64(0)} {
+			var h [8]uint64
+			var m [16]uint64
+			for i := range h {
+				h[i] = rng.Uint64()
+			}
+			for i := range m {
+				m[i] = rng.Uint64()
+			}
+			tt := [2]uint64{rng.Uint64(), rng.Uint64()}
+			witness := blake2bCircuit{T: [2]uints.U64{uints.NewU64(tt[0]), uints.NewU64(tt[1])}, F: uints.NewU64(f)}
+			for i := range h {
+				witness.H[i] = uints.NewU64(h[i])
+			}
+			for i := range m {
+				witness.M[i] = uints.NewU64(m[i])
+			}
+			compressGeneric(rounds, &h, m, tt, f)
+			for i := range h {
+				witness.Expected[i] = uints.NewU64(h[i])
+			}
+			err := test.IsSolved(&blake2bCircuit{rounds: rounds}, &witness, ecc.BN254.ScalarField())
+			assert.NoError(err, fmt.Sprintf("rounds %d, f %x", rounds, f))
+		}
+	}
+}
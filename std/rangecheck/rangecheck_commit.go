@@ -21,11 +21,59 @@ func init() {
 type checkedVariable struct {
 	v    frontend.Variable
 	bits int
+	// merge is set for the checks of a parallel task deferred with [Defer]
+	merge func(remap func(frontend.Variable) frontend.Variable)
 }
 
 type commitChecker struct {
+	api       frontend.API
 	collected []checkedVariable
 	closed    bool
+	forked    bool
+}
+
+// ReplayableState implements [frontend.ReplayableState], the checked
+// variables are collected through [frontend.UpdateState].
+func (c *commitChecker) ReplayableState() {}
+
+// Fork implements [frontend.MergeableState].
+func (c *commitChecker) Fork(api frontend.API) frontend.MergeableState {
+	return &commitChecker{api: api, forked: true}
+}
+
+// Merge implements [frontend.MergeableState]. The deferred checks are decided
+// at their position among the checks of the task.
+func (c *commitChecker) Merge(fork frontend.MergeableState, remap func(frontend.Variable) frontend.Variable) {
+	for _, v := range fork.(*commitChecker).collected {
+		if v.merge == nil {
+			c.Check(remap(v.v), v.bits)
+			continue
+		}
+		merge := v.merge
+		frontend.UpdateState(c.api, func(outer func(frontend.Variable) frontend.Variable) {
+			merge(func(v frontend.Variable) frontend.Variable { return outer(remap(v)) })
+		})
+	}
+}
+
+// Defer calls merge, which makes range checks with the checker of the
+// builder, when the range checks of the parallel task of checker are merged,
+// at its position among them. merge gets the mapping of the variables of the
+// task, and decides the checks which depend on the tasks defined before, as
+// the checks of the limbs of emulated elements. Outside of a parallel task,
+// merge is called immediately with the identity mapping.
+func Defer(checker frontend.Rangechecker, merge func(remap func(frontend.Variable) frontend.Variable)) {
+	c, ok := checker.(*commitChecker)
+	if !ok || !c.forked {
+		merge(func(v frontend.Variable) frontend.Variable { return v })
+		return
+	}
+	if c.closed {
+		panic("checker already closed")
+	}
+	frontend.UpdateState(c.api, func(func(frontend.Variable) frontend.Variable) {
+		c.collected = append(c.collected, checkedVariable{merge: merge})
+	})
 }
 
 func newCommitRangechecker(api frontend.API) *commitChecker {
@@ -41,7 +89,7 @@ func newCommitRangechecker(api frontend.API) *commitChecker {
 			panic("stored rangechecker is not valid")
 		}
 	}
-	cht := &commitChecker{}
+	cht := &commitChecker{api: api}
 	kv.SetKeyValue(ctxCheckerKey{}, cht)
 	api.Compiler().Defer(cht.commit)
 	return cht
@@ -51,7 +99,9 @@ func (c *commitChecker) Check(in frontend.Variable, bits int) {
 	if c.closed {
 		panic("checker already closed")
 	}
-	c.collected = append(c.collected, checkedVariable{v: in, bits: bits})
+	frontend.UpdateState(c.api, func(remap func(frontend.Variable) frontend.Variable) {
+		c.collected = append(c.collected, checkedVariable{v: remap(in), bits: bits})
+	})
 }
 
 func (c *commitChecker) buildTable(nbTable int) []frontend.Variable {
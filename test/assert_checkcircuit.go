@@ -1,6 +1,8 @@
 package test
 
 import (
+	"crypto/sha256"
+
 	"github.com/consensys/gnark-crypto/ecc"
 	"github.com/consensys/gnark/backend"
 	"github.com/consensys/gnark/backend/groth16"
@@ -26,7 +28,7 @@ import (
 //   - the circuit can be solved with the constraint system solver
 //   - the circuit can be solved with the prover
 //   - the circuit can be verified with the verifier
-//   - the circuit can be verified with gnark-solidity-checker
+//   - the circuit can be verified with gnark-solidity-checker (BN254), or solc and evm (BLS12-381 Groth16)
 //   - the circuit, witness, proving and verifying keys can be serialized and deserialized
 func (assert *Assert) CheckCircuit(circuit frontend.Circuit, opts ...TestingOption) {
 	// get the testing configuration
@@ -122,7 +124,7 @@ func (assert *Assert) CheckCircuit(circuit frontend.Circuit, opts ...TestingOpti
 					for _, w := range validWitnesses {
 						w := w
 						assert.Run(func(assert *Assert) {
-							checkSolidity := opt.checkSolidity && curve == ecc.BN254
+							checkSolidity := opt.checkSolidity && (curve == ecc.BN254 || (curve == ecc.BLS12_381 && b == backend.GROTH16))
 							proof, err := concreteBackend.prove(ccs, pk, w.full, opt.proverOpts...)
 							assert.noError(err, &w)
 
@@ -133,7 +135,14 @@ func (assert *Assert) CheckCircuit(circuit frontend.Circuit, opts ...TestingOpti
 								// check that the proof can be verified by gnark-solidity-checker
 								if _vk, ok := vk.(verifyingKey); ok {
 									assert.Run(func(assert *Assert) {
-										assert.solidityVerification(b, _vk, proof, w.public)
+										solidityProof := proof
+										if curve == ecc.BLS12_381 {
+											// the BLS12-381 contract hashes the commitments with SHA256.
+											sha256Proof, err := concreteBackend.prove(ccs, pk, w.full, append(opt.proverOpts, backend.WithProverHashToFieldFunction(sha256.New()))...)
+											assert.noError(err, &w)
+											solidityProof = sha256Proof
+										}
+										assert.solidityVerification(b, _vk, solidityProof, w.public)
 									}, "solidity")
 								}
 							}
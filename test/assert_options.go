@@ -129,7 +129,8 @@ func NoTestEngine() TestingOption {
 // even when the build tags "solccheck" and "release_checks" are set.
 //
 // When the tags are set; this requires gnark-solidity-checker to be installed, which in turns
-// requires solc and abigen to be reachable in the PATH.
+// requires solc and abigen to be reachable in the PATH. BLS12-381 Groth16 verifiers
+// are executed directly and require solc and the evm tool of go-ethereum.
 //
 // See https://github.com/ConsenSys/gnark-solidity-checker for more details.
 func NoSolidityChecks() TestingOption {
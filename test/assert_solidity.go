@@ -3,16 +3,21 @@ package test
 import (
 	"bytes"
 	"encoding/hex"
+	"fmt"
 	"io"
 	"os"
 	"os/exec"
 	"path/filepath"
 	"strconv"
+	"strings"
 
+	fr_bls12381 "github.com/consensys/gnark-crypto/ecc/bls12-381/fr"
 	"github.com/consensys/gnark/backend"
+	groth16_bls12381 "github.com/consensys/gnark/backend/groth16/bls12-381"
 	groth16_bn254 "github.com/consensys/gnark/backend/groth16/bn254"
 	plonk_bn254 "github.com/consensys/gnark/backend/plonk/bn254"
 	"github.com/consensys/gnark/backend/witness"
+	"golang.org/x/crypto/sha3"
 )
 
 type verifyingKey interface {
@@ -22,7 +27,9 @@ type verifyingKey interface {
 
 // solidityVerification checks that the exported solidity contract can verify the proof
 // and that the proof is valid.
-// It uses gnark-solidity-checker see test.WithSolidity option.
+// It uses gnark-solidity-checker see test.WithSolidity option. BLS12-381
+// Groth16 proofs are not handled by gnark-solidity-checker; their verifier is
+// compiled with solc and executed with the evm tool of go-ethereum instead.
 func (assert *Assert) solidityVerification(b backend.ID, vk verifyingKey,
 	proof any,
 	validPublicWitness witness.Witness) {
@@ -31,6 +38,11 @@ func (assert *Assert) solidityVerification(b backend.ID, vk verifyingKey,
 	}
 	assert.t.Helper()
 
+	if _proof, ok := proof.(*groth16_bls12381.Proof); ok {
+		assert.solidityExecution(vk, _proof, validPublicWitness)
+		return
+	}
+
 	// make temp dir
 	tmpDir, err := os.MkdirTemp("", "gnark-solidity-check*")
 	assert.NoError(err)
@@ -98,3 +110,125 @@ func (assert *Assert) solidityVerification(b backend.ID, vk verifyingKey,
 	out, err = cmd.CombinedOutput()
 	assert.NoError(err, string(out))
 }
+
+// pragueGenesis enables the EIP-2537 precompiles in the evm tool.
+const pragueGenesis = `{
+	"config": {
+		"chainId": 1,
+		"homesteadBlock": 0,
+		"eip150Block": 0,
+		"eip155Block": 0,
+		"eip158Block": 0,
+		"byzantiumBlock": 0,
+		"constantinopleBlock": 0,
+		"petersburgBlock": 0,
+		"istanbulBlock": 0,
+		"berlinBlock": 0,
+		"londonBlock": 0,
+		"mergeNetsplitBlock": 0,
+		"terminalTotalDifficulty": 0,
+		"shanghaiTime": 0,
+		"cancunTime": 0,
+		"pragueTime": 0,
+		"blobSchedule": {
+			"cancun": {"target": 3, "max": 6, "baseFeeUpdateFraction": 3338477},
+			"prague": {"target": 6, "max": 9, "baseFeeUpdateFraction": 5007716}
+		}
+	},
+	"alloc": {},
+	"gasLimit": "0x1c9c380",
+	"difficulty": "0x0"
+}`
+
+// solidityExecution compiles the exported BLS12-381 Groth16 contract with solc
+// and runs verifyProof with the evm tool, both of which must be in the PATH.
+// The call must succeed with the valid public witness and revert when the
+// first public input, if any, is modified.
+func (assert *Assert) solidityExecution(vk verifyingKey, proof *groth16_bls12381.Proof, validPublicWitness witness.Witness) {
+	assert.t.Helper()
+
+	tmpDir, err := os.MkdirTemp("", "gnark-solidity-check*")
+	assert.NoError(err)
+	defer os.RemoveAll(tmpDir)
+
+	var buf bytes.Buffer
+	assert.NoError(vk.ExportSolidity(&buf))
+	assert.NoError(os.WriteFile(filepath.Join(tmpDir, "gnark_verifier.sol"), buf.Bytes(), 0600))
+
+	cmd := exec.Command("solc", "--optimize", "--bin-runtime", "-o", tmpDir, "gnark_verifier.sol")
+	cmd.Dir = tmpDir
+	assert.t.Log("running ", cmd.String())
+	out, err := cmd.CombinedOutput()
+	assert.NoError(err, string(out))
+
+	runtime, err := os.ReadFile(filepath.Join(tmpDir, "Verifier.bin-runtime"))
+	assert.NoError(err)
+
+	publicInputs := validPublicWitness.Vector().(fr_bls12381.Vector)
+	withCommitment := len(proof.Commitments) > 0
+	proofBytes := proof.MarshalSolidity()
+
+	input := solidityCalldata(withCommitment, proofBytes, publicInputs)
+	assert.NoError(runEvm(tmpDir, strings.TrimSpace(string(runtime)), input))
+
+	if len(publicInputs) == 0 {
+		// no public input to modify
+		return
+	}
+	wrong := make(fr_bls12381.Vector, len(publicInputs))
+	copy(wrong, publicInputs)
+	var one fr_bls12381.Element
+	one.SetOne()
+	wrong[0].Add(&wrong[0], &one)
+	input = solidityCalldata(withCommitment, proofBytes, wrong)
+	assert.Error(runEvm(tmpDir, strings.TrimSpace(string(runtime)), input), "verifier accepted a wrong public input")
+}
+
+// solidityCalldata returns the ABI encoding of a call to verifyProof of the
+// BLS12-381 Groth16 contract. All the arguments are static arrays, so they are
+// simply concatenated after the selector.
+func solidityCalldata(withCommitment bool, proof []byte, publicInputs fr_bls12381.Vector) []byte {
+	signature := "verifyProof(uint256[16],"
+	if withCommitment {
+		signature += "uint256[4],uint256[4],"
+	}
+	signature += fmt.Sprintf("uint256[%d])", len(publicInputs))
+
+	h := sha3.NewLegacyKeccak256()
+	h.Write([]byte(signature))
+	res := h.Sum(nil)[:4]
+	res = append(res, proof...)
+	for i := range publicInputs {
+		b := publicInputs[i].Bytes()
+		res = append(res, b[:]...)
+	}
+	return res
+}
+
+// runEvm executes the runtime code on input and returns an error if the call
+// didn't succeed. The evm tool prints the data returned by the call, followed
+// by the error of the call if it failed. verifyProof returns nothing and
+// reverts with an error selector, so the call succeeded only if the output is
+// the empty return data.
+func runEvm(dir, runtime string, input []byte) error {
+	genesis := filepath.Join(dir, "genesis.json")
+	if err := os.WriteFile(genesis, []byte(pragueGenesis), 0600); err != nil {
+		return err
+	}
+	cmd := exec.Command("evm",
+		"--prestate", genesis,
+		"--code", runtime,
+		"--input", hex.EncodeToString(input),
+		"--gas", "10000000",
+		"run")
+	var stderr bytes.Buffer
+	cmd.Stderr = &stderr
+	out, err := cmd.Output()
+	if err != nil {
+		return fmt.Errorf("%w: %s", err, stderr.Bytes())
+	}
+	if res := strings.TrimSpace(string(out)); res != "0x" {
+		return fmt.Errorf("execution failed: %s", res)
+	}
+	return nil
+}